@@ -65,11 +65,14 @@ func (a *AIBullshitAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*m
 				strings.Contains(text, "claude") ||
 				strings.Contains(text, "bard") {
 				pos := fset.Position(comment.Pos())
+				end := fset.Position(comment.End())
 				issues = append(
 					issues, &models.Issue{
 						File:       pos.Filename,
 						Line:       pos.Line,
 						Column:     pos.Column,
+						EndLine:    end.Line,
+						EndColumn:  end.Column,
 						Position:   pos,
 						Type:       models.IssueAIGeneratedComment,
 						Severity:   models.SeverityLevelLow,
@@ -109,6 +112,7 @@ func (a *AIBullshitAnalyzer) checkOverEngineering(fn *ast.FuncDecl, fset *token.
 	}
 
 	pos := fset.Position(fn.Pos())
+	end := fset.Position(fn.Name.End())
 	funcName := fn.Name.Name
 
 	// Skip binding/serialization code where complex patterns are expected
@@ -123,6 +127,8 @@ func (a *AIBullshitAnalyzer) checkOverEngineering(fn *ast.FuncDecl, fset *token.
 				File:       pos.Filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueAIOverengineeredSimple,
 				Severity:   models.SeverityLevelHigh,
@@ -230,6 +236,7 @@ func (a *AIBullshitAnalyzer) checkUnnecessaryComplexity(fn *ast.FuncDecl, fset *
 	}
 
 	pos := fset.Position(fn.Pos())
+	end := fset.Position(fn.Name.End())
 	cyclomaticComplexity := a.calculateCyclomaticComplexity(fn)
 	nestingDepth := a.calculateNestingDepth(fn)
 
@@ -237,12 +244,14 @@ func (a *AIBullshitAnalyzer) checkUnnecessaryComplexity(fn *ast.FuncDecl, fset *
 	if cyclomaticComplexity > a.cyclomaticComplexityThreshold {
 		issues = append(
 			issues, &models.Issue{
-				File:     pos.Filename,
-				Line:     pos.Line,
-				Column:   pos.Column,
-				Position: pos,
-				Type:     models.IssueAIUnnecessaryComplexity,
-				Severity: models.SeverityLevelHigh,
+				File:      pos.Filename,
+				Line:      pos.Line,
+				Column:    pos.Column,
+				EndLine:   end.Line,
+				EndColumn: end.Column,
+				Position:  pos,
+				Type:      models.IssueAIUnnecessaryComplexity,
+				Severity:  models.SeverityLevelHigh,
 				Message: fmt.Sprintf(
 					"Function has cyclomatic complexity of %d (threshold: %d)", cyclomaticComplexity, a.cyclomaticComplexityThreshold,
 				),
@@ -262,12 +271,16 @@ BETTER: Split into focused, single-purpose functions`, cyclomaticComplexity,
 		)
 	}
 
+	// Deep nesting alone hurts readability less than many branches, so it is
+	// reported one level below the rule's severity
 	if nestingDepth > a.nestingDepthThreshold {
 		issues = append(
 			issues, &models.Issue{
 				File:       pos.Filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueAIUnnecessaryComplexity,
 				Severity:   models.SeverityLevelMedium,
@@ -345,11 +358,14 @@ func (a *AIBullshitAnalyzer) checkInterfaceAbuse(iface *ast.InterfaceType, fset
 	// Check for single-method interfaces (potential over-abstraction)
 	if iface.Methods != nil && len(iface.Methods.List) == 1 {
 		pos := fset.Position(iface.Pos())
+		end := fset.Position(iface.End())
 		issues = append(
 			issues, &models.Issue{
 				File:       pos.Filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueAIOverAbstraction,
 				Severity:   models.SeverityLevelLow,
@@ -393,11 +409,14 @@ func (a *AIBullshitAnalyzer) checkUnnecessaryGoroutine(fn *ast.FuncDecl, fset *t
 	// If function is simple and uses goroutine+channel for sync work
 	if hasGoroutine && hasChannelOp && stmtCount < a.maxStatementsForGoroutine {
 		pos := fset.Position(fn.Pos())
+		end := fset.Position(fn.Name.End())
 		issues = append(
 			issues, &models.Issue{
 				File:       pos.Filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueAIGoroutineOverkill,
 				Severity:   models.SeverityLevelMedium,
@@ -453,11 +472,14 @@ func (a *AIBullshitAnalyzer) checkGenericInterfaceNames(decl *ast.GenDecl, fset
 		// Check if interface has generic name
 		if genericNames[typeSpec.Name.Name] {
 			pos := fset.Position(typeSpec.Pos())
+			end := fset.Position(typeSpec.Name.End())
 			issues = append(
 				issues, &models.Issue{
 					File:       pos.Filename,
 					Line:       pos.Line,
 					Column:     pos.Column,
+					EndLine:    end.Line,
+					EndColumn:  end.Column,
 					Position:   pos,
 					Type:       models.IssueAIUnnecessaryInterface,
 					Severity:   models.SeverityLevelLow,
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"encoding/json"
 	"go/ast"
 	"go/token"
 	"hash/fnv"
@@ -22,8 +23,14 @@ type AnalysisCache struct {
 	mu      sync.RWMutex
 	results map[string]CacheEntry
 	maxAge  time.Duration
+	// cleanAt is the number of entries at which expired ones are dropped next;
+	// it doubles with the live entries so that cleaning is amortized O(1)
+	cleanAt int
 }
 
+// minCacheClean is the fewest entries the legacy cache is cleaned at
+const minCacheClean = 64
+
 type CacheEntry struct {
 	Hash      string
 	Issues    []*models.Issue
@@ -31,24 +38,29 @@ type CacheEntry struct {
 }
 
 var (
-	// Global hybrid cache instance
+	// Global hybrid cache instance, set up by EnablePersistentCache
 	globalHybridCache *cache.HybridCache
 
 	// Legacy cache for compatibility
 	globalCache = &AnalysisCache{
 		results: make(map[string]CacheEntry, 100),
 		maxAge:  15 * time.Minute,
+		cleanAt: minCacheClean,
 	}
 )
 
-func init() {
-	// Initialize hybrid cache with 1000 items in memory
-	var err error
-	globalHybridCache, err = cache.NewHybridCache("", 1000)
+// EnablePersistentCache keeps analysis results in the cache directory under
+// baseDir (the working directory when empty) so they survive between runs.
+// Without it results are only cached in memory. It must be called before any
+// analysis starts.
+func EnablePersistentCache(baseDir string) error {
+	// Keep 1000 items in memory
+	hc, err := cache.NewHybridCache(baseDir, 1000)
 	if err != nil {
-		// Fallback to simple cache if hybrid cache fails
-		globalHybridCache = nil
+		return err
 	}
+	globalHybridCache = hc
+	return nil
 }
 
 // AnalyzeAll is the main entry point for code analysis with caching support
@@ -56,10 +68,20 @@ func AnalyzeAll(filename string, file *ast.File, fset *token.FileSet) []*models.
 	return Analyze(filename, file, fset, nil)
 }
 
+// sharedWalkName is the name the traversal shared by node analyzers is
+// profiled under
+const sharedWalkName = "walk"
+
 // Analyze is the main entry point with configuration support
 func Analyze(
 	filename string, file *ast.File, fset *token.FileSet, enabledAnalyzers map[string]bool,
 ) []*models.Issue {
+	return AnalyzeWithOptions(filename, file, fset, Options{Analyzers: enabledAnalyzers})
+}
+
+// AnalyzeWithOptions analyzes file, parsed into fset from filename, as opts
+// configure
+func AnalyzeWithOptions(filename string, file *ast.File, fset *token.FileSet, opts Options) []*models.Issue {
 	// Check for nil input
 	if file == nil {
 		return []*models.Issue{}
@@ -71,92 +93,118 @@ func Analyze(
 	// }
 
 	// Check cache first
-	if cachedIssues, ok := checkCache(filename, file); ok {
-		return cachedIssues
+	key := cacheKey(file, &opts)
+	if key != "" {
+		if cachedIssues, ok := checkCache(filename, key); ok {
+			return cachedIssues
+		}
 	}
 
 	issues := make([]*models.Issue, 0, 32)
 
-	// Build list of analyzers to run based on configuration
-	type analyzerEntry struct {
-		name string
-		fn   func() Analyzer
-	}
-
-	allAnalyzers := []analyzerEntry{
-		// Performance analyzers (unique to this tool)
-		{"loop", NewLoopAnalyzer},
-		{"deferoptimization", NewDeferOptimizationAnalyzer},
-		{"slice", NewSliceAnalyzer},
-		{"map", NewMapAnalyzer},
-		{"reflection", NewReflectionAnalyzer},
-		{"interface", NewInterfaceAnalyzer},
-		{"regex", NewRegexAnalyzer},
-		{"time", NewTimeAnalyzer},
-		{"memoryleak", NewMemoryLeakAnalyzer},
-		{"database", NewDatabaseAnalyzer},
-
-		// Specialized analyzers (not covered by standard linters)
-		{"apimisuse", NewAPIMisuseAnalyzer},
-		{"aibullshit", NewAIBullshitAnalyzer},
-		{"goroutine", NewGoroutineAnalyzer},
-		{"channel", NewChannelAnalyzer},
-		{"httpclient", NewHTTPClientAnalyzer},
-		{"context", NewContextAnalyzer},
-		{"racecondition", NewRaceConditionAnalyzer},
-		{"concurrencypatterns", NewConcurrencyPatternsAnalyzer},
-		{"networkpatterns", NewNetworkPatternsAnalyzer},
-		{"cpuoptimization", NewCPUOptimizationAnalyzer},
-		{"gcpressure", NewGCPressureAnalyzer},
-		{"syncpool", NewSyncPoolAnalyzer},
-
-		// New performance analyzers
-		{"cgo", NewCGOAnalyzer},
-		{"serialization", NewSerializationAnalyzer},
-		{"crypto", NewCryptoAnalyzer},
-		{"httpreuse", NewHTTPReuseAnalyzer},
-		{"iobuffer", NewIOBufferAnalyzer},
-
-		// Security/privacy (specialized)
-		{"privacy", NewPrivacyAnalyzer},
-
-		// Struct layout optimization
-		{"structlayout", NewStructLayoutAnalyzer},
-
-		// CPU cache optimization
-		{"cpucache", NewCPUCacheAnalyzer},
-
-		// Testing (usually noisy, disabled by default in config)
-		{"testcoverage", NewTestCoverageAnalyzer},
-	}
-
-	// Only create and run enabled analyzers
-	analyzersRun := 0
-	for _, entry := range allAnalyzers {
+	// Built-in analyzers followed by those added with Register
+	allAnalyzers := Registered()
+
+	// Only create and run enabled analyzers. Node analyzers subscribe to one
+	// shared traversal of the file; the others walk it themselves.
+	s := &session{opts: opts}
+	profile := s.profile()
+	ctx := s.context()
+	walker := NewWalker(fset, file)
+	walker.session = s
+	results := make([][]*models.Issue, len(allAnalyzers))
+	subscribed := make(map[int]func() []*models.Issue)
+	for i, entry := range allAnalyzers {
 		// If no config provided, run all analyzers
-		if enabledAnalyzers == nil || enabledAnalyzers[entry.name] {
-			analyzer := entry.fn()
-			analyzerIssues := analyzer.Analyze(file, fset)
-			if len(analyzerIssues) > 0 {
-				analyzersRun++
-			}
-			issues = append(issues, analyzerIssues...)
+		if opts.Analyzers != nil && !opts.Analyzers[entry.Name] {
+			continue
+		}
+		// Partial results of an abandoned analysis are neither used nor cached
+		if ctx.Err() != nil {
+			return []*models.Issue{}
+		}
+		analyzer := entry.New()
+		if sa, ok := analyzer.(sessionAnalyzer); ok {
+			sa.useSession(s)
+		}
+		if na, ok := analyzer.(NodeAnalyzer); ok {
+			subscribed[i] = walker.subscribe(entry.Name, na)
+			continue
+		}
+		profile.measure(entry.Name, func() int {
+			results[i] = analyzer.Analyze(file, fset)
+			return len(results[i])
+		})
+	}
+	if len(subscribed) > 0 {
+		if ctx.Err() != nil {
+			return []*models.Issue{}
+		}
+		if profile != nil {
+			walker.timeHandlers()
 		}
+		profile.measure(sharedWalkName, func() int {
+			walker.Walk()
+			return 0
+		})
+		for i, collect := range subscribed {
+			profile.measure(allAnalyzers[i].Name, func() int {
+				results[i] = collect()
+				return len(results[i])
+			})
+		}
+		profile.moveTime(sharedWalkName, walker.handlerTimes())
+	}
+	if ctx.Err() != nil {
+		return []*models.Issue{}
 	}
+	for _, analyzerIssues := range results {
+		issues = append(issues, analyzerIssues...)
+	}
+
+	attachSourceCode(filename, opts.Overlay, issues)
 
 	// Update cache with results
-	updateCache(filename, file, issues)
+	if key != "" {
+		updateCache(filename, key, issues, !opts.NoMemoryCache)
+	}
 
 	return issues
 }
 
-func checkCache(filename string, file *ast.File) ([]*models.Issue, bool) {
-	hash := computeFileHash(file)
+// cacheKey returns the key the results of file are cached under, or "" when
+// they are not cached. Besides the syntax tree the key covers the
+// configuration the results depend on: the analyzers run, the thresholds and
+// the custom rules. Results found with a profile, the compiler's escape
+// decisions or overlaid sources are not cached, since the profile has to
+// measure the analyzers and the others are not part of the key.
+func cacheKey(file *ast.File, opts *Options) string {
+	if opts.Profile != nil || opts.Overlay != nil || opts.Escapes != nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	for _, entry := range Registered() {
+		if opts.Analyzers == nil || opts.Analyzers[entry.Name] {
+			h.Write([]byte(entry.Name + "\x00"))
+		}
+	}
+	h.Write(strconv.AppendInt(nil, int64(opts.Thresholds.withDefaults().MaxLoopDepth), 10))
+	if rules := opts.CustomRules; len(rules) > 0 {
+		data, err := json.Marshal(rules)
+		if err != nil {
+			return ""
+		}
+		h.Write(data)
+	}
+	return computeFileHash(file) + "." + strconv.FormatUint(h.Sum64(), 36)
+}
 
+func checkCache(filename, key string) ([]*models.Issue, bool) {
 	// Try hybrid cache first
 	if globalHybridCache != nil {
 		if entry, ok := globalHybridCache.Get(filename); ok {
-			if entry.Hash == hash {
+			if entry.Hash == key {
 				return cloneIssues(entry.Issues), true
 			}
 		}
@@ -168,7 +216,7 @@ func checkCache(filename string, file *ast.File) ([]*models.Issue, bool) {
 	defer globalCache.mu.RUnlock()
 
 	if entry, ok := globalCache.results[filename]; ok {
-		if entry.Hash == hash && time.Since(entry.Timestamp) < globalCache.maxAge {
+		if entry.Hash == key && time.Since(entry.Timestamp) < globalCache.maxAge {
 			return entry.Issues, true
 		}
 	}
@@ -176,17 +224,21 @@ func checkCache(filename string, file *ast.File) ([]*models.Issue, bool) {
 	return nil, false
 }
 
-func updateCache(filename string, file *ast.File, issues []*models.Issue) {
-	hash := computeFileHash(file)
-
+// updateCache stores issues under key in the hybrid cache when it is enabled
+// and otherwise, if memory is set, in the legacy in-memory cache
+func updateCache(filename, key string, issues []*models.Issue, memory bool) {
 	// Use hybrid cache if available
 	if globalHybridCache != nil {
 		globalHybridCache.Put(
 			filename, cache.Entry{
-				Hash:   hash,
+				Hash:   key,
 				Issues: cloneIssues(issues),
 			},
 		)
+		return
+	}
+	if !memory {
+		return
 	}
 
 	// Fallback to legacy cache
@@ -194,13 +246,34 @@ func updateCache(filename string, file *ast.File, issues []*models.Issue) {
 	defer globalCache.mu.Unlock()
 
 	globalCache.results[filename] = CacheEntry{
-		Hash:      hash,
+		Hash:      key,
 		Issues:    cloneIssues(issues),
 		Timestamp: time.Now(),
 	}
 
 	// Clean old entries
-	cleanCache()
+	if len(globalCache.results) >= globalCache.cleanAt {
+		cleanCache()
+		globalCache.cleanAt = max(minCacheClean, 2*len(globalCache.results))
+	}
+}
+
+// InvalidateCache drops the cached results of filenames so their next analysis
+// starts afresh. Results are keyed by the file's syntax tree, so a file whose
+// dependencies changed would otherwise get stale type-aware results.
+func InvalidateCache(filenames ...string) {
+	for _, filename := range filenames {
+		if globalHybridCache != nil {
+			// An entry without a hash never matches
+			globalHybridCache.Put(filename, cache.Entry{})
+		}
+	}
+
+	globalCache.mu.Lock()
+	defer globalCache.mu.Unlock()
+	for _, filename := range filenames {
+		delete(globalCache.results, filename)
+	}
 }
 
 func cloneIssues(src []*models.Issue) []*models.Issue {
@@ -280,104 +353,16 @@ func WalkWithContext(node ast.Node, fn func(n ast.Node, ctx *AnalysisContext) bo
 		TypeDecls: make(map[string]*ast.TypeSpec, 20),
 	}
 
-	walkWithContext(node, ctx, fn)
-}
-
-func walkWithContext(node ast.Node, ctx *AnalysisContext, fn func(n ast.Node, ctx *AnalysisContext) bool) {
-	if node == nil {
-		return
-	}
-
-	// Call the visitor function first
-	if !fn(node, ctx) {
-		return
-	}
-
-	// Dispatch to specific walker based on node type
-	walkNode(node, ctx, fn)
-}
-
-func walkNode(node ast.Node, ctx *AnalysisContext, fn func(n ast.Node, ctx *AnalysisContext) bool) {
-	// Delegate to specialized handlers based on node category
-	switch node := node.(type) {
-	// Loop statements
-	case *ast.ForStmt, *ast.RangeStmt:
-		walkLoopNode(node, ctx, fn)
-	// Declaration nodes
-	case *ast.FuncDecl, *ast.GenDecl, *ast.ValueSpec:
-		walkDeclNode(node, ctx, fn)
-	// Statement nodes
-	case *ast.BlockStmt, *ast.IfStmt, *ast.AssignStmt, *ast.ReturnStmt:
-		walkStmtNode(node, ctx, fn)
-	// Expression nodes
-	case *ast.CallExpr, *ast.BinaryExpr, *ast.UnaryExpr, *ast.ParenExpr,
-		*ast.SelectorExpr, *ast.IndexExpr, *ast.SliceExpr, *ast.ExprStmt:
-		walkExprNode(node, ctx, fn)
-	// Special case for file
-	case *ast.File:
-		walkFile(node, ctx, fn)
-	}
-}
-
-func walkLoopNode(node ast.Node, ctx *AnalysisContext, fn func(n ast.Node, ctx *AnalysisContext) bool) {
-	switch n := node.(type) {
-	case *ast.ForStmt:
-		walkForStmt(n, ctx, fn)
-	case *ast.RangeStmt:
-		walkRangeStmt(n, ctx, fn)
-	}
-}
-
-func walkDeclNode(node ast.Node, ctx *AnalysisContext, fn func(n ast.Node, ctx *AnalysisContext) bool) {
-	switch n := node.(type) {
-	case *ast.FuncDecl:
-		walkFuncDecl(n, ctx, fn)
-	case *ast.GenDecl:
-		walkGenDecl(n, ctx, fn)
-	case *ast.ValueSpec:
-		walkValueSpec(n, ctx, fn)
-	}
-}
-
-func walkStmtNode(node ast.Node, ctx *AnalysisContext, fn func(n ast.Node, ctx *AnalysisContext) bool) {
-	switch n := node.(type) {
-	case *ast.BlockStmt:
-		walkBlockStmt(n, ctx, fn)
-	case *ast.IfStmt:
-		walkIfStmt(n, ctx, fn)
-	case *ast.AssignStmt:
-		walkAssignStmt(n, ctx, fn)
-	case *ast.ReturnStmt:
-		walkReturnStmt(n, ctx, fn)
-	}
-}
-
-func walkExprNode(node ast.Node, ctx *AnalysisContext, fn func(n ast.Node, ctx *AnalysisContext) bool) {
-	switch n := node.(type) {
-	case *ast.CallExpr:
-		walkCallExpr(n, ctx, fn)
-	case *ast.BinaryExpr:
-		walkBinaryExpr(n, ctx, fn)
-	case *ast.UnaryExpr, *ast.ParenExpr, *ast.SelectorExpr:
-		walkExpr(node, ctx, fn)
-	case *ast.IndexExpr:
-		walkIndexExpr(n, ctx, fn)
-	case *ast.SliceExpr:
-		walkSliceExpr(n, ctx, fn)
-	case *ast.ExprStmt:
-		walkSimpleExpr(n.X, ctx, fn)
-	}
-}
-
-func walkExpr(node ast.Node, ctx *AnalysisContext, fn func(n ast.Node, ctx *AnalysisContext) bool) {
-	switch n := node.(type) {
-	case *ast.UnaryExpr:
-		walkSimpleExpr(n.X, ctx, fn)
-	case *ast.ParenExpr:
-		walkSimpleExpr(n.X, ctx, fn)
-	case *ast.SelectorExpr:
-		walkSimpleExpr(n.X, ctx, fn)
-	}
+	walkTree(nil, node, func(n ast.Node, wc *WalkContext) bool {
+		ctx.LoopDepth = wc.LoopDepth()
+		ctx.InLoop = wc.InLoop()
+		ctx.CurrentFunc = ""
+		if wc.FuncDecl != nil {
+			ctx.CurrentFunc = wc.FuncDecl.Name.Name
+		}
+		ctx.NodeCount++
+		return fn(n, ctx)
+	})
 }
 
 // AnalysisContext provides shared context between analyzers
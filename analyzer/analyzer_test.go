@@ -111,6 +111,59 @@ func main() {
 	}
 }
 
+func TestAnalyzeIssueRanges(t *testing.T) {
+	code := `package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"unsafe"
+)
+
+var counter int
+
+func main() {
+	for _, name := range os.Args {
+		f, _ := os.Open(name)
+		defer f.Close()
+		re := regexp.MustCompile("a+")
+		_ = re
+		_ = fmt.Sprintf("%s", name)
+	}
+	go func() {
+		counter++
+	}()
+	_ = unsafe.Sizeof(counter)
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "ranges.go", code, parser.ParseComments)
+	require.NoError(t, err)
+
+	issues := Analyze("ranges.go", file, fset, nil)
+	require.NotEmpty(t, issues)
+
+	for _, issue := range issues {
+		require.NotZero(t, issue.EndLine, "%s has no end position", issue.Type)
+		if issue.EndLine == issue.Line {
+			require.Greater(t, issue.EndColumn, issue.Column, "%s has an empty range", issue.Type)
+		} else {
+			require.Greater(t, issue.EndLine, issue.Line, "%s ends before it starts", issue.Type)
+		}
+	}
+
+	for _, issue := range issues {
+		if issue.Type == models.IssueDeferInLoop {
+			require.Equal(t, 15, issue.Line)
+			require.Equal(t, 15, issue.EndLine)
+			require.Equal(t, len("\t\tdefer f.Close()")+1, issue.EndColumn)
+			return
+		}
+	}
+	t.Fatal("expected a DeferInLoop issue")
+}
+
 func TestAnalyzeDependencies(t *testing.T) {
 	// Ensure the AnalyzeDependencies helper does not panic on missing paths
 	assert.NotPanics(t, func() {
@@ -582,3 +635,53 @@ func (s *S) Method() {}`,
 		},
 	)
 }
+
+func TestAnalyzeCacheKeyCoversConfig(t *testing.T) {
+	code := `package main
+
+func grid(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				s += i * j * k
+			}
+		}
+	}
+	return s
+}
+`
+	const filename = "cache_config.go"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, code, parser.ParseComments)
+	require.NoError(t, err)
+	defer InvalidateCache(filename)
+
+	nested := func(opts Options) int {
+		n := 0
+		for _, issue := range AnalyzeWithOptions(filename, file, fset, opts) {
+			if issue.Type == models.IssueNestedLoop {
+				n++
+			}
+		}
+		return n
+	}
+
+	loop := map[string]bool{"loop": true}
+	strict := Thresholds{MaxLoopDepth: 2}
+	require.Positive(t, nested(Options{Analyzers: loop, Thresholds: strict}))
+	require.Zero(t, nested(Options{Analyzers: map[string]bool{"slice": true}, Thresholds: strict}),
+		"results of other analyzers came from the cache")
+	require.Zero(t, nested(Options{Analyzers: loop}), "results for another MaxLoopDepth came from the cache")
+}
+
+func TestAnalyzeNoMemoryCache(t *testing.T) {
+	const filename = "cache_no_memory.go"
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, "package main\n", parser.ParseComments)
+	require.NoError(t, err)
+
+	AnalyzeWithOptions(filename, file, fset, Options{NoMemoryCache: true})
+	_, ok := checkCache(filename, cacheKey(file, &Options{}))
+	require.False(t, ok)
+}
@@ -3,13 +3,16 @@ package analyzer
 import (
 	"go/ast"
 	"go/token"
-	"strings"
+	"go/types"
+	"strconv"
 
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
 // APIMisuseAnalyzer focuses on high signal API pitfalls (waitgroup misuse, expensive calls in loops, etc.).
-type APIMisuseAnalyzer struct{}
+type APIMisuseAnalyzer struct {
+	inSession
+}
 
 func NewAPIMisuseAnalyzer() Analyzer {
 	return &APIMisuseAnalyzer{}
@@ -30,7 +33,7 @@ func (a *APIMisuseAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*mo
 		filename = fset.Position(file.Pos()).Filename
 	}
 
-	ctx := newAPIContext(fset, filename)
+	ctx := newAPIContext(fset, file, filename, a.session.overlay())
 	ast.Walk(&apiVisitor{ctx: ctx}, file)
 	return ctx.issues
 }
@@ -166,9 +169,15 @@ type apiState struct {
 
 type apiContext struct {
 	fset     *token.FileSet
+	file     *ast.File
 	filename string
+	overlay  Overlay
 	issues   []*models.Issue
 
+	// type information is loaded on demand for fixes that need it
+	info       *types.Info
+	infoLoaded bool
+
 	stateStack []apiState
 	state      apiState
 	funcDepth  int
@@ -176,10 +185,12 @@ type apiContext struct {
 	types *typeTable
 }
 
-func newAPIContext(fset *token.FileSet, filename string) *apiContext {
+func newAPIContext(fset *token.FileSet, file *ast.File, filename string, overlay Overlay) *apiContext {
 	ctx := &apiContext{
 		fset:       fset,
+		file:       file,
 		filename:   filename,
+		overlay:    overlay,
 		issues:     make([]*models.Issue, 0, 16),
 		stateStack: make([]apiState, 0, 8),
 		types:      newTypeTable(),
@@ -285,8 +296,7 @@ func (ctx *apiContext) detectMutexParams(fn *ast.FuncDecl) {
 			continue
 		}
 
-		pos := ctx.fset.Position(field.Pos())
-		issue := ctx.newIssue(pos, models.IssueMutexByValue, models.SeverityLevelHigh,
+		issue := ctx.newIssue(field, models.IssueMutexByValue, models.SeverityLevelHigh,
 			"sync.Mutex passed by value - copying a mutex breaks locking semantics",
 			"Accept *sync.Mutex or *sync.RWMutex instead of a value copy")
 
@@ -295,30 +305,28 @@ func (ctx *apiContext) detectMutexParams(fn *ast.FuncDecl) {
 }
 
 func (ctx *apiContext) handleCall(call *ast.CallExpr) {
-	pos := ctx.fset.Position(call.Pos())
-
-	if issue := ctx.detectWaitGroupAdd(call, pos); issue != nil {
+	if issue := ctx.detectWaitGroupAdd(call); issue != nil {
 		ctx.issues = append(ctx.issues, issue)
 	}
-	if issue := ctx.detectTimeMisuse(call, pos); issue != nil {
+	if issue := ctx.detectTimeMisuse(call); issue != nil {
 		ctx.issues = append(ctx.issues, issue)
 	}
-	if issue := ctx.detectFmtConcat(call, pos); issue != nil {
+	if issue := ctx.detectFmtConcat(call); issue != nil {
 		ctx.issues = append(ctx.issues, issue)
 	}
-	if issue := ctx.detectPprofMisuse(call, pos); issue != nil {
+	if issue := ctx.detectPprofMisuse(call); issue != nil {
 		ctx.issues = append(ctx.issues, issue)
 	}
-	if issue := ctx.detectRecoverMisuse(call, pos); issue != nil {
+	if issue := ctx.detectRecoverMisuse(call); issue != nil {
 		ctx.issues = append(ctx.issues, issue)
 	}
-	if issue := ctx.detectJSONMarshal(call, pos); issue != nil {
+	if issue := ctx.detectJSONMarshal(call); issue != nil {
 		ctx.issues = append(ctx.issues, issue)
 	}
-	ctx.issues = append(ctx.issues, ctx.detectRegexIssues(call, pos)...)
+	ctx.issues = append(ctx.issues, ctx.detectRegexIssues(call)...)
 }
 
-func (ctx *apiContext) detectWaitGroupAdd(call *ast.CallExpr, pos token.Position) *models.Issue {
+func (ctx *apiContext) detectWaitGroupAdd(call *ast.CallExpr) *models.Issue {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok || sel.Sel.Name != methodAdd {
 		return nil
@@ -337,12 +345,12 @@ func (ctx *apiContext) detectWaitGroupAdd(call *ast.CallExpr, pos token.Position
 		return nil
 	}
 
-	return ctx.newIssue(pos, models.IssueWaitgroupAddInGoroutine, models.SeverityLevelHigh,
+	return ctx.newIssue(call, models.IssueWaitgroupAddInGoroutine, models.SeverityLevelHigh,
 		"WaitGroup.Add called from goroutine - call Add before starting goroutines",
 		"Increment the WaitGroup counter before launching the goroutine")
 }
 
-func (ctx *apiContext) detectTimeMisuse(call *ast.CallExpr, pos token.Position) *models.Issue {
+func (ctx *apiContext) detectTimeMisuse(call *ast.CallExpr) *models.Issue {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return nil
@@ -359,11 +367,11 @@ func (ctx *apiContext) detectTimeMisuse(call *ast.CallExpr, pos token.Position)
 
 	switch sel.Sel.Name {
 	case "Sleep":
-		return ctx.newIssue(pos, models.IssueSleepInLoop, models.SeverityLevelMedium,
+		return ctx.newIssue(call, models.IssueSleepInLoop, models.SeverityLevelMedium,
 			"time.Sleep in loop blocks the entire iteration",
 			"Use a time.Ticker or rate limiter outside the loop")
 	case "Now":
-		return ctx.newIssue(pos, models.IssueTimeNowInLoop, models.SeverityLevelMedium,
+		return ctx.newIssue(call, models.IssueTimeNowInLoop, models.IssueTimeNowInLoop.Severity(),
 			"time.Now called in loop - repeated syscalls",
 			"Capture time once before the loop or reuse a ticker")
 	default:
@@ -371,7 +379,7 @@ func (ctx *apiContext) detectTimeMisuse(call *ast.CallExpr, pos token.Position)
 	}
 }
 
-func (ctx *apiContext) detectFmtConcat(call *ast.CallExpr, pos token.Position) *models.Issue {
+func (ctx *apiContext) detectFmtConcat(call *ast.CallExpr) *models.Issue {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return nil
@@ -380,20 +388,50 @@ func (ctx *apiContext) detectFmtConcat(call *ast.CallExpr, pos token.Position) *
 	if !ok || pkgIdent.Name != "fmt" || sel.Sel.Name != "Sprintf" {
 		return nil
 	}
-	if ctx.funcDepth == 0 || len(call.Args) != 2 {
+	if ctx.funcDepth == 0 || len(call.Args) < 2 {
 		return nil
 	}
 	lit, ok := call.Args[0].(*ast.BasicLit)
-	if !ok || !strings.EqualFold(strings.Trim(lit.Value, `"`), "%s%s") {
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+
+	var issue *models.Issue
+	switch {
+	case len(call.Args) == 3 && format == "%s%s":
+		issue = ctx.newIssue(call, models.IssueSprintfConcatenation, models.SeverityLevelLow,
+			"fmt.Sprintf used for simple concatenation",
+			"Use the + operator or strings.Builder for simple joins")
+	case len(call.Args) == 2 && simpleConversionVerbs[format]:
+		issue = ctx.newIssue(call, models.IssueSprintfConcatenation, models.SeverityLevelLow,
+			"fmt.Sprintf used for a simple conversion",
+			"Use strconv (e.g. strconv.Itoa) or the string value directly")
+	default:
 		return nil
 	}
 
-	return ctx.newIssue(pos, models.IssueSprintfConcatenation, models.SeverityLevelLow,
-		"fmt.Sprintf used for simple concatenation",
-		"Use the + operator or strings.Builder for simple joins")
+	issue.Fix = sprintfFix(ctx.fset, ctx.file, ctx.typeInfo(), call)
+	issue.CanBeFixed = issue.Fix != nil
+	return issue
 }
 
-func (ctx *apiContext) detectPprofMisuse(call *ast.CallExpr, pos token.Position) *models.Issue {
+// simpleConversionVerbs are Sprintf formats that only convert a single value
+var simpleConversionVerbs = map[string]bool{"%d": true, "%s": true, "%v": true, "%t": true, "%q": true}
+
+// typeInfo type-checks the file on first use
+func (ctx *apiContext) typeInfo() *types.Info {
+	if !ctx.infoLoaded {
+		ctx.infoLoaded = true
+		ctx.info, _ = loadTypes(ctx.fset, ctx.file, ctx.filename, ctx.overlay)
+	}
+	return ctx.info
+}
+
+func (ctx *apiContext) detectPprofMisuse(call *ast.CallExpr) *models.Issue {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok || sel.Sel.Name != "StartCPUProfile" {
 		return nil
@@ -406,12 +444,12 @@ func (ctx *apiContext) detectPprofMisuse(call *ast.CallExpr, pos token.Position)
 		return nil
 	}
 
-	return ctx.newIssue(pos, models.IssuePprofNilWriter, models.SeverityLevelHigh,
+	return ctx.newIssue(call, models.IssuePprofNilWriter, models.SeverityLevelHigh,
 		"pprof.StartCPUProfile called with nil writer",
 		"Pass an io.Writer (e.g. os.Create) instead of nil")
 }
 
-func (ctx *apiContext) detectRecoverMisuse(call *ast.CallExpr, pos token.Position) *models.Issue {
+func (ctx *apiContext) detectRecoverMisuse(call *ast.CallExpr) *models.Issue {
 	ident, ok := call.Fun.(*ast.Ident)
 	if !ok || ident.Name != funcRecover {
 		return nil
@@ -420,12 +458,12 @@ func (ctx *apiContext) detectRecoverMisuse(call *ast.CallExpr, pos token.Positio
 		return nil
 	}
 
-	return ctx.newIssue(pos, models.IssueRecoverWithoutDefer, models.SeverityLevelHigh,
+	return ctx.newIssue(call, models.IssueRecoverWithoutDefer, models.SeverityLevelHigh,
 		"recover must be called from within a deferred function",
 		"Wrap recover in a deferred closure")
 }
 
-func (ctx *apiContext) detectJSONMarshal(call *ast.CallExpr, pos token.Position) *models.Issue {
+func (ctx *apiContext) detectJSONMarshal(call *ast.CallExpr) *models.Issue {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return nil
@@ -443,12 +481,12 @@ func (ctx *apiContext) detectJSONMarshal(call *ast.CallExpr, pos token.Position)
 		return nil
 	}
 
-	return ctx.newIssue(pos, models.IssueJSONMarshalInLoop, models.SeverityLevelHigh,
+	return ctx.newIssue(call, models.IssueJSONMarshalInLoop, models.IssueJSONMarshalInLoop.Severity(),
 		"encoding/json marshaling in loop allocates every iteration",
 		"Move marshaling outside the loop or reuse an encoder")
 }
 
-func (ctx *apiContext) detectRegexIssues(call *ast.CallExpr, pos token.Position) []*models.Issue {
+func (ctx *apiContext) detectRegexIssues(call *ast.CallExpr) []*models.Issue {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return nil
@@ -463,11 +501,12 @@ func (ctx *apiContext) detectRegexIssues(call *ast.CallExpr, pos token.Position)
 	}
 
 	if ctx.state.inLoop {
-		return []*models.Issue{
-			ctx.newIssue(pos, models.IssueRegexCompileInLoop, models.SeverityLevelHigh,
-				"regexp compile in loop is extremely expensive",
-				"Compile the regexp once and reuse it"),
-		}
+		issue := ctx.newIssue(call, models.IssueRegexCompileInLoop, models.IssueRegexCompileInLoop.Severity(),
+			"regexp compile in loop is extremely expensive",
+			"Compile the regexp once and reuse it")
+		issue.Fix = hoistRegexpFix(ctx.fset, ctx.file, ctx.typeInfo(), call)
+		issue.CanBeFixed = issue.Fix != nil
+		return []*models.Issue{issue}
 	}
 
 	if ctx.funcDepth == 0 {
@@ -475,17 +514,21 @@ func (ctx *apiContext) detectRegexIssues(call *ast.CallExpr, pos token.Position)
 	}
 
 	return []*models.Issue{
-		ctx.newIssue(pos, models.IssueRegexCompileInFunc, models.SeverityLevelMedium,
+		ctx.newIssue(call, models.IssueRegexCompileInFunc, models.SeverityLevelMedium,
 			"regexp compiled inside function - runs on every call",
 			"Move regexp.MustCompile to package scope or cache it"),
 	}
 }
 
-func (ctx *apiContext) newIssue(pos token.Position, issueType models.IssueType, severity models.SeverityLevel, message, suggestion string) *models.Issue {
+func (ctx *apiContext) newIssue(node ast.Node, issueType models.IssueType, severity models.SeverityLevel, message, suggestion string) *models.Issue {
+	pos := ctx.fset.Position(node.Pos())
+	end := ctx.fset.Position(node.End())
 	return &models.Issue{
 		File:       ctx.filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       issueType,
 		Severity:   severity,
@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// BoundsCheckAnalyzer reports the bounds checks the compiler keeps inside
+// loops. The analyzed package is built with -d=ssa/check_bce/debug=1, which
+// prints every check that bounds-check elimination could not remove, and each
+// check is mapped back to the index or slice expression it guards. Packages
+// the local toolchain cannot build report nothing.
+type BoundsCheckAnalyzer struct {
+	inSession
+}
+
+func NewBoundsCheckAnalyzer() Analyzer {
+	return &BoundsCheckAnalyzer{}
+}
+
+func (ba *BoundsCheckAnalyzer) Name() string {
+	return "Bounds Check"
+}
+
+// boundsCheckFlags makes the compiler print the bounds checks it keeps
+const boundsCheckFlags = "-d=ssa/check_bce/debug=1"
+
+// boundsCheckPackages caches the checks of package directories
+var boundsCheckPackages = newPackageCache[map[escapePos]string]()
+
+func (ba *BoundsCheckAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
+	file, ok := node.(*ast.File)
+	if !ok || !file.Pos().IsValid() {
+		return nil
+	}
+	filename := fset.Position(file.Pos()).Filename
+	if strings.HasSuffix(filename, "_test.go") {
+		return nil
+	}
+	sources := packageSources(ba.session.overlay(), filename)
+	if sources == nil {
+		return nil
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil
+	}
+	checks := boundsCheckPackages.get(dir, sources, func() map[escapePos]string {
+		return parseBoundsChecks(dir, compilerOutput(dir, ba.session.overlay(), boundsCheckFlags))
+	})
+	if len(checks) == 0 {
+		return nil
+	}
+
+	visitor := &bceVisitor{fset: fset, checks: checks}
+	ast.Walk(visitor, file)
+	return visitor.issues
+}
+
+// parseBoundsChecks parses "./file.go:line:col: Found IsInBounds" lines into
+// the kind of check by position
+func parseBoundsChecks(dir string, out []byte) map[escapePos]string {
+	checks := make(map[escapePos]string)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		pos, msg, ok := splitCompilerLine(dir, scanner.Text())
+		if kind, found := strings.CutPrefix(msg, "Found "); ok && found {
+			checks[pos] = kind
+		}
+	}
+	return checks
+}
+
+type bceVisitor struct {
+	fset   *token.FileSet
+	checks map[escapePos]string
+	loops  []ast.Stmt // enclosing loops, innermost last
+	issues []*models.Issue
+}
+
+func (v *bceVisitor) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.ForStmt:
+		if n.Init != nil {
+			ast.Walk(v, n.Init)
+		}
+		v.walkLoop(n, n.Body)
+		return nil
+	case *ast.RangeStmt:
+		if n.X != nil {
+			ast.Walk(v, n.X)
+		}
+		v.walkLoop(n, n.Body)
+		return nil
+	case *ast.IndexExpr:
+		if len(v.loops) > 0 {
+			v.check(n, n.Lbrack)
+		}
+	case *ast.SliceExpr:
+		if len(v.loops) > 0 {
+			v.check(n, n.Lbrack)
+		}
+	}
+	return v
+}
+
+func (v *bceVisitor) walkLoop(loop, body ast.Stmt) {
+	v.loops = append(v.loops, loop)
+	if f, ok := loop.(*ast.ForStmt); ok {
+		if f.Cond != nil {
+			ast.Walk(v, f.Cond)
+		}
+		if f.Post != nil {
+			ast.Walk(v, f.Post)
+		}
+	}
+	ast.Walk(v, body)
+	v.loops = v.loops[:len(v.loops)-1]
+}
+
+func (v *bceVisitor) check(expr ast.Expr, lbrack token.Pos) {
+	kind, ok := v.checks[positionOf(v.fset, lbrack)]
+	if !ok {
+		return
+	}
+
+	what := "Bounds check"
+	if kind == "IsSliceInBounds" {
+		what = "Slice bounds check"
+	}
+	pos := v.fset.Position(expr.Pos())
+	end := v.fset.Position(expr.End())
+	v.issues = append(v.issues, &models.Issue{
+		File:       pos.Filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
+		Position:   pos,
+		Type:       models.IssueBoundsCheckElimination,
+		Severity:   models.IssueBoundsCheckElimination.Severity(),
+		Message:    fmt.Sprintf("%s on %s runs on every iteration", what, types.ExprString(expr)),
+		Suggestion: v.hint(expr),
+		WhyBad:     "The compiler could not prove the access is in range, so it checks and branches to a panic on every iteration",
+	})
+}
+
+// hint suggests how to let the compiler prove the access in range: re-slice
+// the collection to the loop bound, or access the largest index once first
+func (v *bceVisitor) hint(expr ast.Expr) string {
+	index, ok := expr.(*ast.IndexExpr)
+	if !ok {
+		slice := expr.(*ast.SliceExpr)
+		return fmt.Sprintf(
+			"Re-slice %s once before the loop so its length is known, e.g. %s = %s[:n] with n the loop bound",
+			types.ExprString(slice.X), types.ExprString(slice.X), types.ExprString(slice.X),
+		)
+	}
+
+	x := types.ExprString(index.X)
+	if bound := v.loopBound(index.Index); bound != "" && bound != x {
+		return fmt.Sprintf("Re-slice %s to the loop bound before the loop: %s = %s[:len(%s)]", x, x, x, bound)
+	}
+	if literalIntValue(index.Index) >= 0 {
+		largest := v.maxConstIndex(index.X)
+		return fmt.Sprintf("Access the largest index first with _ = %s[%d] so one check covers the others", x, largest)
+	}
+	return fmt.Sprintf(
+		"Check the largest index once before the loop with _ = %s[n-1], or re-slice %s to the loop bound", x, x,
+	)
+}
+
+// loopBound returns the collection whose length bounds the innermost loop,
+// when index is that loop's counter: for i := range s, or i < len(s)
+func (v *bceVisitor) loopBound(index ast.Expr) string {
+	ident, ok := index.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	switch loop := v.loops[len(v.loops)-1].(type) {
+	case *ast.RangeStmt:
+		if key, ok := loop.Key.(*ast.Ident); ok && key.Name == ident.Name {
+			return types.ExprString(loop.X)
+		}
+	case *ast.ForStmt:
+		cond, ok := loop.Cond.(*ast.BinaryExpr)
+		if !ok || cond.Op != token.LSS {
+			return ""
+		}
+		counter, ok := cond.X.(*ast.Ident)
+		if !ok || counter.Name != ident.Name {
+			return ""
+		}
+		if call, ok := cond.Y.(*ast.CallExpr); ok && len(call.Args) == 1 {
+			if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "len" {
+				return types.ExprString(call.Args[0])
+			}
+		}
+	}
+	return ""
+}
+
+// maxConstIndex returns the largest constant index of x in the innermost loop
+func (v *bceVisitor) maxConstIndex(x ast.Expr) int {
+	name := types.ExprString(x)
+	largest := 0
+	ast.Inspect(v.loops[len(v.loops)-1], func(n ast.Node) bool {
+		if index, ok := n.(*ast.IndexExpr); ok && types.ExprString(index.X) == name {
+			if value := literalIntValue(index.Index); value > largest {
+				largest = value
+			}
+		}
+		return true
+	})
+	return largest
+}
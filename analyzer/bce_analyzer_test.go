@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBoundsChecks(t *testing.T) {
+	out := []byte(`# example.com/bce
+./main.go:6:13: Found IsInBounds
+./main.go:25:8: Found IsSliceInBounds
+main.go:1: not a check
+`)
+	checks := parseBoundsChecks("/src", out)
+	want := map[escapePos]string{
+		{filename: "/src/main.go", line: 6, column: 13}: "IsInBounds",
+		{filename: "/src/main.go", line: 25, column: 8}: "IsSliceInBounds",
+	}
+	if len(checks) != len(want) {
+		t.Fatalf("got %d checks, want %d: %v", len(checks), len(want), checks)
+	}
+	for pos, kind := range want {
+		if checks[pos] != kind {
+			t.Errorf("check at %v = %q, want %q", pos, checks[pos], kind)
+		}
+	}
+}
+
+func TestBoundsCheckAnalyzer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a package with the Go toolchain")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	code := `package main
+
+func pairs(a, b []int) int {
+	n := 0
+	for i := 0; i < len(a); i++ {
+		n += a[i] * b[i]
+	}
+	return n
+}
+
+func words(b []byte, n int) (sum uint32) {
+	for i := 0; i < n; i++ {
+		sum += uint32(b[0]) | uint32(b[3])<<8
+	}
+	return sum
+}
+
+func first(b []byte) byte {
+	return b[0]
+}
+
+func main() {
+	_ = pairs(nil, nil)
+	_ = words(nil, 1)
+	_ = first(nil)
+}
+`
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/bce\n\ngo 1.21\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(filename, []byte(code), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, issue := range NewBoundsCheckAnalyzer().Analyze(file, fset) {
+		got[issue.Message] = issue.Suggestion
+	}
+	want := map[string]string{
+		"Bounds check on b[i] runs on every iteration": "Re-slice b to the loop bound before the loop: b = b[:len(a)]",
+		"Bounds check on b[0] runs on every iteration": "Access the largest index first with _ = b[3] so one check covers the others",
+		"Bounds check on b[3] runs on every iteration": "Access the largest index first with _ = b[3] so one check covers the others",
+	}
+	for message, suggestion := range want {
+		if got[message] != suggestion {
+			t.Errorf("%q: suggestion = %q, want %q", message, got[message], suggestion)
+		}
+	}
+	// first(b) is outside any loop
+	if len(got) != len(want) {
+		t.Errorf("got %d issues, want %d: %v", len(got), len(want), got)
+	}
+}
@@ -246,6 +246,7 @@ func BenchmarkCacheHit(b *testing.B) {
 
 	// Populate cache
 	_ = AnalyzeAll("cache_hit_bench.go", file, fset)
+	key := cacheKey(file, &Options{})
 
 	b.ResetTimer()
 	var hitFailed atomic.Bool
@@ -253,7 +254,7 @@ func BenchmarkCacheHit(b *testing.B) {
 		func(pb *testing.PB) {
 			for pb.Next() {
 				// This should hit the cache
-				if _, ok := checkCache("cache_hit_bench.go", file); !ok {
+				if _, ok := checkCache("cache_hit_bench.go", key); !ok {
 					hitFailed.Store(true)
 				}
 			}
@@ -271,6 +272,7 @@ func BenchmarkCacheMiss(b *testing.B) {
 		b.Fatal(err)
 	}
 
+	key := cacheKey(file, &Options{})
 	b.ResetTimer()
 	var counter atomic.Uint64
 	b.RunParallel(
@@ -279,7 +281,7 @@ func BenchmarkCacheMiss(b *testing.B) {
 				// This should miss the cache (different filename each time)
 				i := counter.Add(1)
 				filename := fmt.Sprintf("cache_miss_%d.go", i)
-				checkCache(filename, file)
+				checkCache(filename, key)
 			}
 		},
 	)
@@ -129,7 +129,10 @@ func (ctx *cgoContext) handleCall(call *ast.CallExpr) {
 	}
 
 	pos := ctx.fset.Position(call.Pos())
+	end := ctx.fset.Position(call.End())
 
+	// The rule's default severity covers calls from functions on hot paths;
+	// a crossing in the loop itself costs more, and more again in nested loops
 	if ctx.loopDepth > 0 {
 		severity := models.SeverityLevelMedium
 		if ctx.loopDepth > 1 {
@@ -139,6 +142,8 @@ func (ctx *cgoContext) handleCall(call *ast.CallExpr) {
 			File:       pos.Filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueCGOInLoop,
 			Severity:   severity,
@@ -152,9 +157,11 @@ func (ctx *cgoContext) handleCall(call *ast.CallExpr) {
 			File:       pos.Filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueCGOMemoryLeak,
-			Severity:   models.SeverityLevelMedium,
+			Severity:   models.IssueCGOMemoryLeak.Severity(),
 			Message:    "CGO conversion allocates and copies between Go and C",
 			Suggestion: "Reuse buffers or prefer pure-Go conversions when possible",
 		})
@@ -89,13 +89,19 @@ type channelContext struct {
 
 type channelData struct {
 	buffered          bool
-	sends             []token.Position
-	receives          []token.Position
-	closes            []token.Position
+	sends             []channelOp
+	receives          []channelOp
+	closes            []channelOp
 	goroutineSends    bool
 	goroutineReceives bool
 }
 
+// channelOp is the source span of a send, receive or close
+type channelOp struct {
+	pos token.Position
+	end token.Position
+}
+
 func newChannelContext(fset *token.FileSet, filename string) *channelContext {
 	return &channelContext{
 		fset:     fset,
@@ -191,7 +197,7 @@ func (ctx *channelContext) markSend(send *ast.SendStmt) {
 		return
 	}
 	ch := ctx.ensureChannel(ident.Name)
-	ch.sends = append(ch.sends, ctx.fset.Position(send.Pos()))
+	ch.sends = append(ch.sends, ctx.newOp(send))
 	if ctx.loopDepth > 0 {
 		ch.goroutineSends = true
 	}
@@ -203,7 +209,7 @@ func (ctx *channelContext) markReceive(recv *ast.UnaryExpr) {
 		return
 	}
 	ch := ctx.ensureChannel(ident.Name)
-	ch.receives = append(ch.receives, ctx.fset.Position(recv.Pos()))
+	ch.receives = append(ch.receives, ctx.newOp(recv))
 	if ctx.loopDepth > 0 {
 		ch.goroutineReceives = true
 	}
@@ -219,7 +225,7 @@ func (ctx *channelContext) markClose(call *ast.CallExpr) {
 		return
 	}
 	ch := ctx.ensureChannel(target.Name)
-	ch.closes = append(ch.closes, ctx.fset.Position(call.Pos()))
+	ch.closes = append(ch.closes, ctx.newOp(call))
 }
 
 func (ctx *channelContext) markGoroutine(goStmt *ast.GoStmt) {
@@ -274,9 +280,9 @@ func (ctx *channelContext) finalize() {
 		}
 
 		if len(ch.closes) > 0 {
-			closeLine := ch.closes[0].Line
+			closeLine := ch.closes[0].pos.Line
 			for _, sendPos := range ch.sends {
-				if sendPos.Line > closeLine {
+				if sendPos.pos.Line > closeLine {
 					ctx.addIssue(sendPos, models.IssueChannelSendOnClosed,
 						"sending on closed channel '"+name+"'")
 				}
@@ -301,7 +307,7 @@ func (ctx *channelContext) finalize() {
 	}
 }
 
-func (ch *channelData) firstOp() token.Position {
+func (ch *channelData) firstOp() channelOp {
 	if len(ch.sends) > 0 {
 		return ch.sends[0]
 	}
@@ -311,17 +317,23 @@ func (ch *channelData) firstOp() token.Position {
 	if len(ch.closes) > 0 {
 		return ch.closes[0]
 	}
-	return token.Position{}
+	return channelOp{}
+}
+
+func (ctx *channelContext) newOp(node ast.Node) channelOp {
+	return channelOp{pos: ctx.fset.Position(node.Pos()), end: ctx.fset.Position(node.End())}
 }
 
-func (ctx *channelContext) addIssue(pos token.Position, issueType models.IssueType, msg string) {
+func (ctx *channelContext) addIssue(op channelOp, issueType models.IssueType, msg string) {
 	ctx.issues = append(ctx.issues, &models.Issue{
-		File:     ctx.filename,
-		Line:     pos.Line,
-		Column:   pos.Column,
-		Position: pos,
-		Type:     issueType,
-		Severity: issueType.Severity(),
-		Message:  msg,
+		File:      ctx.filename,
+		Line:      op.pos.Line,
+		Column:    op.pos.Column,
+		EndLine:   op.end.Line,
+		EndColumn: op.end.Column,
+		Position:  op.pos,
+		Type:      issueType,
+		Severity:  issueType.Severity(),
+		Message:   msg,
 	})
 }
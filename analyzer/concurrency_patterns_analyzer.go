@@ -189,12 +189,12 @@ func (ctx *concurrencyContext) handleGoStmt(stmt *ast.GoStmt) {
 	}
 
 	if ctx.loopCapturesRangeVar(fn.Body) {
-		ctx.addIssue(stmt.Pos(), models.IssueGoroutineCapturesLoop, models.SeverityLevelHigh,
+		ctx.addIssue(stmt, models.IssueGoroutineCapturesLoop, models.SeverityLevelHigh,
 			"goroutine captures loop variable", "Copy the loop variable inside the goroutine or pass it as an argument")
 	}
 
-	if pos, ok := findContextBackground(fn.Body); ok {
-		ctx.addIssue(pos, models.IssueContextBackgroundInGoroutine, models.SeverityLevelMedium,
+	if call, ok := findContextBackground(fn.Body); ok {
+		ctx.addIssue(call, models.IssueContextBackgroundInGoroutine, models.SeverityLevelMedium,
 			"goroutine starts with context.Background()", "Propagate the parent context instead of creating a background context")
 	}
 }
@@ -213,7 +213,7 @@ func (ctx *concurrencyContext) handleCallExpr(call *ast.CallExpr) {
 	typ := ctx.types.lookup(ident.Obj.Pos())
 	if sel.Sel.Name == methodAdd {
 		if isWaitGroupType(typ) && ctx.insideLoop() {
-			ctx.addIssue(call.Pos(), models.IssueWaitGroupAddInLoop, models.SeverityLevelMedium,
+			ctx.addIssue(call, models.IssueWaitGroupAddInLoop, models.SeverityLevelMedium,
 				"WaitGroup.Add inside loop", "Call Add once before the loop and use Done inside goroutines")
 		}
 	}
@@ -241,12 +241,15 @@ func (ctx *concurrencyContext) loopCapturesRangeVar(body *ast.BlockStmt) bool {
 	return captured
 }
 
-func (ctx *concurrencyContext) addIssue(pos token.Pos, issueType models.IssueType, severity models.SeverityLevel, message, suggestion string) {
-	position := ctx.fset.Position(pos)
+func (ctx *concurrencyContext) addIssue(node ast.Node, issueType models.IssueType, severity models.SeverityLevel, message, suggestion string) {
+	position := ctx.fset.Position(node.Pos())
+	end := ctx.fset.Position(node.End())
 	ctx.issues = append(ctx.issues, &models.Issue{
 		File:       ctx.filename,
 		Line:       position.Line,
 		Column:     position.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   position,
 		Type:       issueType,
 		Severity:   severity,
@@ -255,8 +258,8 @@ func (ctx *concurrencyContext) addIssue(pos token.Pos, issueType models.IssueTyp
 	})
 }
 
-func findContextBackground(body *ast.BlockStmt) (token.Pos, bool) {
-	var hit token.Pos
+func findContextBackground(body *ast.BlockStmt) (*ast.CallExpr, bool) {
+	var hit *ast.CallExpr
 
 	ast.Inspect(body, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
@@ -275,15 +278,12 @@ func findContextBackground(body *ast.BlockStmt) (token.Pos, bool) {
 		}
 
 		if ident.Name == pkgContext && sel.Sel.Name == backgroundFunc {
-			hit = call.Pos()
+			hit = call
 			return false
 		}
 
 		return true
 	})
 
-	if hit == token.NoPos {
-		return token.NoPos, false
-	}
-	return hit, true
+	return hit, hit != nil
 }
@@ -79,8 +79,7 @@ func (ca *contextAnalysis) inspectFunction(fn *ast.FuncDecl) {
 		}
 		if isContextType(field.Type) {
 			if i > 0 {
-				pos := ca.fset.Position(field.Pos())
-				ca.addIssue(pos, models.IssueContextNotFirst, "context.Context should be the first parameter")
+				ca.addIssue(field, models.IssueContextNotFirst, "context.Context should be the first parameter")
 			}
 			break
 		}
@@ -105,20 +104,23 @@ func (ca *contextAnalysis) inspectCall(call *ast.CallExpr) {
 		return
 	}
 	if _, ok := call.Args[1].(*ast.BasicLit); ok {
-		pos := ca.fset.Position(call.Args[1].Pos())
-		ca.addIssue(pos, models.IssueContextValue, "avoid using basic types as context keys")
+		ca.addIssue(call.Args[1], models.IssueContextValue, "avoid using basic types as context keys")
 	}
 }
 
-func (ca *contextAnalysis) addIssue(pos token.Position, issueType models.IssueType, msg string) {
+func (ca *contextAnalysis) addIssue(node ast.Node, issueType models.IssueType, msg string) {
+	pos := ca.fset.Position(node.Pos())
+	end := ca.fset.Position(node.End())
 	ca.issues = append(ca.issues, &models.Issue{
-		File:     ca.filename,
-		Line:     pos.Line,
-		Column:   pos.Column,
-		Position: pos,
-		Type:     issueType,
-		Severity: issueType.Severity(),
-		Message:  msg,
+		File:      ca.filename,
+		Line:      pos.Line,
+		Column:    pos.Column,
+		EndLine:   end.Line,
+		EndColumn: end.Column,
+		Position:  pos,
+		Type:      issueType,
+		Severity:  issueType.Severity(),
+		Message:   msg,
 	})
 }
 
@@ -65,10 +65,13 @@ func analyzeStructForFalseSharing(name string, st *ast.StructType, fset *token.F
 			concurrentFields++
 			if concurrentFields > 1 {
 				pos := fset.Position(st.Pos())
+				end := fset.Position(st.End())
 				return &models.Issue{
 					File:       filename,
 					Line:       pos.Line,
 					Column:     pos.Column,
+					EndLine:    end.Line,
+					EndColumn:  end.Column,
 					Position:   pos,
 					Type:       models.IssueCacheFalseSharing,
 					Severity:   models.SeverityLevelLow,
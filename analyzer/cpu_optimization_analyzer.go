@@ -18,48 +18,39 @@ func (coa *CPUOptimizationAnalyzer) Name() string {
 }
 
 func (coa *CPUOptimizationAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
-	file, ok := node.(*ast.File)
-	if !ok {
-		return nil
-	}
+	return walkAlone(coa, node, fset)
+}
 
+func (coa *CPUOptimizationAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
 	issues := make([]*models.Issue, 0, 8)
-
-	ast.Inspect(file, func(n ast.Node) bool {
-		if fn, ok := n.(*ast.FuncDecl); ok {
-			if fn.Body != nil {
-				issues = append(issues, checkLenInLoop(fn.Body, fset)...)
-			}
+	w.On(func(n ast.Node, wc *WalkContext) {
+		forStmt, _ := n.(*ast.ForStmt)
+		if issue := checkLenInLoop(forStmt, wc.Fset); issue != nil {
+			issues = append(issues, issue)
 		}
-		return true
-	})
-
-	return issues
+	}, (*ast.ForStmt)(nil))
+	return func() []*models.Issue { return issues }
 }
 
-func checkLenInLoop(body *ast.BlockStmt, fset *token.FileSet) []*models.Issue {
-	issues := make([]*models.Issue, 0, 2)
-
-	ast.Inspect(body, func(n ast.Node) bool {
-		if forStmt, ok := n.(*ast.ForStmt); ok {
-			if forStmt.Cond != nil && containsLenCall(forStmt.Cond) {
-				pos := fset.Position(forStmt.Cond.Pos())
-				issues = append(issues, &models.Issue{
-					File:       pos.Filename,
-					Line:       pos.Line,
-					Column:     pos.Column,
-					Position:   pos,
-					Type:       models.IssueCPUIntensive,
-					Severity:   models.SeverityLevelLow,
-					Message:    "len() computed in loop condition; consider hoisting",
-					Suggestion: "Cache len(collection) in a variable before the loop",
-				})
-			}
-		}
-		return true
-	})
-
-	return issues
+// checkLenInLoop reports a for loop whose condition calls len
+func checkLenInLoop(forStmt *ast.ForStmt, fset *token.FileSet) *models.Issue {
+	if forStmt.Cond == nil || !containsLenCall(forStmt.Cond) {
+		return nil
+	}
+	pos := fset.Position(forStmt.Cond.Pos())
+	end := fset.Position(forStmt.Cond.End())
+	return &models.Issue{
+		File:       pos.Filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
+		Position:   pos,
+		Type:       models.IssueCPUIntensive,
+		Severity:   models.SeverityLevelLow,
+		Message:    "len() computed in loop condition; consider hoisting",
+		Suggestion: "Cache len(collection) in a variable before the loop",
+	}
 }
 
 func containsLenCall(expr ast.Expr) bool {
@@ -18,32 +18,18 @@ func (ca *CryptoAnalyzer) Name() string {
 }
 
 func (ca *CryptoAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
-	file, ok := node.(*ast.File)
-	if !ok {
-		return nil
-	}
-
-	filename := ""
-	if file.Pos().IsValid() {
-		filename = fset.Position(file.Pos()).Filename
-	}
+	return walkAlone(ca, node, fset)
+}
 
+func (ca *CryptoAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
 	issues := make([]*models.Issue, 0, 4)
-
-	ast.Inspect(file, func(n ast.Node) bool {
-		call, ok := n.(*ast.CallExpr)
-		if !ok {
-			return true
-		}
-
-		issue := ca.inspectCall(call, fset, filename)
-		if issue != nil {
+	w.On(func(n ast.Node, wc *WalkContext) {
+		call, _ := n.(*ast.CallExpr)
+		if issue := ca.inspectCall(call, wc.Fset, wc.Filename); issue != nil {
 			issues = append(issues, issue)
 		}
-		return true
-	})
-
-	return issues
+	}, (*ast.CallExpr)(nil))
+	return func() []*models.Issue { return issues }
 }
 
 func (ca *CryptoAnalyzer) inspectCall(call *ast.CallExpr, fset *token.FileSet, filename string) *models.Issue {
@@ -61,10 +47,13 @@ func (ca *CryptoAnalyzer) inspectCall(call *ast.CallExpr, fset *token.FileSet, f
 	case "rand":
 		if sel.Sel.Name == "Read" || sel.Sel.Name == "Int" || sel.Sel.Name == "Intn" || sel.Sel.Name == "Perm" {
 			pos := fset.Position(call.Pos())
+			end := fset.Position(call.End())
 			return &models.Issue{
 				File:       filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueInsecureRandom,
 				Severity:   models.SeverityLevelHigh,
@@ -74,10 +63,13 @@ func (ca *CryptoAnalyzer) inspectCall(call *ast.CallExpr, fset *token.FileSet, f
 		}
 	case "md5", "sha1":
 		pos := fset.Position(call.Pos())
+		end := fset.Position(call.End())
 		return &models.Issue{
 			File:       filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueWeakHash,
 			Severity:   models.SeverityLevelMedium,
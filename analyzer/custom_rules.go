@@ -0,0 +1,293 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// CustomRulesAnalyzerName is the registered name of the analyzer that
+// evaluates Options.CustomRules
+const CustomRulesAnalyzerName = "customrules"
+
+// CallPattern matches calls of one package-level function or method by the
+// import path of its package
+type CallPattern struct {
+	Path string // import path, e.g. "database/sql"
+	Type string // receiver type name for methods, e.g. "DB"; empty for functions
+	Name string // function or method name, e.g. "Query"
+}
+
+// ParseCallPattern parses "path.Func", "path.Type.Method" or
+// "(*path.Type).Method", e.g. "net/http.Get" or "database/sql.Rows.Close".
+// Methods match value and pointer receivers alike.
+func ParseCallPattern(s string) (CallPattern, error) {
+	spec := strings.TrimSpace(s)
+	if strings.HasPrefix(spec, "(") {
+		spec = strings.Replace(strings.TrimPrefix(spec, "("), ")", "", 1)
+		spec = strings.TrimPrefix(spec, "*")
+	}
+
+	slash := strings.LastIndex(spec, "/")
+	parts := strings.Split(spec[slash+1:], ".")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return CallPattern{}, fmt.Errorf("call %q: want path.Func or path.Type.Method", s)
+	}
+	for _, name := range parts[1:] {
+		if !token.IsIdentifier(name) {
+			return CallPattern{}, fmt.Errorf("call %q: %q is not an identifier", s, name)
+		}
+	}
+
+	p := CallPattern{Path: spec[:slash+1] + parts[0], Name: parts[len(parts)-1]}
+	if len(parts) == 3 {
+		p.Type = parts[1]
+	}
+	return p, nil
+}
+
+func (p CallPattern) String() string {
+	if p.Type != "" {
+		return p.Path + "." + p.Type + "." + p.Name
+	}
+	return p.Path + "." + p.Name
+}
+
+// CustomRule reports calls matching Call in the contexts its constraints
+// describe. Every constraint that is set must hold.
+type CustomRule struct {
+	Type models.IssueType // issue type reported, usually added with models.RegisterRule
+	Call CallPattern
+
+	InLoop      bool // the call runs on every iteration of a loop
+	InGoroutine bool // the call runs in a go statement or the function literal it starts
+	InHandler   bool // the call is in an HTTP handler function
+	// MissingCall, when set, limits the rule to functions that do not also
+	// call it, e.g. a Query without a Rows.Close
+	MissingCall *CallPattern
+
+	Message    string
+	Suggestion string
+}
+
+// CustomRulesAnalyzer evaluates the declarative rules from the configuration
+type CustomRulesAnalyzer struct{}
+
+func NewCustomRulesAnalyzer() Analyzer {
+	return &CustomRulesAnalyzer{}
+}
+
+func (ca *CustomRulesAnalyzer) Name() string {
+	return "Custom Rules"
+}
+
+func (ca *CustomRulesAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
+	return walkAlone(ca, node, fset)
+}
+
+func (ca *CustomRulesAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
+	rules := w.session.customRules()
+	if len(rules) == 0 {
+		return func() []*models.Issue { return nil }
+	}
+
+	c := &customRulesCollector{
+		rules:    rules,
+		overlay:  w.session.overlay(),
+		fset:     w.FileSet(),
+		file:     w.File(),
+		imports:  importNames(w.File()),
+		handlers: make(map[*ast.FuncDecl]bool),
+	}
+	w.On(c.visitCall, (*ast.CallExpr)(nil))
+	return c.issues
+}
+
+// customCall is a call whose name matches a rule, with the context it was
+// found in; whether it matches is decided with type information once the
+// file has been walked
+type customCall struct {
+	call      *ast.CallExpr
+	filename  string
+	scope     ast.Node // declared function around the call, or the literal at package level
+	inLoop    bool
+	inGo      bool
+	inHandler bool
+}
+
+type customRulesCollector struct {
+	rules    []CustomRule
+	overlay  Overlay
+	fset     *token.FileSet
+	file     *ast.File
+	imports  map[string]string
+	handlers map[*ast.FuncDecl]bool
+
+	calls []customCall
+
+	info       *types.Info
+	infoLoaded bool
+}
+
+func (c *customRulesCollector) visitCall(n ast.Node, wc *WalkContext) {
+	call, _ := n.(*ast.CallExpr)
+	name := calleeName(call)
+	if name == nil || !c.mentions(name.Name) {
+		return
+	}
+
+	found := customCall{
+		call:     call,
+		filename: wc.Filename,
+		scope:    wc.Func,
+		inLoop:   wc.InLoop(),
+		inGo:     wc.Go != nil,
+	}
+	if wc.FuncDecl != nil {
+		found.scope = wc.FuncDecl
+		handler, ok := c.handlers[wc.FuncDecl]
+		if !ok {
+			handler = isHTTPHandler(wc.FuncDecl, c.imports)
+			c.handlers[wc.FuncDecl] = handler
+		}
+		found.inHandler = handler
+	}
+	c.calls = append(c.calls, found)
+}
+
+// mentions reports whether a rule is about a function or method called name
+func (c *customRulesCollector) mentions(name string) bool {
+	for _, rule := range c.rules {
+		if rule.Call.Name == name || (rule.MissingCall != nil && rule.MissingCall.Name == name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *customRulesCollector) issues() []*models.Issue {
+	var issues []*models.Issue
+	for _, rule := range c.rules {
+		var present map[ast.Node]bool
+		if rule.MissingCall != nil {
+			present = make(map[ast.Node]bool)
+			for _, found := range c.calls {
+				if c.matches(*rule.MissingCall, found.call) {
+					present[found.scope] = true
+				}
+			}
+		}
+
+		for _, found := range c.calls {
+			switch {
+			case rule.InLoop && !found.inLoop,
+				rule.InGoroutine && !found.inGo,
+				rule.InHandler && !found.inHandler,
+				present != nil && present[found.scope],
+				!c.matches(rule.Call, found.call):
+				continue
+			}
+			issues = append(issues, c.issue(rule, found))
+		}
+	}
+	return issues
+}
+
+func (c *customRulesCollector) issue(rule CustomRule, found customCall) *models.Issue {
+	message := rule.Message
+	if message == "" {
+		message = "call to " + rule.Call.String()
+	}
+	pos := c.fset.Position(found.call.Pos())
+	end := c.fset.Position(found.call.End())
+	return &models.Issue{
+		File:       found.filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
+		Position:   pos,
+		Type:       rule.Type,
+		Severity:   rule.Type.Severity(),
+		Message:    message,
+		Suggestion: rule.Suggestion,
+	}
+}
+
+// matches reports whether call calls the function or method p describes.
+// Without type information only package-level functions are recognized, by
+// the import path of the package qualifier.
+func (c *customRulesCollector) matches(p CallPattern, call *ast.CallExpr) bool {
+	name := calleeName(call)
+	if name == nil || name.Name != p.Name {
+		return false
+	}
+
+	if info := c.typeInfo(); info != nil {
+		if fn, ok := info.Uses[name].(*types.Func); ok {
+			return funcMatches(p, fn)
+		}
+	}
+
+	if p.Type != "" {
+		return false
+	}
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && c.imports[pkg.Name] == p.Path
+}
+
+// typeInfo type-checks the file on first use
+func (c *customRulesCollector) typeInfo() *types.Info {
+	if !c.infoLoaded {
+		c.infoLoaded = true
+		filename := ""
+		if c.file.Pos().IsValid() {
+			filename = c.fset.Position(c.file.Pos()).Filename
+		}
+		c.info, _ = loadTypes(c.fset, c.file, filename, c.overlay)
+	}
+	return c.info
+}
+
+func funcMatches(p CallPattern, fn *types.Func) bool {
+	if fn.Pkg() == nil || fn.Pkg().Path() != p.Path {
+		return false
+	}
+	sig, _ := fn.Type().(*types.Signature)
+	if sig == nil || sig.Recv() == nil {
+		return p.Type == ""
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	return ok && named.Obj().Name() == p.Type
+}
+
+// calleeName returns the identifier naming the function a call calls, or nil
+// for calls of function values computed by expressions
+func calleeName(call *ast.CallExpr) *ast.Ident {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	}
+	return nil
+}
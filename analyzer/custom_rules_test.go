@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const customRulesTestCode = `package main
+
+import (
+	"database/sql"
+	"net/http"
+	"sync"
+	"time"
+)
+
+func poll(items []int) {
+	time.Sleep(time.Second)
+	for range items {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	http.Get("http://example.com")
+}
+
+func fetch() {
+	http.Get("http://example.com")
+}
+
+func leak(db *sql.DB) {
+	db.Query("SELECT 1")
+}
+
+func closed(db *sql.DB) error {
+	rows, err := db.Query("SELECT 1")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return nil
+}
+
+func spawn(wg *sync.WaitGroup) {
+	wg.Add(1)
+	go func() {
+		wg.Add(1)
+	}()
+}
+`
+
+func mustCallPattern(t *testing.T, s string) CallPattern {
+	t.Helper()
+	p, err := ParseCallPattern(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestParseCallPattern(t *testing.T) {
+	tests := map[string]CallPattern{
+		"net/http.Get":                {Path: "net/http", Name: "Get"},
+		"database/sql.DB.Query":       {Path: "database/sql", Type: "DB", Name: "Query"},
+		"(*database/sql.Rows).Close":  {Path: "database/sql", Type: "Rows", Name: "Close"},
+		"example.com/ourlog.Debugf":   {Path: "example.com/ourlog", Name: "Debugf"},
+		"example.com/v2/log.L.Debugf": {Path: "example.com/v2/log", Type: "L", Name: "Debugf"},
+	}
+	for spec, want := range tests {
+		got, err := ParseCallPattern(spec)
+		if err != nil || got != want {
+			t.Errorf("ParseCallPattern(%q) = %+v, %v; want %+v", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"", "Get", "net/http.", "net/http.a.b.c", "net/http.Get()"} {
+		if _, err := ParseCallPattern(spec); err == nil {
+			t.Errorf("ParseCallPattern(%q) should fail", spec)
+		}
+	}
+}
+
+func TestCustomRulesAnalyzer(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", customRulesTestCode, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	if issues := NewCustomRulesAnalyzer().Analyze(file, fset); len(issues) != 0 {
+		t.Fatalf("found %d issues without rules", len(issues))
+	}
+
+	sleepInLoop := models.MustRegisterRule(models.Rule{ID: "TEST-101", Name: "TestSleepInLoop"})
+	getInHandler := models.MustRegisterRule(models.Rule{ID: "TEST-102", Name: "TestGetInHandler"})
+	queryWithoutClose := models.MustRegisterRule(models.Rule{
+		ID: "TEST-103", Name: "TestQueryWithoutClose", Severity: models.SeverityLevelHigh,
+	})
+	addInGoroutine := models.MustRegisterRule(models.Rule{ID: "TEST-104", Name: "TestAddInGoroutine"})
+	missingClose := mustCallPattern(t, "database/sql.Rows.Close")
+	rules := []CustomRule{
+		{Type: sleepInLoop, Call: mustCallPattern(t, "time.Sleep"), InLoop: true, Message: "sleep in loop"},
+		{Type: getInHandler, Call: mustCallPattern(t, "net/http.Get"), InHandler: true},
+		{Type: queryWithoutClose, Call: mustCallPattern(t, "database/sql.DB.Query"), MissingCall: &missingClose},
+		{Type: addInGoroutine, Call: mustCallPattern(t, "sync.WaitGroup.Add"), InGoroutine: true},
+	}
+	want := map[models.IssueType]int{sleepInLoop: 13, getInHandler: 18, queryWithoutClose: 26, addInGoroutine: 41}
+	opts := Options{Analyzers: map[string]bool{CustomRulesAnalyzerName: true}, CustomRules: rules}
+	issues := AnalyzeWithOptions("test.go", file, fset, opts)
+	if len(issues) != len(want) {
+		for _, issue := range issues {
+			t.Logf("%s at %d", issue.Type, issue.Line)
+		}
+		t.Fatalf("found %d issues, want %d", len(issues), len(want))
+	}
+	for _, issue := range issues {
+		if line, ok := want[issue.Type]; !ok || issue.Line != line {
+			t.Errorf("%s at line %d, want line %d", issue.Type, issue.Line, line)
+		}
+		if issue.Severity != issue.Type.Severity() || issue.Message == "" {
+			t.Errorf("%s: severity %s, message %q", issue.Type, issue.Severity, issue.Message)
+		}
+	}
+	if issues[0].Message != "sleep in loop" || issues[2].Severity != models.SeverityLevelHigh {
+		t.Errorf("rule message and severity not used: %+v, %+v", issues[0], issues[2])
+	}
+}
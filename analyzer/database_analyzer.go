@@ -10,7 +10,9 @@ import (
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
-type DatabaseAnalyzer struct{}
+type DatabaseAnalyzer struct {
+	inSession
+}
 
 func NewDatabaseAnalyzer() Analyzer {
 	return &DatabaseAnalyzer{}
@@ -27,6 +29,7 @@ func (da *DatabaseAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*mo
 	}
 
 	issues := make([]*models.Issue, 0, 16)
+	file, _ := node.(*ast.File)
 
 	ast.Inspect(
 		root, func(n ast.Node) bool {
@@ -35,7 +38,7 @@ func (da *DatabaseAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*mo
 				return true
 			}
 
-			issues = append(issues, da.analyzeFunction(fn, fset)...)
+			issues = append(issues, da.analyzeFunction(file, fn, fset)...)
 			return false
 		},
 	)
@@ -43,13 +46,14 @@ func (da *DatabaseAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*mo
 	return issues
 }
 
-func (da *DatabaseAnalyzer) analyzeFunction(fn *ast.FuncDecl, fset *token.FileSet) []*models.Issue {
+func (da *DatabaseAnalyzer) analyzeFunction(file *ast.File, fn *ast.FuncDecl, fset *token.FileSet) []*models.Issue {
 	if fn.Body == nil {
 		return nil
 	}
 
 	ctx := &dbFunctionContext{
 		analyzer:     da,
+		file:         file,
 		fn:           fn,
 		loopRoot:     fn.Body,
 		fset:         fset,
@@ -79,6 +83,7 @@ func (da *DatabaseAnalyzer) analyzeFunction(fn *ast.FuncDecl, fset *token.FileSe
 
 type dbFunctionContext struct {
 	analyzer     *DatabaseAnalyzer
+	file         *ast.File // nil when a single node is analyzed
 	fn           *ast.FuncDecl
 	loopRoot     ast.Node
 	fset         *token.FileSet
@@ -90,6 +95,7 @@ type dbFunctionContext struct {
 
 type transactionState struct {
 	pos              token.Position
+	end              token.Position
 	hasRollback      bool
 	rollbackDeferred bool
 	hasCommit        bool
@@ -97,6 +103,7 @@ type transactionState struct {
 
 type resourceState struct {
 	pos    token.Position
+	end    token.Position
 	closed bool
 }
 
@@ -123,17 +130,18 @@ func (ctx *dbFunctionContext) handleAssign(assign *ast.AssignStmt) {
 
 		name := ident.Name
 		pos := ctx.fset.Position(call.Pos())
+		end := ctx.fset.Position(call.End())
 		method := sel.Sel.Name
 
 		switch {
 		case ctx.analyzer.isQueryReturningRows(method):
-			ctx.rows[name] = &resourceState{pos: pos}
+			ctx.rows[name] = &resourceState{pos: pos, end: end}
 
 		case ctx.analyzer.isPrepareMethod(method):
-			ctx.statements[name] = &resourceState{pos: pos}
+			ctx.statements[name] = &resourceState{pos: pos, end: end}
 
 		case ctx.analyzer.isTransactionBegin(method):
-			ctx.transactions[name] = &transactionState{pos: pos}
+			ctx.transactions[name] = &transactionState{pos: pos, end: end}
 		}
 	}
 }
@@ -219,9 +227,10 @@ func (ctx *dbFunctionContext) handleQueryCall(sel *ast.SelectorExpr, call *ast.C
 	}
 
 	pos := ctx.fset.Position(call.Pos())
-	if IsInLoop(ctx.loopRoot, call) {
+	end := ctx.fset.Position(call.End())
+	if ctx.inLoop(call) {
 		ctx.addIssue(
-			pos,
+			pos, end,
 			models.IssueSQLNPlusOne,
 			models.SeverityLevelHigh,
 			"Database query inside loop can trigger N+1 problems",
@@ -231,7 +240,7 @@ func (ctx *dbFunctionContext) handleQueryCall(sel *ast.SelectorExpr, call *ast.C
 
 	if ctx.analyzer.hasInjectionRisk(call, method) {
 		ctx.addIssue(
-			pos,
+			pos, end,
 			models.IssueSQLNPlusOne,
 			models.SeverityLevelHigh,
 			"Query text built from dynamic strings may be injectable",
@@ -244,9 +253,10 @@ func (ctx *dbFunctionContext) handleQueryCall(sel *ast.SelectorExpr, call *ast.C
 		return
 	}
 	upperQuery := strings.ToUpper(query)
+	// SELECT * only wastes bandwidth, far less than a query per row
 	if strings.Contains(upperQuery, "SELECT *") {
 		ctx.addIssue(
-			pos,
+			pos, end,
 			models.IssueSQLNPlusOne,
 			models.SeverityLevelLow,
 			"SELECT * fetches unnecessary columns",
@@ -259,7 +269,7 @@ func (ctx *dbFunctionContext) finalize() {
 	for name, tx := range ctx.transactions {
 		if !tx.hasRollback {
 			ctx.addIssue(
-				tx.pos,
+				tx.pos, tx.end,
 				models.IssueMissingDefer,
 				models.SeverityLevelHigh,
 				fmt.Sprintf("Transaction '%s' has no rollback protection", name),
@@ -271,7 +281,7 @@ func (ctx *dbFunctionContext) finalize() {
 	for name, res := range ctx.rows {
 		if !res.closed {
 			ctx.addIssue(
-				res.pos,
+				res.pos, res.end,
 				models.IssueMissingClose,
 				models.SeverityLevelMedium,
 				fmt.Sprintf("Result set '%s' is not closed", name),
@@ -283,7 +293,7 @@ func (ctx *dbFunctionContext) finalize() {
 	for name, stmt := range ctx.statements {
 		if !stmt.closed {
 			ctx.addIssue(
-				stmt.pos,
+				stmt.pos, stmt.end,
 				models.IssueMissingClose,
 				models.SeverityLevelMedium,
 				fmt.Sprintf("Prepared statement '%s' is not closed", name),
@@ -294,13 +304,15 @@ func (ctx *dbFunctionContext) finalize() {
 }
 
 func (ctx *dbFunctionContext) addIssue(
-	pos token.Position, issueType models.IssueType, severity models.SeverityLevel, message, suggestion string,
+	pos, end token.Position, issueType models.IssueType, severity models.SeverityLevel, message, suggestion string,
 ) {
 	ctx.issues = append(
 		ctx.issues, &models.Issue{
 			File:       pos.Filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       issueType,
 			Severity:   severity,
@@ -436,3 +448,16 @@ func extractQueryLiteral(call *ast.CallExpr) (string, bool) {
 
 	return value, true
 }
+
+// inLoop reports whether call can run more than once per call of the
+// function. The SSA form sees loops the syntax hides, like goto loops, and
+// knows that a loop body ending in an unconditional break runs once; files
+// whose package does not type-check fall back to the syntax.
+func (ctx *dbFunctionContext) inLoop(call *ast.CallExpr) bool {
+	if s := ctx.analyzer.session.ssaOf(ctx.fset, ctx.file); s != nil {
+		if depth, ok := s.LoopDepth(call); ok {
+			return depth > 0
+		}
+	}
+	return IsInLoop(ctx.loopRoot, call)
+}
@@ -78,10 +78,13 @@ func analyzeBlock(body *ast.BlockStmt, filename string, fset *token.FileSet, inL
 		if count > 1 {
 			// report once at block start
 			pos := fset.Position(body.Pos())
+			end := fset.Position(body.End())
 			issues = append(issues, &models.Issue{
 				File:       filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueMultipleDefers,
 				Severity:   models.SeverityLevelLow,
@@ -111,11 +114,14 @@ func analyzeDeferStmt(
 	}
 
 	pos := fset.Position(stmt.Pos())
+	end := fset.Position(stmt.End())
 	if inLoop {
 		issues = append(issues, &models.Issue{
 			File:       filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueDeferInLoop,
 			Severity:   models.SeverityLevelMedium,
@@ -129,6 +135,8 @@ func analyzeDeferStmt(
 			File:       filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueDeferAtEnd,
 			Severity:   models.SeverityLevelLow,
@@ -143,6 +151,8 @@ func analyzeDeferStmt(
 				File:       filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueDeferInShortFunc,
 				Severity:   models.SeverityLevelLow,
@@ -35,7 +35,7 @@ func (a *DependencyAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*m
 			continue
 		}
 		path := trimQuote(imp.Path.Value)
-		if issue := checkImportPath(path, fset.Position(imp.Pos()), filename); issue != nil {
+		if issue := checkImportPath(path, fset.Position(imp.Pos()), fset.Position(imp.End()), filename); issue != nil {
 			issues = append(issues, issue)
 		}
 	}
@@ -43,13 +43,15 @@ func (a *DependencyAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*m
 	return issues
 }
 
-func checkImportPath(path string, pos token.Position, filename string) *models.Issue {
+func checkImportPath(path string, pos, end token.Position, filename string) *models.Issue {
 	switch path {
 	case "unsafe":
 		return &models.Issue{
 			File:       filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueDependencyUnsafe,
 			Severity:   models.SeverityLevelMedium,
@@ -61,6 +63,8 @@ func checkImportPath(path string, pos token.Position, filename string) *models.I
 			File:       filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueDependencyCGO,
 			Severity:   models.SeverityLevelLow,
@@ -74,6 +78,8 @@ func checkImportPath(path string, pos token.Position, filename string) *models.I
 			File:       filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueDependencyVersionConflict,
 			Severity:   models.SeverityLevelLow,
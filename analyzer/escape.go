@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EscapeAnalysis runs the compiler's escape analysis on the packages of the
+// analyzed files, so allocation findings can be confirmed and inlining
+// decisions reported. Each package is built once per content with
+// `go build -gcflags=-m=2`; packages the local toolchain cannot build keep
+// the syntactic findings.
+type EscapeAnalysis struct {
+	packages *packageCache[*EscapeFacts]
+}
+
+// NewEscapeAnalysis returns an EscapeAnalysis that has not built anything yet
+func NewEscapeAnalysis() *EscapeAnalysis {
+	return &EscapeAnalysis{packages: newPackageCache[*EscapeFacts]()}
+}
+
+// packageFacts returns the compiler decisions for the package of filename,
+// built with overlay, or nil when the package does not build
+func (ea *EscapeAnalysis) packageFacts(filename string, overlay Overlay) *EscapeFacts {
+	sources := packageSources(overlay, filename)
+	if sources == nil {
+		return nil
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil
+	}
+	return ea.packages.get(dir, sources, func() *EscapeFacts {
+		return buildEscapeFacts(dir, overlay)
+	})
+}
+
+// buildEscapeFacts builds the package in dir with the compiler's decisions
+// printed and parses them; nil means nothing was decided
+func buildEscapeFacts(dir string, overlay Overlay) *EscapeFacts {
+	facts := parseEscapeFacts(dir, compilerOutput(dir, overlay, "-m=2"))
+	if len(facts.allocs) == 0 && len(facts.inlining) == 0 {
+		return nil
+	}
+	return facts
+}
+
+// escapePos is a position printed by the compiler
+type escapePos struct {
+	filename     string // absolute
+	line, column int
+}
+
+// EscapeFact is the compiler's decision on where a value is allocated
+type EscapeFact struct {
+	Heap    bool   // escapes to the heap, or the variable is moved there
+	Message string // the decision as printed, e.g. "&T{...} escapes to heap"
+}
+
+// InlineFact is the compiler's decision on inlining a function
+type InlineFact struct {
+	CanInline bool
+	Cost      int    // 0 when the compiler gave no cost
+	Budget    int    // 0 when the compiler gave no budget
+	Reason    string // why the function cannot be inlined
+}
+
+// EscapeFacts are the compiler decisions for one package, by position
+type EscapeFacts struct {
+	allocs   map[escapePos]EscapeFact
+	inlining map[escapePos]InlineFact
+}
+
+// FileEscapes are the compiler decisions for one file, attached to the nodes
+// they were made for
+type FileEscapes struct {
+	Allocs   map[ast.Expr]EscapeFact
+	Inlining map[*ast.FuncDecl]InlineFact
+}
+
+// Attach maps the decisions made for file to its nodes. An allocation
+// decision belongs to the outermost expression the compiler reports at its
+// position, an inlining decision to the function declared there. The compiler
+// repeats some decisions for an operand; those stay with the outer expression.
+func (f *EscapeFacts) Attach(fset *token.FileSet, file *ast.File) *FileEscapes {
+	attached := &FileEscapes{
+		Allocs:   make(map[ast.Expr]EscapeFact),
+		Inlining: make(map[*ast.FuncDecl]InlineFact),
+	}
+	if f == nil {
+		return attached
+	}
+
+	seen := make(map[escapePos]bool)
+	byMessage := make(map[string][]ast.Expr)
+	repeated := func(expr ast.Expr, message string) bool {
+		for _, outer := range byMessage[message] {
+			if outer.Pos() <= expr.Pos() && expr.End() <= outer.End() {
+				return true
+			}
+		}
+		return false
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if fact, ok := f.inlining[positionOf(fset, n.Name.Pos())]; ok {
+				attached.Inlining[n] = fact
+			}
+		case ast.Expr:
+			pos := positionOf(fset, compilerPos(n))
+			if fact, ok := f.allocs[pos]; ok && !seen[pos] {
+				seen[pos] = true
+				if !repeated(n, fact.Message) {
+					attached.Allocs[n] = fact
+					byMessage[fact.Message] = append(byMessage[fact.Message], n)
+				}
+			}
+		}
+		return true
+	})
+	return attached
+}
+
+// compilerPos returns the position the compiler reports decisions about expr
+// at, which for calls, literals and operators is not where expr starts
+func compilerPos(expr ast.Expr) token.Pos {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		return e.Lparen
+	case *ast.CompositeLit:
+		return e.Lbrace
+	case *ast.BinaryExpr:
+		return e.OpPos
+	}
+	return expr.Pos()
+}
+
+func positionOf(fset *token.FileSet, pos token.Pos) escapePos {
+	p := fset.Position(pos)
+	filename, err := filepath.Abs(p.Filename)
+	if err != nil {
+		filename = p.Filename
+	}
+	return escapePos{filename: filename, line: p.Line, column: p.Column}
+}
+
+// parseEscapeFacts parses the output of `go build -gcflags=-m=2` run in dir.
+// Only final decisions are kept: explanations are indented, and the headers
+// that introduce them end with a colon.
+func parseEscapeFacts(dir string, out []byte) *EscapeFacts {
+	facts := &EscapeFacts{
+		allocs:   make(map[escapePos]EscapeFact),
+		inlining: make(map[escapePos]InlineFact),
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		pos, msg, ok := splitCompilerLine(dir, scanner.Text())
+		if !ok || msg == "" || msg[0] == ' ' || strings.HasSuffix(msg, ":") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(msg, "can inline "):
+			fact := InlineFact{CanInline: true}
+			if _, after, found := strings.Cut(msg, " with cost "); found {
+				fact.Cost, _ = strconv.Atoi(strings.Fields(after)[0])
+			}
+			facts.inlining[pos] = fact
+		case strings.HasPrefix(msg, "cannot inline "):
+			_, reason, _ := strings.Cut(msg, ": ")
+			facts.inlining[pos] = parseInlineReason(reason)
+		case strings.HasPrefix(msg, "moved to heap: "), strings.HasSuffix(msg, " escapes to heap"):
+			facts.allocs[pos] = EscapeFact{Heap: true, Message: msg}
+		case strings.HasSuffix(msg, " does not escape") && !strings.HasPrefix(msg, "... argument"):
+			if _, ok := facts.allocs[pos]; !ok {
+				facts.allocs[pos] = EscapeFact{Message: msg}
+			}
+		}
+	}
+	return facts
+}
+
+// splitCompilerLine splits "./file.go:line:col: message"
+func splitCompilerLine(dir, line string) (escapePos, string, bool) {
+	parts := strings.SplitN(line, ":", 4)
+	if len(parts) != 4 || !strings.HasSuffix(parts[0], ".go") {
+		return escapePos{}, "", false
+	}
+	lineNo, err1 := strconv.Atoi(parts[1])
+	column, err2 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil {
+		return escapePos{}, "", false
+	}
+	filename := parts[0]
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(dir, filename)
+	}
+	return escapePos{filename: filename, line: lineNo, column: column}, strings.TrimPrefix(parts[3], " "), true
+}
+
+// parseInlineReason parses reasons such as
+// "function too complex: cost 118 exceeds budget 80"
+func parseInlineReason(reason string) InlineFact {
+	fact := InlineFact{Reason: reason}
+	fields := strings.Fields(reason)
+	for i := 0; i+1 < len(fields); i++ {
+		switch fields[i] {
+		case "cost":
+			fact.Cost, _ = strconv.Atoi(fields[i+1])
+		case "budget":
+			fact.Budget, _ = strconv.Atoi(fields[i+1])
+		}
+	}
+	return fact
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const escapeTestSource = `package main
+
+type T struct{ v int }
+
+func grow(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		switch {
+		case i%3 == 0:
+			total += i * 2
+		case i%5 == 0:
+			total -= i
+		case i%7 == 0:
+			total *= 2
+		case i%11 == 0:
+			total /= 2
+		case i%13 == 0:
+			total += 13
+		case i%17 == 0:
+			total -= 17
+		case i%19 == 0:
+			total += 19
+		case i%23 == 0:
+			total -= 23
+		case i%29 == 0:
+			total += 29
+		case i%31 == 0:
+			total -= 31
+		default:
+			total++
+		}
+	}
+	return total
+}
+
+func main() {
+	var keep []*T
+	for i := 0; i < 3; i++ {
+		m := make(map[int]int)
+		m[i] = grow(i)
+		keep = append(keep, &T{v: m[i]})
+	}
+	_ = keep
+}
+`
+
+const escapeTestOutput = `# example.com/esc
+./main.go:5:6: cannot inline grow: function too complex: cost 116 exceeds budget 80
+./main.go:36:6: cannot inline main: function too complex: cost 99 exceeds budget 80
+./main.go:41:23: &T{...} escapes to heap in main:
+./main.go:41:23:   flow: {heap} = &{storage for &T{...}}:
+./main.go:41:23:     from &T{...} (spill) at ./main.go:41:23
+./main.go:39:12: make(map[int]int) does not escape
+./main.go:41:23: &T{...} escapes to heap
+./main.go:41:16: append does not escape
+`
+
+func TestParseEscapeFacts(t *testing.T) {
+	dir := t.TempDir()
+	facts := parseEscapeFacts(dir, []byte(escapeTestOutput))
+
+	filename := filepath.Join(dir, "main.go")
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, escapeTestSource, 0)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	escapes := facts.Attach(fset, file)
+
+	got := make(map[string]EscapeFact)
+	for expr, fact := range escapes.Allocs {
+		got[types.ExprString(expr)] = fact
+	}
+	if fact, ok := got["&T{…}"]; !ok || !fact.Heap {
+		t.Errorf("&T{...} fact = %+v, %v; want heap", fact, ok)
+	}
+	if fact, ok := got["make(map[int]int)"]; !ok || fact.Heap {
+		t.Errorf("make fact = %+v, %v; want stack", fact, ok)
+	}
+	if len(got) != 3 {
+		t.Errorf("attached %d allocation facts, want 3: %v", len(got), got)
+	}
+
+	for fn, fact := range escapes.Inlining {
+		switch fn.Name.Name {
+		case "grow":
+			want := InlineFact{Cost: 116, Budget: 80, Reason: "function too complex: cost 116 exceeds budget 80"}
+			if fact != want {
+				t.Errorf("grow inlining = %+v, want %+v", fact, want)
+			}
+		case "main":
+			if fact.CanInline || fact.Cost != 99 {
+				t.Errorf("main inlining = %+v", fact)
+			}
+		}
+	}
+	if len(escapes.Inlining) != 2 {
+		t.Errorf("attached %d inlining facts, want 2", len(escapes.Inlining))
+	}
+}
+
+func TestEscapeAnalysisConfirmsAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a package with the Go toolchain")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/esc\n\ngo 1.21\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	filename := filepath.Join(dir, "main.go")
+	if err := os.WriteFile(filename, []byte(escapeTestSource), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	s := &session{opts: Options{Escapes: NewEscapeAnalysis()}}
+	gcPressure, hotPath := NewGCPressureAnalyzer(), NewHotPathAnalyzer()
+	gcPressure.(sessionAnalyzer).useSession(s)
+	hotPath.(sessionAnalyzer).useSession(s)
+
+	var gc []string
+	for _, issue := range gcPressure.Analyze(file, fset) {
+		gc = append(gc, issue.Type.String()+": "+issue.Message)
+	}
+	// The map stays on the stack, the pointer kept in the slice does not
+	want := []string{"AllocInLoop: &T{...} escapes to heap on every iteration"}
+	if strings.Join(gc, "\n") != strings.Join(want, "\n") {
+		t.Errorf("GC pressure issues = %q, want %q", gc, want)
+	}
+
+	var inlining []*models.Issue
+	for _, issue := range hotPath.Analyze(file, fset) {
+		if issue.Type == models.IssuePreventsInlining {
+			inlining = append(inlining, issue)
+		}
+	}
+	if len(inlining) != 1 || !strings.HasPrefix(inlining[0].Message, "grow is called in a loop but cannot be inlined") {
+		t.Errorf("inlining issues = %v", inlining)
+	}
+}
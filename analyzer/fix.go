@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// ApplyFixes applies the suggested fixes attached to issues to sources, which
+// map filenames to their content, and returns the gofmt'ed content of every
+// edited file together with the issues whose fixes were applied.
+// Fixes are taken in source order; a fix whose edits overlap an already accepted
+// fix or touch a file missing from sources is skipped as a whole, and a fix
+// identical to an accepted one is merged into it. Imports needed by the fixes
+// are added and imports left unused by them are removed.
+func ApplyFixes(sources map[string][]byte, issues []*models.Issue) (map[string][]byte, []*models.Issue, error) {
+	resolve := fileResolver(sources)
+	candidates := make([]*models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue != nil && issue.Fix != nil && len(issue.Fix.Edits) > 0 {
+			candidates = append(candidates, issue)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		fi, fj := resolve(issueFilename(candidates[i])), resolve(issueFilename(candidates[j]))
+		if fi != fj {
+			return fi < fj
+		}
+		return candidates[i].Fix.Edits[0].Start < candidates[j].Fix.Edits[0].Start
+	})
+
+	accepted := make(map[string][]models.TextEdit)
+	imports := make(map[string][]string)
+	applied := make([]*models.Issue, 0, len(candidates))
+	for _, issue := range candidates {
+		home := resolve(issueFilename(issue))
+		byFile := make(map[string][]models.TextEdit, 1)
+		for _, edit := range issue.Fix.Edits {
+			filename := home
+			if edit.Filename != "" {
+				filename = resolve(edit.Filename)
+			}
+			edit.Filename = ""
+			byFile[filename] = append(byFile[filename], edit)
+		}
+
+		fresh := make(map[string][]models.TextEdit, len(byFile))
+		ok := true
+		for filename, edits := range byFile {
+			src, found := sources[filename]
+			if !found {
+				ok = false
+				break
+			}
+			if fresh[filename], ok = composeEdits(accepted[filename], edits, len(src)); !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		for filename, edits := range fresh {
+			accepted[filename] = append(accepted[filename], edits...)
+		}
+		if len(issue.Fix.Imports) > 0 {
+			imports[home] = append(imports[home], issue.Fix.Imports...)
+			if _, edited := accepted[home]; !edited {
+				accepted[home] = nil
+			}
+		}
+		applied = append(applied, issue)
+	}
+
+	fixed := make(map[string][]byte, len(accepted))
+	for filename, edits := range accepted {
+		src := sources[filename]
+		out, err := finishFixedSource(filename, src, applyEdits(src, edits), imports[filename])
+		if err != nil {
+			return nil, nil, err
+		}
+		fixed[filename] = out
+	}
+	return fixed, applied, nil
+}
+
+func issueFilename(issue *models.Issue) string {
+	if issue.Position.Filename != "" {
+		return issue.Position.Filename
+	}
+	return issue.File
+}
+
+// fileResolver maps a filename to the key naming the same file in sources,
+// so that relative and absolute spellings of a path agree
+func fileResolver(sources map[string][]byte) func(string) string {
+	keys := make(map[string]string, len(sources))
+	for name := range sources {
+		if abs, err := filepath.Abs(name); err == nil {
+			keys[abs] = name
+		}
+	}
+	return func(name string) string {
+		if _, ok := sources[name]; ok {
+			return name
+		}
+		if abs, err := filepath.Abs(name); err == nil {
+			if key, ok := keys[abs]; ok {
+				return key
+			}
+		}
+		return name
+	}
+}
+
+// composeEdits returns the edits of a fix that are not yet accepted, or false
+// when the fix conflicts with accepted edits or falls outside the source
+func composeEdits(accepted, edits []models.TextEdit, size int) ([]models.TextEdit, bool) {
+	fresh := make([]models.TextEdit, 0, len(edits))
+	for _, edit := range edits {
+		if edit.Start < 0 || edit.End < edit.Start || edit.End > size {
+			return nil, false
+		}
+		duplicate := false
+		for _, prev := range accepted {
+			if prev == edit {
+				duplicate = true
+				break
+			}
+			if prev.Overlaps(edit) {
+				return nil, false
+			}
+		}
+		for _, prev := range fresh {
+			if prev.Overlaps(edit) {
+				return nil, false
+			}
+		}
+		if !duplicate {
+			fresh = append(fresh, edit)
+		}
+	}
+	return fresh, true
+}
+
+// applyEdits rewrites src with non-overlapping edits. Insertions at the same
+// offset keep the order in which they were accepted.
+func applyEdits(src []byte, edits []models.TextEdit) []byte {
+	ordered := make([]models.TextEdit, len(edits))
+	copy(ordered, edits)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Start != ordered[j].Start {
+			return ordered[i].Start < ordered[j].Start
+		}
+		return ordered[i].End < ordered[j].End
+	})
+
+	var buf bytes.Buffer
+	buf.Grow(len(src))
+	prev := 0
+	for _, edit := range ordered {
+		buf.Write(src[prev:edit.Start])
+		buf.WriteString(edit.NewText)
+		prev = edit.End
+	}
+	buf.Write(src[prev:])
+	return buf.Bytes()
+}
+
+// finishFixedSource fixes up imports of the edited source and formats it
+func finishFixedSource(filename string, original, edited []byte, imports []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	before, err := parser.ParseFile(fset, filename, original, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse original source: %w", err)
+	}
+	file, err := parser.ParseFile(fset, filename, edited, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("fixes produced invalid code: %w", err)
+	}
+
+	for _, spec := range before.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || spec.Name != nil {
+			continue
+		}
+		if astutil.UsesImport(before, path) && !astutil.UsesImport(file, path) {
+			astutil.DeleteImport(fset, file, path)
+		}
+	}
+	for _, path := range imports {
+		astutil.AddImport(fset, file, path)
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, fmt.Errorf("format fixed source: %w", err)
+	}
+	return buf.Bytes(), nil
+}
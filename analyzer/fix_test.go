@@ -0,0 +1,306 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// fixSource runs the analyzers over code parsed as filename and returns the
+// source after applying every suggested fix
+func fixSource(t *testing.T, filename, code string, analyzers ...Analyzer) string {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, code, parser.ParseComments)
+	require.NoError(t, err)
+
+	var issues []*models.Issue
+	for _, a := range analyzers {
+		issues = append(issues, a.Analyze(file, fset)...)
+	}
+	for _, issue := range issues {
+		require.Equal(t, issue.Fix != nil, issue.CanBeFixed)
+	}
+
+	result, _, err := ApplyFixes(map[string][]byte{filename: []byte(code)}, issues)
+	require.NoError(t, err)
+	if fixed, ok := result[filename]; ok {
+		return string(fixed)
+	}
+	return code
+}
+
+func TestApplyFixesComposesEdits(t *testing.T) {
+	src := []byte("package main\n\nvar a, b = 1, 2\n")
+	sources := map[string][]byte{"main.go": src}
+	issues := []*models.Issue{
+		{Fix: &models.SuggestedFix{Edits: []models.TextEdit{{Start: 18, End: 19, NewText: "x"}}}},
+		{Fix: &models.SuggestedFix{Edits: []models.TextEdit{{Start: 18, End: 22, NewText: "y, z"}}}},
+		{Fix: &models.SuggestedFix{Edits: []models.TextEdit{{Start: 18, End: 19, NewText: "x"}}}},
+		{Fix: &models.SuggestedFix{Edits: []models.TextEdit{{Start: 21, End: 22, NewText: "c"}}}},
+		{Message: "no fix"},
+	}
+
+	for _, issue := range issues {
+		issue.Position.Filename = "main.go"
+	}
+
+	result, applied, err := ApplyFixes(sources, issues)
+	require.NoError(t, err)
+	require.Equal(t, "package main\n\nvar x, c = 1, 2\n", string(result["main.go"]))
+	require.Equal(t, []*models.Issue{issues[0], issues[2], issues[3]}, applied)
+}
+
+func TestApplyFixesRejectsInvalidResult(t *testing.T) {
+	sources := map[string][]byte{"main.go": []byte("package main\n")}
+	issues := []*models.Issue{{
+		Position: token.Position{Filename: "main.go"},
+		Fix:      &models.SuggestedFix{Edits: []models.TextEdit{{Start: 0, End: 7, NewText: "func"}}},
+	}}
+
+	_, _, err := ApplyFixes(sources, issues)
+	require.Error(t, err)
+}
+
+func TestDeferInLoopFix(t *testing.T) {
+	code := `package main
+
+import "os"
+
+func main() {
+	for _, name := range os.Args {
+		f, _ := os.Open(name)
+		defer f.Close()
+	}
+	for _, name := range os.Args {
+		if name == "" {
+			continue
+		}
+		f, _ := os.Open(name)
+		defer f.Close()
+	}
+}
+`
+	want := `package main
+
+import "os"
+
+func main() {
+	for _, name := range os.Args {
+		func() {
+			f, _ := os.Open(name)
+			defer f.Close()
+		}()
+	}
+	for _, name := range os.Args {
+		if name == "" {
+			continue
+		}
+		f, _ := os.Open(name)
+		defer f.Close()
+	}
+}
+`
+	require.Equal(t, want, fixSource(t, "main.go", code, NewLoopAnalyzer()))
+	// The defer the fix scopes to a function literal is not reported again
+	require.Equal(t, want, fixSource(t, "main.go", want, NewLoopAnalyzer()))
+}
+
+func TestRegexCompileInLoopFix(t *testing.T) {
+	code := `package main
+
+import "regexp"
+
+// count counts numeric lines
+func count(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		if regexp.MustCompile(` + "`^\\d+$`" + `).MatchString(l) {
+			n++
+		}
+	}
+	return n
+}
+`
+	want := `package main
+
+import "regexp"
+
+var countRegexp = regexp.MustCompile(` + "`^\\d+$`" + `)
+
+// count counts numeric lines
+func count(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		if countRegexp.MatchString(l) {
+			n++
+		}
+	}
+	return n
+}
+`
+	require.Equal(t, want, fixSource(t, "main.go", code, NewRegexAnalyzer()))
+}
+
+func TestRegexCompileInLoopFixNamesAreUnique(t *testing.T) {
+	code := `package main
+
+import "regexp"
+
+var mainRegexp = 1
+
+type parser struct{}
+
+func (p *parser) parse(lines []string) {
+	for _, l := range lines {
+		regexp.MustCompile(` + "`a`" + `).MatchString(l)
+	}
+}
+
+func main() {
+	for _, l := range []string{"a"} {
+		regexp.MustCompile(` + "`a`" + `).MatchString(l)
+		regexp.MustCompile(` + "`b`" + `).MatchString(l)
+	}
+}
+`
+	want := `package main
+
+import "regexp"
+
+var mainRegexp = 1
+
+type parser struct{}
+
+var parserParseRegexp = regexp.MustCompile(` + "`a`" + `)
+
+func (p *parser) parse(lines []string) {
+	for _, l := range lines {
+		parserParseRegexp.MatchString(l)
+	}
+}
+
+var mainRegexp2 = regexp.MustCompile(` + "`a`" + `)
+
+var mainRegexp3 = regexp.MustCompile(` + "`b`" + `)
+
+func main() {
+	for _, l := range []string{"a"} {
+		mainRegexp2.MatchString(l)
+		mainRegexp3.MatchString(l)
+	}
+}
+`
+	require.Equal(t, want, fixSource(t, "main.go", code, NewRegexAnalyzer()))
+}
+
+func TestSprintfConcatenationFix(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+func label(id int, ok bool, a, b string) string {
+	return fmt.Sprintf("%d", id) + fmt.Sprintf("%t", ok) + fmt.Sprintf("%s%s", a, b)[1:]
+}
+`
+	want := `package main
+
+import "strconv"
+
+func label(id int, ok bool, a, b string) string {
+	return strconv.Itoa(id) + strconv.FormatBool(ok) + (a + b)[1:]
+}
+`
+	require.Equal(t, want, fixSource(t, "main.go", code, NewAPIMisuseAnalyzer()))
+}
+
+func TestStructLayoutFix(t *testing.T) {
+	code := `package main
+
+type Record struct {
+	Active bool // is active
+	ID     int64 ` + "`json:\"id\"`" + `
+	Flag   bool
+	Count  int64
+}
+
+var keyed = Record{ID: 1}
+`
+	want := `package main
+
+type Record struct {
+	ID     int64 ` + "`json:\"id\"`" + `
+	Count  int64
+	Active bool // is active
+	Flag   bool
+}
+
+var keyed = Record{ID: 1}
+`
+	// The reorder moves source text, so the file has to exist on disk
+	filename := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(filename, []byte(code), 0o644))
+	require.Equal(t, want, fixSource(t, filename, code, NewStructLayoutAnalyzer()))
+
+	positional := code + "\nvar positional = []Record{{true, 1, false, 2}}\n"
+	require.NoError(t, os.WriteFile(filename, []byte(positional), 0o644))
+	require.Equal(t,
+		want+"\nvar positional = []Record{{1, 2, true, false}}\n",
+		fixSource(t, filename, positional, NewStructLayoutAnalyzer()),
+	)
+}
+
+func TestStructLayoutFixKeepsPointersFirst(t *testing.T) {
+	code := `package main
+
+type Node struct {
+	Flag  bool
+	Count int64
+	Next  *Node
+	Seen  bool
+}
+`
+	want := `package main
+
+type Node struct {
+	Next  *Node
+	Count int64
+	Flag  bool
+	Seen  bool
+}
+`
+	filename := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(filename, []byte(code), 0o644))
+	require.Equal(t, want, fixSource(t, filename, code, NewStructLayoutAnalyzer()))
+}
+
+func TestStructLayoutFixUpdatesPackageLiterals(t *testing.T) {
+	t.Setenv("GOARCH", "amd64")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/demo\n\ngo 1.21\n"), 0o644))
+	code := "package demo\n\ntype Pair struct {\n\tA bool\n\tB int64\n\tC bool\n}\n"
+	other := "package demo\n\nvar p = Pair{true, 2, false}\n"
+	filename := filepath.Join(dir, "pair.go")
+	otherName := filepath.Join(dir, "use.go")
+	require.NoError(t, os.WriteFile(filename, []byte(code), 0o644))
+	require.NoError(t, os.WriteFile(otherName, []byte(other), 0o644))
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, code, parser.ParseComments)
+	require.NoError(t, err)
+	issues := NewStructLayoutAnalyzer().Analyze(file, fset)
+	require.Len(t, issues, 1)
+	require.Equal(t, "Struct Pair wastes 14 bytes due to padding (24 → 16 bytes on amd64)", issues[0].Message)
+
+	result, applied, err := ApplyFixes(map[string][]byte{filename: []byte(code), otherName: []byte(other)}, issues)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	require.Equal(t, "package demo\n\ntype Pair struct {\n\tB int64\n\tA bool\n\tC bool\n}\n", string(result[filename]))
+	require.Equal(t, "package demo\n\nvar p = Pair{2, true, false}\n", string(result[otherName]))
+}
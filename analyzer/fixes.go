@@ -0,0 +1,623 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/astutil"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// offsetOf returns the byte offset of pos in its file, or -1 if it is unknown
+func offsetOf(fset *token.FileSet, pos token.Pos) int {
+	tf := fset.File(pos)
+	if tf == nil {
+		return -1
+	}
+	return tf.Offset(pos)
+}
+
+// deferLoopFix wraps the loop body in a function literal so that deferred calls
+// run at the end of every iteration. Bodies that return, jump or break out of
+// the loop are left alone because the closure would change their meaning.
+func deferLoopFix(fset *token.FileSet, body *ast.BlockStmt) *models.SuggestedFix {
+	if body == nil || loopBodyEscapes(body) {
+		return nil
+	}
+
+	open, closing := offsetOf(fset, body.Lbrace), offsetOf(fset, body.Rbrace)
+	if open < 0 || closing < 0 {
+		return nil
+	}
+
+	return &models.SuggestedFix{
+		Message: "Wrap the loop body in a closure so defers run every iteration",
+		Edits: []models.TextEdit{
+			{Start: open + 1, End: open + 1, NewText: "\nfunc() {"},
+			{Start: closing, End: closing, NewText: "}()\n"},
+		},
+	}
+}
+
+// loopBodyEscapes reports whether body contains control flow that leaves the
+// loop iteration: return, goto, labeled branches, or break/continue that bind
+// to the loop itself.
+func loopBodyEscapes(body *ast.BlockStmt) bool {
+	escapes := false
+	var walk func(n ast.Node, inLoop, inBreakable bool)
+	walk = func(n ast.Node, inLoop, inBreakable bool) {
+		ast.Inspect(n, func(node ast.Node) bool {
+			if escapes || node == nil {
+				return false
+			}
+			switch stmt := node.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				escapes = true
+			case *ast.BranchStmt:
+				switch {
+				case stmt.Label != nil || stmt.Tok == token.GOTO:
+					escapes = true
+				case stmt.Tok == token.BREAK && !inBreakable:
+					escapes = true
+				case stmt.Tok == token.CONTINUE && !inLoop:
+					escapes = true
+				}
+			case *ast.ForStmt:
+				walk(stmt.Body, true, true)
+				return false
+			case *ast.RangeStmt:
+				walk(stmt.Body, true, true)
+				return false
+			case *ast.SwitchStmt:
+				walk(stmt.Body, inLoop, true)
+				return false
+			case *ast.TypeSwitchStmt:
+				walk(stmt.Body, inLoop, true)
+				return false
+			case *ast.SelectStmt:
+				walk(stmt.Body, inLoop, true)
+				return false
+			}
+			return !escapes
+		})
+	}
+	walk(body, false, false)
+	return escapes
+}
+
+// hoistRegexpFix moves regexp.MustCompile of a constant pattern to a package
+// variable declared before the enclosing top-level declaration. info, when
+// not nil, is the type information of the package of file and keeps the
+// variable name clear of every declaration in the package.
+// Both analyzers that report compiling in loops build the same fix, so the
+// fix engine merges their edits.
+func hoistRegexpFix(fset *token.FileSet, file *ast.File, info *types.Info, call *ast.CallExpr) *models.SuggestedFix {
+	if file == nil || !isHoistableRegexp(call) {
+		return nil
+	}
+
+	decl := enclosingDecl(file, call.Pos())
+	if decl == nil {
+		return nil
+	}
+
+	name := hoistedRegexpName(fset, file, info, call)
+	declStart := decl.Pos()
+	if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc != nil {
+		declStart = fn.Doc.Pos()
+	}
+	if gen, ok := decl.(*ast.GenDecl); ok && gen.Doc != nil {
+		declStart = gen.Doc.Pos()
+	}
+
+	insertAt := offsetOf(fset, declStart)
+	start, end := offsetOf(fset, call.Pos()), offsetOf(fset, call.End())
+	if name == "" || insertAt < 0 || start < 0 || end < 0 {
+		return nil
+	}
+
+	pattern := call.Args[0].(*ast.BasicLit).Value
+	return &models.SuggestedFix{
+		Message: "Compile the regexp once in package variable " + name,
+		Edits: []models.TextEdit{
+			{Start: insertAt, End: insertAt, NewText: "var " + name + " = regexp.MustCompile(" + pattern + ")\n\n"},
+			{Start: start, End: end, NewText: name},
+		},
+	}
+}
+
+func isHoistableRegexp(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != methodMustCompile || len(call.Args) != 1 {
+		return false
+	}
+	pkgIdent, ok := sel.X.(*ast.Ident)
+	if !ok || pkgIdent.Name != pkgRegexp {
+		return false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING
+}
+
+func enclosingDecl(file *ast.File, pos token.Pos) ast.Decl {
+	for _, decl := range file.Decls {
+		if decl.Pos() <= pos && pos < decl.End() {
+			return decl
+		}
+	}
+	return nil
+}
+
+// hoistedRegexpName returns the package variable call is hoisted to. Names
+// are handed out to every hoistable compile of the package in file and source
+// order, each the first free one of its declaration's base name, so the fixes
+// of one pass get distinct names whichever of them are applied. A name is
+// free when nothing in the package or the imports of its files declares it.
+func hoistedRegexpName(fset *token.FileSet, file *ast.File, info *types.Info, call *ast.CallExpr) string {
+	files := packageFiles(fset, file, info)
+	handedOut := make(map[string]bool)
+	taken := func(name string) bool {
+		if handedOut[name] {
+			return true
+		}
+		for _, f := range files {
+			var scope *types.Scope
+			if info != nil {
+				scope = info.Scopes[f]
+			}
+			if scope != nil {
+				// The file scope holds the imports; its parents the package
+				// and the universe
+				if _, obj := scope.LookupParent(name, token.NoPos); obj != nil {
+					return true
+				}
+			} else if f.Scope != nil && f.Scope.Lookup(name) != nil || importsName(f, name) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			base := hoistBaseName(decl) + "Regexp"
+			var found string
+			ast.Inspect(decl, func(n ast.Node) bool {
+				c, ok := n.(*ast.CallExpr)
+				if !ok || !isHoistableRegexp(c) {
+					return found == ""
+				}
+				name := base
+				for i := 2; taken(name); i++ {
+					name = base + strconv.Itoa(i)
+				}
+				handedOut[name] = true
+				if c == call {
+					found = name
+				}
+				return found == ""
+			})
+			if found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
+
+// packageFiles returns the files of the package of file sorted by name, or
+// just file without type information
+func packageFiles(fset *token.FileSet, file *ast.File, info *types.Info) []*ast.File {
+	var files []*ast.File
+	if info != nil {
+		for node := range info.Scopes {
+			if f, ok := node.(*ast.File); ok {
+				files = append(files, f)
+			}
+		}
+	}
+	if !slices.Contains(files, file) {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return fset.Position(files[i].Pos()).Filename < fset.Position(files[j].Pos()).Filename
+	})
+	return files
+}
+
+// hoistBaseName names the variables hoisted out of decl after the function,
+// the receiver type and method, or the first variable it declares
+func hoistBaseName(decl ast.Decl) string {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		if recv := receiverType(decl); recv != "" {
+			return lowerFirst(recv) + upperFirst(decl.Name.Name)
+		}
+		return lowerFirst(decl.Name.Name)
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			if vs, ok := spec.(*ast.ValueSpec); ok && len(vs.Names) > 0 && vs.Names[0].Name != "_" {
+				return lowerFirst(vs.Names[0].Name)
+			}
+		}
+	}
+	return "pattern"
+}
+
+// importsName reports whether file imports a package under name
+func importsName(file *ast.File, name string) bool {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		imported := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			imported = imp.Name.Name
+		}
+		if imported == name {
+			return true
+		}
+	}
+	return false
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// sprintfFix replaces fmt.Sprintf with a single verb by the matching strconv call,
+// or by plain concatenation for "%s%s" of two strings. It needs type information
+// to prove the conversion keeps the output identical.
+func sprintfFix(fset *token.FileSet, file *ast.File, info *types.Info, call *ast.CallExpr) *models.SuggestedFix {
+	if file == nil || info == nil || len(call.Args) < 2 {
+		return nil
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+
+	var replacement string
+	var imports []string
+	switch len(call.Args) {
+	case 2:
+		replacement = strconvReplacement(format, basicKind(info, call.Args[1]), exprText(fset, call.Args[1]))
+		if replacement == "" {
+			return nil
+		}
+		if strings.HasPrefix(replacement, "strconv.") {
+			imports = []string{"strconv"}
+		}
+	case 3:
+		if format != "%s%s" ||
+			basicKind(info, call.Args[1]) != types.String || basicKind(info, call.Args[2]) != types.String {
+			return nil
+		}
+		left, right := exprText(fset, call.Args[1]), exprText(fset, call.Args[2])
+		if left == "" || right == "" {
+			return nil
+		}
+		replacement = left + " + " + right
+	default:
+		return nil
+	}
+	if needsParens(file, call) && !strings.HasPrefix(replacement, "strconv.") {
+		replacement = "(" + replacement + ")"
+	}
+
+	start, end := offsetOf(fset, call.Pos()), offsetOf(fset, call.End())
+	if start < 0 || end < 0 {
+		return nil
+	}
+	return &models.SuggestedFix{
+		Message: "Replace fmt.Sprintf with " + replacement,
+		Edits:   []models.TextEdit{{Start: start, End: end, NewText: replacement}},
+		Imports: imports,
+	}
+}
+
+// needsParens reports whether call is indexed or sliced, where a bare
+// expression replacing it would bind differently
+func needsParens(file *ast.File, call *ast.CallExpr) bool {
+	path, _ := astutil.PathEnclosingInterval(file, call.Pos(), call.End())
+	if len(path) < 2 {
+		return false
+	}
+	switch parent := path[1].(type) {
+	case *ast.IndexExpr:
+		return parent.X == call
+	case *ast.SliceExpr:
+		return parent.X == call
+	}
+	return false
+}
+
+func strconvReplacement(format string, kind types.BasicKind, arg string) string {
+	if arg == "" {
+		return ""
+	}
+	switch {
+	case kind == types.Int && (format == "%d" || format == "%v"):
+		return "strconv.Itoa(" + arg + ")"
+	case kind == types.Int64 && (format == "%d" || format == "%v"):
+		return "strconv.FormatInt(" + arg + ", 10)"
+	case kind == types.Uint64 && (format == "%d" || format == "%v"):
+		return "strconv.FormatUint(" + arg + ", 10)"
+	case (kind == types.Int8 || kind == types.Int16 || kind == types.Int32) && format == "%d":
+		return "strconv.FormatInt(int64(" + arg + "), 10)"
+	case (kind == types.Uint || kind == types.Uint8 || kind == types.Uint16 || kind == types.Uint32) && format == "%d":
+		return "strconv.FormatUint(uint64(" + arg + "), 10)"
+	case kind == types.Bool && (format == "%t" || format == "%v"):
+		return "strconv.FormatBool(" + arg + ")"
+	case kind == types.String && format == "%q":
+		return "strconv.Quote(" + arg + ")"
+	case kind == types.String && (format == "%s" || format == "%v"):
+		return arg
+	}
+	return ""
+}
+
+// basicKind returns the kind of an unnamed basic type, mapping untyped constants
+// to their default type. Named types report Invalid since they may implement
+// fmt.Stringer or fmt.Formatter.
+func basicKind(info *types.Info, expr ast.Expr) types.BasicKind {
+	t := info.TypeOf(expr)
+	if t == nil {
+		return types.Invalid
+	}
+	basic, ok := types.Unalias(t).(*types.Basic)
+	if !ok {
+		return types.Invalid
+	}
+	switch basic.Kind() {
+	case types.UntypedInt:
+		return types.Int
+	case types.UntypedBool:
+		return types.Bool
+	case types.UntypedString:
+		return types.String
+	}
+	return basic.Kind()
+}
+
+func exprText(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, expr); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// structReorderFix rewrites the fields of st in optimal order (see optimalOrder).
+// Field text, including doc comments, trailing comments and tags, is moved
+// verbatim, and positional composite literals of the type anywhere in the
+// type-checked package, read from overlay before the disk, are reordered to
+// match. Structs with free-standing comments between fields are left alone.
+func structReorderFix(
+	fset *token.FileSet, file *ast.File, src []byte, overlay Overlay, info *types.Info, sizes types.Sizes,
+	name *ast.Ident, st *ast.StructType,
+) *models.SuggestedFix {
+	if src == nil || st.Fields == nil || len(st.Fields.List) < 2 {
+		return nil
+	}
+
+	fields := st.Fields.List
+	fieldTypes := make([]types.Type, len(fields))
+	for i, field := range fields {
+		t := info.TypeOf(field.Type)
+		if t == nil {
+			return nil
+		}
+		fieldTypes[i] = t
+	}
+
+	tf := fset.File(st.Pos())
+	if tf == nil || hasFloatingComments(file, st) {
+		return nil
+	}
+
+	order := optimalOrder(fieldTypes, sizes)
+	before, _ := layoutOf(expandFields(fields, fieldTypes, nil), sizes)
+	after, _ := layoutOf(expandFields(fields, fieldTypes, order), sizes)
+	if sort.IntsAreSorted(order) || after >= before {
+		return nil
+	}
+
+	segments := make([]string, len(fields))
+	var start, end int
+	for i, field := range fields {
+		from, to := field.Pos(), field.End()
+		if field.Doc != nil {
+			from = field.Doc.Pos()
+		}
+		if field.Comment != nil {
+			to = field.Comment.End()
+		}
+		s, e := offsetOf(fset, from), offsetOf(fset, to)
+		if s < 0 || e > len(src) || s >= e || s < end {
+			return nil
+		}
+		if i == 0 {
+			start = s
+		}
+		end = e
+		segments[i] = string(src[s:e])
+	}
+
+	var sb strings.Builder
+	for i, idx := range order {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(segments[idx])
+	}
+
+	edits := []models.TextEdit{{Start: start, End: end, NewText: sb.String()}}
+	literalEdits, ok := positionalLiteralEdits(fset, tf, src, overlay, info, name, fields, order)
+	if !ok {
+		return nil
+	}
+	edits = append(edits, literalEdits...)
+	for i := range edits {
+		for j := i + 1; j < len(edits); j++ {
+			if edits[i].Filename == edits[j].Filename && edits[i].Overlaps(edits[j]) {
+				return nil
+			}
+		}
+	}
+
+	return &models.SuggestedFix{
+		Message: fmt.Sprintf("Reorder fields of %s (%d → %d bytes)", name.Name, before, after),
+		Edits:   edits,
+	}
+}
+
+// positionalLiteralEdits moves the values of positional composite literals of
+// the named type to follow the new field order. Literals in other files of the
+// package get edits for that file. It returns false when a literal cannot be
+// rewritten.
+func positionalLiteralEdits(
+	fset *token.FileSet, structFile *token.File, src []byte, overlay Overlay, info *types.Info,
+	name *ast.Ident, fields []*ast.Field, order []int,
+) ([]models.TextEdit, bool) {
+	obj := info.Defs[name]
+	if obj == nil {
+		return nil, false
+	}
+
+	// Values of a positional literal follow the fields one name at a time
+	first := make([]int, len(fields))
+	count := 0
+	for i, field := range fields {
+		first[i] = count
+		count += max(1, len(field.Names))
+	}
+	moved := make([]int, 0, count)
+	for _, idx := range order {
+		for j := range max(1, len(fields[idx].Names)) {
+			moved = append(moved, first[idx]+j)
+		}
+	}
+
+	literals := make([]*ast.CompositeLit, 0, 4)
+	for expr, tv := range info.Types {
+		lit, ok := expr.(*ast.CompositeLit)
+		if !ok || len(lit.Elts) == 0 || tv.Type == nil {
+			continue
+		}
+		if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+			continue
+		}
+		t := types.Unalias(tv.Type)
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if types.Identical(t, obj.Type()) {
+			literals = append(literals, lit)
+		}
+	}
+	sort.Slice(literals, func(i, j int) bool {
+		return literals[i].Pos() < literals[j].Pos()
+	})
+
+	sources := map[*token.File][]byte{structFile: src}
+	edits := make([]models.TextEdit, 0, len(literals)*count)
+	for _, lit := range literals {
+		if len(lit.Elts) != count {
+			return nil, false
+		}
+		tf := fset.File(lit.Pos())
+		if tf == nil {
+			return nil, false
+		}
+		litSrc, ok := sources[tf]
+		if !ok {
+			litSrc = readTokenFile(tf, overlay)
+			sources[tf] = litSrc
+		}
+		if litSrc == nil {
+			return nil, false
+		}
+		filename := ""
+		if tf != structFile {
+			filename = tf.Name()
+		}
+
+		for slot, idx := range moved {
+			if slot == idx {
+				continue
+			}
+			target, value := lit.Elts[slot], lit.Elts[idx]
+			vs, ve := tf.Offset(value.Pos()), tf.Offset(value.End())
+			edits = append(edits, models.TextEdit{
+				Filename: filename,
+				Start:    tf.Offset(target.Pos()),
+				End:      tf.Offset(target.End()),
+				NewText:  string(litSrc[vs:ve]),
+			})
+		}
+	}
+	return edits, true
+}
+
+// expandFields lists one type per field name, following order when it is set
+func expandFields(fields []*ast.Field, fieldTypes []types.Type, order []int) []types.Type {
+	expanded := make([]types.Type, 0, len(fields))
+	for i := range fields {
+		idx := i
+		if order != nil {
+			idx = order[i]
+		}
+		for range max(1, len(fields[idx].Names)) {
+			expanded = append(expanded, fieldTypes[idx])
+		}
+	}
+	return expanded
+}
+
+// hasFloatingComments reports comments inside the struct body that are not
+// attached to a field and would be lost when fields move
+func hasFloatingComments(file *ast.File, st *ast.StructType) bool {
+	attached := make(map[*ast.CommentGroup]bool, len(st.Fields.List)*2)
+	for _, field := range st.Fields.List {
+		attached[field.Doc] = true
+		attached[field.Comment] = true
+	}
+	for _, group := range file.Comments {
+		if group.Pos() > st.Fields.Opening && group.End() < st.Fields.Closing && !attached[group] {
+			return true
+		}
+	}
+	return false
+}
@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
+	"strings"
 
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
-type GCPressureAnalyzer struct{}
+type GCPressureAnalyzer struct {
+	inSession
+}
 
 func NewGCPressureAnalyzer() Analyzer {
 	return &GCPressureAnalyzer{}
@@ -32,6 +36,8 @@ func (gpa *GCPressureAnalyzer) Analyze(node interface{}, fset *token.FileSet) []
 	visitor := &gcVisitor{
 		fset:     fset,
 		filename: filename,
+		escapes:  gpa.session.escapeFacts(fset, file),
+		reported: make(map[ast.Expr]bool),
 		issues:   make([]*models.Issue, 0, 8),
 	}
 
@@ -42,6 +48,8 @@ func (gpa *GCPressureAnalyzer) Analyze(node interface{}, fset *token.FileSet) []
 type gcVisitor struct {
 	fset      *token.FileSet
 	filename  string
+	escapes   *FileEscapes // compiler decisions; nil without escape analysis
+	reported  map[ast.Expr]bool
 	loopDepth int
 	issues    []*models.Issue
 }
@@ -67,6 +75,9 @@ func (v *gcVisitor) Visit(node ast.Node) ast.Visitor {
 	case *ast.AssignStmt:
 		v.inspectAssign(n)
 	}
+	if expr, ok := node.(ast.Expr); ok && v.loopDepth > 0 {
+		v.inspectEscape(expr)
+	}
 	return v
 }
 
@@ -86,32 +97,46 @@ func (v *gcVisitor) inspectCall(call *ast.CallExpr) {
 
 	switch call.Args[0].(type) {
 	case *ast.MapType:
+		message, ok := v.confirmHeap(call, "Map allocation inside loop allocates each iteration")
+		if !ok {
+			return
+		}
 		pos := v.fset.Position(call.Pos())
+		end := v.fset.Position(call.End())
 		v.issues = append(
 			v.issues, &models.Issue{
 				File:       v.filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueHighGCPressure,
-				Severity:   models.SeverityLevelMedium,
-				Message:    "Map allocation inside loop allocates each iteration",
+				Severity:   models.IssueHighGCPressure.Severity(),
+				Message:    message,
 				Suggestion: "Move make(map) outside loop or reuse a cleared map",
 			},
 		)
 	case *ast.ArrayType:
 		if len(call.Args) >= 2 {
 			if size := literalIntValue(call.Args[1]); size >= 512 {
+				message, ok := v.confirmHeap(call, "Large slice allocation inside loop")
+				if !ok {
+					return
+				}
 				pos := v.fset.Position(call.Pos())
+				end := v.fset.Position(call.End())
 				v.issues = append(
 					v.issues, &models.Issue{
 						File:       v.filename,
 						Line:       pos.Line,
 						Column:     pos.Column,
+						EndLine:    end.Line,
+						EndColumn:  end.Column,
 						Position:   pos,
 						Type:       models.IssueHighGCPressure,
 						Severity:   models.SeverityLevelLow,
-						Message:    "Large slice allocation inside loop",
+						Message:    message,
 						Suggestion: "Consider reusing a buffer or allocating once",
 					},
 				)
@@ -120,6 +145,65 @@ func (v *gcVisitor) inspectCall(call *ast.CallExpr) {
 	}
 }
 
+// confirmHeap checks an allocation found in the syntax against the compiler's
+// decision. Allocations kept on the stack are dropped and heap ones say so.
+func (v *gcVisitor) confirmHeap(expr ast.Expr, message string) (string, bool) {
+	v.reported[expr] = true
+	if v.escapes == nil {
+		return message, true
+	}
+	fact, ok := v.escapes.Allocs[expr]
+	switch {
+	case !ok:
+		return message, true
+	case !fact.Heap:
+		return "", false
+	}
+	return message + " and escapes to heap", true
+}
+
+// inspectEscape reports the heap allocations the compiler found in loops that
+// the syntactic checks did not. Appends are left to the slice analyzer: they
+// only allocate when the slice grows.
+func (v *gcVisitor) inspectEscape(expr ast.Expr) {
+	if v.escapes == nil || v.reported[expr] {
+		return
+	}
+	if call, ok := expr.(*ast.CallExpr); ok {
+		if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "append" {
+			return
+		}
+	}
+	fact, ok := v.escapes.Allocs[expr]
+	if !ok || !fact.Heap {
+		return
+	}
+
+	message := fact.Message + " on every iteration"
+	if name, ok := strings.CutPrefix(fact.Message, "moved to heap: "); ok {
+		message = name + " is moved to heap on every iteration"
+	} else if strings.HasPrefix(fact.Message, "~") {
+		// Results of inlined calls are named ~r0, ~r1, ...
+		message = types.ExprString(expr) + " escapes to heap on every iteration"
+	}
+	pos := v.fset.Position(expr.Pos())
+	end := v.fset.Position(expr.End())
+	v.issues = append(
+		v.issues, &models.Issue{
+			File:       v.filename,
+			Line:       pos.Line,
+			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
+			Position:   pos,
+			Type:       models.IssueAllocInLoop,
+			Severity:   models.IssueAllocInLoop.Severity(),
+			Message:    message,
+			Suggestion: "Allocate once outside the loop, or keep the value from escaping: do not retain pointers to it or pass it as an interface",
+		},
+	)
+}
+
 func (v *gcVisitor) inspectAssign(assign *ast.AssignStmt) {
 	if v.loopDepth == 0 {
 		return
@@ -135,11 +219,14 @@ func (v *gcVisitor) inspectAssign(assign *ast.AssignStmt) {
 
 	if isStringLiteral(assign.Rhs[0]) {
 		pos := v.fset.Position(assign.Pos())
+		end := v.fset.Position(assign.End())
 		v.issues = append(
 			v.issues, &models.Issue{
 				File:       v.filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueHighGCPressure,
 				Severity:   models.SeverityLevelLow,
@@ -74,10 +74,13 @@ func (v *goroutineVisitor) inspectGo(stmt *ast.GoStmt) {
 
 	if v.loopDepth > 0 {
 		pos := v.fset.Position(stmt.Pos())
+		end := v.fset.Position(stmt.End())
 		v.issues = append(v.issues, &models.Issue{
 			File:       v.filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueGoroutinePerRequest,
 			Severity:   models.SeverityLevelMedium,
@@ -88,10 +91,13 @@ func (v *goroutineVisitor) inspectGo(stmt *ast.GoStmt) {
 
 	if capturesRangeVar(stmt) {
 		pos := v.fset.Position(stmt.Pos())
+		end := v.fset.Position(stmt.End())
 		v.issues = append(v.issues, &models.Issue{
 			File:       v.filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueGoroutineCapturesLoop,
 			Severity:   models.SeverityLevelHigh,
@@ -0,0 +1,469 @@
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// HotPathAnalyzer reports expensive calls in functions that run on a hot path
+// without being inside a loop themselves: functions called from a loop,
+// including goroutines started in one, functions serving HTTP requests, and
+// everything they call in turn. Calls lexically inside a loop are left to the
+// analyzers that already report them.
+//
+// The call graph is the static call graph of the package's shared SSA form,
+// so only calls to functions and methods declared in the package are
+// followed, not calls through interfaces and function values, and nothing is
+// reported when the package does not type-check or uses cgo.
+type HotPathAnalyzer struct {
+	inSession
+}
+
+func NewHotPathAnalyzer() Analyzer {
+	return &HotPathAnalyzer{}
+}
+
+func (ha *HotPathAnalyzer) Name() string {
+	return "Hot Path"
+}
+
+// hotPathOp is an expensive call reported when it runs on a hot path
+type hotPathOp struct {
+	pkg        string // import path
+	funcs      []string
+	issueType  models.IssueType
+	perRequest bool // also too expensive to run once per HTTP request
+	suggestion string
+}
+
+var hotPathOps = []hotPathOp{
+	{
+		pkg:        pkgRegexp,
+		funcs:      []string{"Compile", "MustCompile", "CompilePOSIX", "MustCompilePOSIX"},
+		issueType:  models.IssueRegexCompileInLoop,
+		perRequest: true,
+		suggestion: "Compile the expression once into a package-level variable",
+	},
+	{
+		pkg:        "time",
+		funcs:      []string{"Now"},
+		issueType:  models.IssueTimeNowInLoop,
+		suggestion: "Read the clock once in the caller and pass the time down",
+	},
+	{
+		pkg:        "encoding/json",
+		funcs:      []string{"Marshal", "MarshalIndent", "Unmarshal"},
+		issueType:  models.IssueJSONMarshalInLoop,
+		suggestion: "Reuse a json.Encoder or json.Decoder, or serialize the batch once in the caller",
+	},
+	{
+		pkg:        "reflect",
+		funcs:      []string{"TypeOf", "ValueOf", "DeepEqual"},
+		issueType:  models.IssueReflectionInLoop,
+		suggestion: "Resolve the reflection once in the caller, or use a type switch or generics",
+	},
+}
+
+// hotPath explains why a function is hot: the root that makes it so and the
+// functions called from there, ending with the function itself
+type hotPath struct {
+	origin     string
+	funcs      []string
+	perRequest bool // reached from an HTTP handler rather than a loop
+}
+
+func (p hotPath) String() string {
+	return strings.Join(append([]string{p.origin}, p.funcs...), " → ")
+}
+
+func (ha *HotPathAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
+	file, ok := node.(*ast.File)
+	if !ok || !file.Pos().IsValid() {
+		return nil
+	}
+	filename := fset.Position(file.Pos()).Filename
+	if strings.HasSuffix(filename, "_test.go") {
+		// Benchmarks loop over everything they call
+		return nil
+	}
+
+	s := ha.session.ssaOf(fset, file)
+	if s == nil {
+		return nil
+	}
+	hot := packageHotPaths(s)
+	if len(hot) == 0 {
+		return nil
+	}
+
+	imports := importNames(file)
+	escapes := ha.session.escapeFacts(fset, file)
+	var issues []*models.Issue
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		path, ok := hot[funcKey(fn)]
+		if !ok {
+			continue
+		}
+		if issue := checkHotInlining(fset, fn, path, escapes); issue != nil {
+			issues = append(issues, issue)
+		}
+		memoized := memoizedCalls(fn.Body)
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ForStmt, *ast.RangeStmt:
+				return false
+			case *ast.CallExpr:
+				if memoized[n] {
+					return true
+				}
+				if op, name := matchHotPathOp(n, imports); op != nil && (op.perRequest || !path.perRequest) {
+					issues = append(issues, newHotPathIssue(fset, n, op, name, path))
+				}
+			}
+			return true
+		})
+	}
+	return issues
+}
+
+// checkHotInlining reports a function called directly from a loop that the
+// compiler will not inline because it is slightly over the inlining budget.
+// Functions at more than twice the budget are rarely worth trimming.
+func checkHotInlining(fset *token.FileSet, fn *ast.FuncDecl, path hotPath, escapes *FileEscapes) *models.Issue {
+	if escapes == nil || path.perRequest || len(path.funcs) != 1 {
+		return nil
+	}
+	fact, ok := escapes.Inlining[fn]
+	if !ok || fact.CanInline || fact.Budget == 0 || fact.Cost > 2*fact.Budget {
+		return nil
+	}
+
+	pos := fset.Position(fn.Name.Pos())
+	end := fset.Position(fn.Name.End())
+	return &models.Issue{
+		File:      pos.Filename,
+		Line:      pos.Line,
+		Column:    pos.Column,
+		EndLine:   end.Line,
+		EndColumn: end.Column,
+		Position:  pos,
+		Type:      models.IssuePreventsInlining,
+		Severity:  models.IssuePreventsInlining.Severity(),
+		Message: fmt.Sprintf(
+			"%s is called in a loop but cannot be inlined: cost %d exceeds budget %d (%s)",
+			funcKey(fn), fact.Cost, fact.Budget, path,
+		),
+		Suggestion: "Move rarely taken branches into a separate function so the rest fits the inlining budget",
+	}
+}
+
+// memoizedCalls returns the calls in body whose results are stored in a map or
+// sync.Map, as caches that compute a value once per key do
+func memoizedCalls(body *ast.BlockStmt) map[*ast.CallExpr]bool {
+	memoized := make(map[*ast.CallExpr]bool)
+	storedVars := make(map[string]bool)
+	store := func(value ast.Expr) {
+		switch v := value.(type) {
+		case *ast.Ident:
+			storedVars[v.Name] = true
+		case *ast.CallExpr:
+			memoized[v] = true
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if _, ok := lhs.(*ast.IndexExpr); ok && i < len(n.Rhs) {
+					store(n.Rhs[i])
+				}
+			}
+		case *ast.CallExpr:
+			if sel, ok := n.Fun.(*ast.SelectorExpr); ok && len(n.Args) == 2 &&
+				(sel.Sel.Name == "Store" || sel.Sel.Name == "LoadOrStore") {
+				store(n.Args[1])
+			}
+		}
+		return true
+	})
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Rhs) == 1 {
+			call, isCall := assign.Rhs[0].(*ast.CallExpr)
+			ident, isIdent := assign.Lhs[0].(*ast.Ident)
+			if isCall && isIdent && storedVars[ident.Name] {
+				memoized[call] = true
+			}
+		}
+		return true
+	})
+	return memoized
+}
+
+func matchHotPathOp(call *ast.CallExpr, imports map[string]string) (*hotPathOp, string) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, ""
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return nil, ""
+	}
+	path, ok := imports[pkg.Name]
+	if !ok {
+		return nil, ""
+	}
+	for i := range hotPathOps {
+		op := &hotPathOps[i]
+		if op.pkg == path && slices.Contains(op.funcs, sel.Sel.Name) {
+			return op, pkg.Name + "." + sel.Sel.Name
+		}
+	}
+	return nil, ""
+}
+
+func newHotPathIssue(fset *token.FileSet, call *ast.CallExpr, op *hotPathOp, name string, path hotPath) *models.Issue {
+	pos := fset.Position(call.Pos())
+	end := fset.Position(call.End())
+	return &models.Issue{
+		File:       pos.Filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
+		Position:   pos,
+		Type:       op.issueType,
+		Severity:   op.issueType.Severity(),
+		Message:    fmt.Sprintf("%s runs on a hot path: %s", name, path),
+		Suggestion: op.suggestion,
+		WhyBad:     "The call is one function away from the loop or handler that repeats it, so it runs just as often as if it were written there",
+	}
+}
+
+// packageHotPaths returns the hot functions of the package of s, keyed by
+// funcKey. They are found once per SSA form and shared by its files.
+func packageHotPaths(s *SSA) map[string]hotPath {
+	return packageFact(s, "hotpath", func() map[string]hotPath {
+		return buildHotPaths(s)
+	})
+}
+
+// hotCall is a call from one function of the package to another
+type hotCall struct {
+	callee string
+	inLoop bool
+	pos    token.Pos
+}
+
+// hotFunc is a function of the package with the calls it makes, in order
+type hotFunc struct {
+	key     string
+	calls   []hotCall
+	handler bool
+}
+
+// buildHotPaths finds the functions called from loops and HTTP handlers and
+// propagates their hotness to everything they call, keeping the shortest path
+func buildHotPaths(s *SSA) map[string]hotPath {
+	decls := make([]*ssa.Function, 0, len(s.index.decls))
+	for _, fn := range s.index.decls {
+		decls = append(decls, fn)
+	}
+	slices.SortFunc(decls, func(a, b *ssa.Function) int {
+		pa, pb := s.Fset.Position(a.Pos()), s.Fset.Position(b.Pos())
+		return cmp.Or(strings.Compare(pa.Filename, pb.Filename), cmp.Compare(pa.Offset, pb.Offset))
+	})
+
+	var funcs []*hotFunc
+	byKey := make(map[string]*hotFunc, len(decls))
+	for _, fn := range decls {
+		key := hotFuncKey(s, fn)
+		if key == "" || fn.Blocks == nil {
+			continue
+		}
+		f := &hotFunc{key: key, handler: servesHTTP(fn.Signature)}
+		collectCalls(s, fn, false, &f.calls)
+		sort.SliceStable(f.calls, func(i, j int) bool { return f.calls[i].pos < f.calls[j].pos })
+		funcs = append(funcs, f)
+		byKey[f.key] = f
+	}
+
+	hot := make(map[string]hotPath)
+	var queue []string
+	mark := func(key string, path hotPath) {
+		if _, ok := hot[key]; ok {
+			return
+		}
+		hot[key] = path
+		queue = append(queue, key)
+	}
+	propagate := func() {
+		for len(queue) > 0 {
+			key := queue[0]
+			queue = queue[1:]
+			path := hot[key]
+			for _, call := range byKey[key].calls {
+				next := path
+				next.funcs = append(append([]string(nil), path.funcs...), call.callee)
+				mark(call.callee, next)
+			}
+		}
+	}
+
+	// Loops come first so a function reached both ways reports everything
+	for _, f := range funcs {
+		for _, call := range f.calls {
+			if call.inLoop {
+				pos := s.Fset.Position(call.pos)
+				origin := fmt.Sprintf("loop in %s (%s:%d)", f.key, filepath.Base(pos.Filename), pos.Line)
+				mark(call.callee, hotPath{origin: origin, funcs: []string{call.callee}})
+			}
+		}
+	}
+	propagate()
+	for _, f := range funcs {
+		if f.handler {
+			mark(f.key, hotPath{origin: "HTTP handler " + f.key, perRequest: true})
+		}
+	}
+	propagate()
+	return hot
+}
+
+// collectCalls appends the static calls fn and its closures make to
+// functions declared in the package. A call repeats when it sits in a loop
+// or in a closure created in one, like the body of a goroutine started in a
+// loop.
+func collectCalls(s *SSA, fn *ssa.Function, inLoop bool, calls *[]hotCall) {
+	for _, edge := range s.callSites(fn) {
+		if callee := hotFuncKey(s, edge.Callee.Func); callee != "" {
+			repeats := inLoop || s.loopOf(edge.Site.Block()) != nil
+			*calls = append(*calls, hotCall{callee: callee, inLoop: repeats, pos: edge.Site.Pos()})
+		}
+	}
+	for _, anon := range fn.AnonFuncs {
+		collectCalls(s, anon, inLoop || createdInLoop(s, anon), calls)
+	}
+}
+
+// createdInLoop reports whether the closure anon is created or called in a
+// loop of the function containing it
+func createdInLoop(s *SSA, anon *ssa.Function) bool {
+	refs := anon.Referrers()
+	if refs == nil {
+		return false
+	}
+	for _, instr := range *refs {
+		if b := instr.Block(); b != nil && s.loopOf(b) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hotFuncKey returns the funcKey of the package function or method fn is, or
+// of its generic origin, and "" for closures, wrappers and other packages
+func hotFuncKey(s *SSA, fn *ssa.Function) string {
+	if origin := fn.Origin(); origin != nil {
+		fn = origin
+	}
+	if fn.Pkg != s.Package || fn.Parent() != nil {
+		return ""
+	}
+	decl, ok := fn.Syntax().(*ast.FuncDecl)
+	if !ok {
+		return ""
+	}
+	return funcKey(decl)
+}
+
+// servesHTTP reports whether sig takes an http.ResponseWriter and an
+// *http.Request, as handler functions and ServeHTTP methods do
+func servesHTTP(sig *types.Signature) bool {
+	var writer, request bool
+	for i := 0; i < sig.Params().Len(); i++ {
+		t := sig.Params().At(i).Type()
+		writer = writer || isHTTPNamed(t, "ResponseWriter")
+		if ptr, ok := t.(*types.Pointer); ok {
+			request = request || isHTTPNamed(ptr.Elem(), "Request")
+		}
+	}
+	return writer && request
+}
+
+func isHTTPNamed(t types.Type, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == name
+}
+
+// funcKey names a function declaration as Name or Type.Name
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil {
+		return fn.Name.Name
+	}
+	return receiverType(fn) + "." + fn.Name.Name
+}
+
+func receiverType(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// isHTTPHandler reports whether fn serves HTTP requests, which handler
+// functions and ServeHTTP methods do by taking an http.ResponseWriter and an
+// *http.Request
+func isHTTPHandler(fn *ast.FuncDecl, imports map[string]string) bool {
+	var writer, request bool
+	for _, field := range fn.Type.Params.List {
+		switch t := field.Type.(type) {
+		case *ast.SelectorExpr:
+			writer = writer || isHTTPType(t, "ResponseWriter", imports)
+		case *ast.StarExpr:
+			if sel, ok := t.X.(*ast.SelectorExpr); ok {
+				request = request || isHTTPType(sel, "Request", imports)
+			}
+		}
+	}
+	return writer && request
+}
+
+func isHTTPType(sel *ast.SelectorExpr, name string, imports map[string]string) bool {
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && sel.Sel.Name == name && imports[pkg.Name] == "net/http"
+}
@@ -0,0 +1,261 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func analyzeHotPath(t *testing.T, code string) []*models.Issue {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	return NewHotPathAnalyzer().Analyze(file, fset)
+}
+
+func TestHotPathFollowsCallsFromLoops(t *testing.T) {
+	issues := analyzeHotPath(t, `package main
+import "regexp"
+func run(lines []string) {
+    for _, line := range lines {
+        process(line)
+    }
+}
+func process(line string) { _ = parse(line) }
+func parse(line string) bool {
+    return regexp.MustCompile("^a+$").MatchString(line)
+}`)
+
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(issues))
+	}
+	issue := issues[0]
+	if issue.Type != models.IssueRegexCompileInLoop || issue.Line != 10 {
+		t.Fatalf("unexpected issue %v at line %d", issue.Type, issue.Line)
+	}
+	want := "regexp.MustCompile runs on a hot path: loop in run (test.go:5) → process → parse"
+	if issue.Message != want {
+		t.Fatalf("message = %q, want %q", issue.Message, want)
+	}
+}
+
+func TestHotPathRoots(t *testing.T) {
+	tests := []struct {
+		name  string
+		code  string
+		types []models.IssueType
+	}{
+		{
+			name: "goroutines started in a loop",
+			code: `package main
+import "time"
+func fanOut(jobs []int) {
+    for range jobs {
+        go work()
+    }
+}
+func work() { _ = time.Now() }`,
+			types: []models.IssueType{models.IssueTimeNowInLoop},
+		},
+		{
+			name: "closures started in a loop",
+			code: `package main
+import "time"
+func fanOut(jobs []int) {
+    for range jobs {
+        go func() {
+            work()
+        }()
+    }
+}
+func work() { _ = time.Now() }`,
+			types: []models.IssueType{models.IssueTimeNowInLoop},
+		},
+		{
+			name: "methods called on the receiver",
+			code: `package main
+import "encoding/json"
+type svc struct{}
+func (s *svc) all(items []int) {
+    for _, item := range items {
+        s.encode(item)
+    }
+}
+func (s *svc) encode(item int) { _, _ = json.Marshal(item) }`,
+			types: []models.IssueType{models.IssueJSONMarshalInLoop},
+		},
+		{
+			name: "methods resolve through the type of their operand",
+			code: `package main
+import (
+    "encoding/json"
+    "time"
+)
+type cache struct{}
+type store struct{}
+func (cache) get(k int) { _, _ = json.Marshal(k) }
+func (store) get(k int) { _ = time.Now() }
+func run(s store, keys []int) {
+    for _, k := range keys {
+        s.get(k)
+    }
+}`,
+			types: []models.IssueType{models.IssueTimeNowInLoop},
+		},
+		{
+			name: "calls through interfaces are not followed",
+			code: `package main
+import "time"
+type getter interface{ get(int) }
+type store struct{}
+func (store) get(k int) { _ = time.Now() }
+func run(g getter, keys []int) {
+    for _, k := range keys {
+        g.get(k)
+    }
+}`,
+		},
+		{
+			name: "HTTP handlers only report per-request work",
+			code: `package main
+import (
+    "encoding/json"
+    "net/http"
+    "regexp"
+)
+func handle(w http.ResponseWriter, r *http.Request) {
+    _, _ = json.Marshal(r.URL.Path)
+    validate(r.URL.Path)
+}
+func validate(path string) { _ = regexp.MustCompile("^/").MatchString(path) }`,
+			types: []models.IssueType{models.IssueRegexCompileInLoop},
+		},
+		{
+			name: "calls outside loops are not hot",
+			code: `package main
+import "reflect"
+func run(v any) { inspect(v) }
+func inspect(v any) { _ = reflect.TypeOf(v) }`,
+		},
+		{
+			name: "results cached per key are computed once",
+			code: `package main
+import (
+    "regexp"
+    "sync"
+)
+var compiled sync.Map
+func run(patterns []string) {
+    for _, p := range patterns {
+        pattern(p)
+    }
+}
+func pattern(p string) *regexp.Regexp {
+    if re, ok := compiled.Load(p); ok {
+        return re.(*regexp.Regexp)
+    }
+    re := regexp.MustCompile(p)
+    compiled.Store(p, re)
+    return re
+}`,
+		},
+		{
+			name: "expensive calls inside loops are left to the loop analyzers",
+			code: `package main
+import "time"
+func run(items []int) {
+    for range items {
+        step(items)
+    }
+}
+func step(items []int) {
+    for range items {
+        _ = time.Now()
+    }
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := analyzeHotPath(t, tt.code)
+			if len(issues) != len(tt.types) {
+				t.Fatalf("expected %d issues, got %d", len(tt.types), len(issues))
+			}
+			for i, issue := range issues {
+				if issue.Type != tt.types[i] {
+					t.Errorf("issue %d type = %v, want %v", i, issue.Type, tt.types[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHotPathCrossesFilesInPackage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go": `package main
+func main() {
+    for _, line := range []string{"a"} {
+        check(line)
+    }
+}`,
+		"check.go": `package main
+import "regexp"
+func check(line string) bool { return regexp.MustCompile("a").MatchString(line) }`,
+	}
+	for name, code := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(code), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filename := filepath.Join(dir, "check.go")
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	issues := NewHotPathAnalyzer().Analyze(file, fset)
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(issues))
+	}
+	if !strings.Contains(issues[0].Message, "loop in main (main.go:4) → check") {
+		t.Fatalf("unexpected message %q", issues[0].Message)
+	}
+}
+
+func TestHotPathUsesSessionSSA(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", `package main
+import "regexp"
+func run(lines []string) {
+    for _, line := range lines {
+        parse(line)
+    }
+}
+func parse(line string) bool { return regexp.MustCompile("a").MatchString(line) }`, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	s := &session{}
+	hotPath := NewHotPathAnalyzer()
+	hotPath.(sessionAnalyzer).useSession(s)
+	if issues := hotPath.Analyze(file, fset); len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(issues))
+	}
+	// The hot paths are derived from the form the session built
+	if _, ok := s.ssa.index.facts.Load("hotpath"); !ok {
+		t.Fatal("expected the hot paths to be kept with the session's SSA form")
+	}
+}
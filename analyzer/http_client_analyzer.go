@@ -61,13 +61,16 @@ func detectDefaultClient(call *ast.CallExpr, fset *token.FileSet, filename strin
 	switch sel.Sel.Name {
 	case methodGet, methodPost, methodHead, methodPostForm:
 		pos := fset.Position(call.Pos())
+		end := fset.Position(call.End())
 		return &models.Issue{
 			File:       filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueHTTPNoTimeout,
-			Severity:   models.SeverityLevelMedium,
+			Severity:   models.IssueHTTPNoTimeout.Severity(),
 			Message:    "http." + sel.Sel.Name + " uses the default client without timeout",
 			Suggestion: "Use a custom http.Client with Timeout set",
 		}
@@ -100,10 +103,13 @@ func detectMissingTimeout(comp *ast.CompositeLit, fset *token.FileSet, filename
 	}
 
 	pos := fset.Position(comp.Pos())
+	end := fset.Position(comp.End())
 	return &models.Issue{
 		File:       filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssueHTTPNoTimeout,
 		Severity:   models.SeverityLevelHigh,
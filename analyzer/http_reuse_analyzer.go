@@ -82,10 +82,13 @@ func (v *httpReuseVisitor) inspectCall(call *ast.CallExpr) {
 		switch sel.Sel.Name {
 		case methodGet, methodPost, methodHead, methodPostForm:
 			pos := v.fset.Position(call.Pos())
+			end := v.fset.Position(call.End())
 			v.issues = append(v.issues, &models.Issue{
 				File:       v.filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueHTTPNoConnectionReuse,
 				Severity:   models.SeverityLevelMedium,
@@ -95,14 +98,18 @@ func (v *httpReuseVisitor) inspectCall(call *ast.CallExpr) {
 		}
 	}
 
+	// A RoundTrip in a loop may well reuse its transport, so it is only a hint
 	if v.loopDepth > 0 {
 		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
 			if sel.Sel.Name == "RoundTrip" {
 				pos := v.fset.Position(call.Pos())
+				end := v.fset.Position(call.End())
 				v.issues = append(v.issues, &models.Issue{
 					File:       v.filename,
 					Line:       pos.Line,
 					Column:     pos.Column,
+					EndLine:    end.Line,
+					EndColumn:  end.Column,
 					Position:   pos,
 					Type:       models.IssueHTTPNoConnectionReuse,
 					Severity:   models.SeverityLevelLow,
@@ -1,31 +1,92 @@
 package analyzer
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"strings"
+	"time"
 
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
+// Suppression directive prefixes recognised in comments
+const (
+	directiveIgnore         = "abc:ignore"
+	directiveIgnoreLine     = "abc:ignore-line"
+	directiveIgnoreNextLine = "abc:ignore-next-line"
+	directiveIgnoreFile     = "abc:ignore-file"
+	directiveNolint         = "nolint"
+
+	nolintLinterName = "aibscleaner"
+	untilDateLayout  = "2006-01-02"
+)
+
+// IgnoreOptions controls how suppression directives are interpreted
+type IgnoreOptions struct {
+	// RequireReason makes directives without a "-- reason" suffix ineffective
+	RequireReason bool
+	// Now is the reference time for expiry checks; zero means time.Now()
+	Now time.Time
+	// Checked reports whether the analysis looked for issues of a type, i.e.
+	// an analyzer reporting it ran. An unused directive naming a type that
+	// was not checked, or naming no type, is not reported stale since nothing
+	// is known about its usage. Nil means every type was checked.
+	Checked func(models.IssueType) bool
+	// Overlay supplies the source the file was parsed from when it is not the
+	// file on disk, for the fixes of stale directives
+	Overlay Overlay
+}
+
 // IgnoreChecker checks if issues should be ignored based on comments
 type IgnoreChecker struct {
 	fset         *token.FileSet
 	file         *ast.File
-	ignoreRanges map[string][]ignoreRange // key is issue type, empty key means all types
+	opts         IgnoreOptions
+	directives   []*ignoreDirective
+	ignoreRanges map[string][]ignoreRange // key is canonical issue type, empty key means all types
+	nodeEnds     map[int]int              // start line -> end line of the widest statement/declaration
+	codeColumns  map[int]int              // line -> column of the first code token
 }
 
 type ignoreRange struct {
 	startLine int
 	endLine   int
 	issueType string // empty means ignore all types
+	directive *ignoreDirective
+}
+
+// ignoreDirective is a single parsed suppression comment
+type ignoreDirective struct {
+	comment *ast.Comment
+	pos     token.Position
+	text    string
+	kind    string
+	types   []string // canonical issue type names, empty means all types
+	unknown []string // references that did not resolve to an issue type
+	reason  string
+	until   time.Time
+	invalid string // non-empty when the directive is malformed
+	expired bool
+	missing bool // reason required but not given
+	used    bool // suppressed at least one issue
+	shared  bool // nolint directive that other linters also read
 }
 
 // NewIgnoreChecker creates a new ignore checker for a file
 func NewIgnoreChecker(fset *token.FileSet, file *ast.File) *IgnoreChecker {
+	return NewIgnoreCheckerWithOptions(fset, file, IgnoreOptions{})
+}
+
+// NewIgnoreCheckerWithOptions creates an ignore checker with custom options
+func NewIgnoreCheckerWithOptions(fset *token.FileSet, file *ast.File, opts IgnoreOptions) *IgnoreChecker {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
 	ic := &IgnoreChecker{
 		fset:         fset,
 		file:         file,
+		opts:         opts,
 		ignoreRanges: make(map[string][]ignoreRange, 10),
 	}
 	ic.parseIgnoreComments()
@@ -36,10 +97,14 @@ func (ic *IgnoreChecker) parseIgnoreComments() {
 	// Check all comment groups in the file
 	for _, cg := range ic.file.Comments {
 		for _, c := range cg.List {
-			text := ic.extractCommentText(c.Text)
-			if strings.HasPrefix(text, "abc:ignore") {
-				ic.processIgnoreDirective(text, c.Pos())
+			d := ic.parseDirective(c.Text)
+			if d == nil {
+				continue
 			}
+			d.comment = c
+			d.pos = ic.fset.Position(c.Pos())
+			ic.directives = append(ic.directives, d)
+			ic.applyDirective(d, cg, c)
 		}
 	}
 }
@@ -54,130 +119,431 @@ func (ic *IgnoreChecker) extractCommentText(text string) string {
 	return strings.TrimSpace(text)
 }
 
-func (ic *IgnoreChecker) processIgnoreDirective(text string, pos token.Pos) {
-	position := ic.fset.Position(pos)
-	line := position.Line
-	parts := strings.Split(text, " ")
+// parseDirective parses a raw comment into a directive, or returns nil if the
+// comment is not a suppression directive for this tool
+func (ic *IgnoreChecker) parseDirective(raw string) *ignoreDirective {
+	// golangci-lint requires "//nolint" without a space, so check the raw text
+	if rest, ok := strings.CutPrefix(raw, "//"+directiveNolint); ok {
+		return parseNolintDirective(raw, rest)
+	}
+
+	text := ic.extractCommentText(raw)
+	if !strings.HasPrefix(text, directiveIgnore) {
+		return nil
+	}
+
+	d := &ignoreDirective{text: text}
+	head, reason, _ := strings.Cut(text, "--")
+	d.reason = strings.TrimSpace(reason)
+
+	fields := strings.Fields(head)
+	kind, options, hasOptions := strings.Cut(fields[0], "[")
+	d.kind = kind
+	switch kind {
+	case directiveIgnore, directiveIgnoreLine, directiveIgnoreNextLine, directiveIgnoreFile:
+	default:
+		d.invalid = "unknown directive " + kind
+		return d
+	}
 
-	if len(parts) == 1 {
-		// Simple abc:ignore - ignores next line for all issue types
-		ic.addIgnoreRange("", line+1, line+1)
-	} else {
-		directive := parts[0]
-		issueTypes := make([]string, 0, 10)
-		if len(parts) > 1 {
-			issueTypes = strings.Split(parts[1], ",")
+	if hasOptions {
+		d.parseOptions(options)
+	}
+
+	for _, field := range fields[1:] {
+		for _, ref := range strings.Split(field, ",") {
+			d.addTypeRef(ref)
 		}
-		ic.processSpecificDirective(directive, issueTypes, line)
 	}
+	return d
 }
 
-func (ic *IgnoreChecker) addIgnoreRange(issueType string, startLine, endLine int) {
+// parseNolintDirective handles golangci-lint style "//nolint" and
+// "//nolint:aibscleaner" comments
+func parseNolintDirective(raw, rest string) *ignoreDirective {
+	rest, reason, _ := strings.Cut(rest, "//")
+	rest = strings.TrimSpace(rest)
+
+	d := &ignoreDirective{
+		text:   strings.TrimSpace(strings.TrimPrefix(raw, "//")),
+		kind:   directiveNolint,
+		reason: strings.TrimSpace(reason),
+		shared: true,
+	}
+
+	linters, ok := strings.CutPrefix(rest, ":")
+	if !ok {
+		if rest != "" {
+			return nil
+		}
+		return d
+	}
+
+	names := strings.Split(linters, ",")
+	for _, linter := range names {
+		if strings.EqualFold(strings.TrimSpace(linter), nolintLinterName) {
+			d.shared = len(names) > 1
+			return d
+		}
+	}
+	return nil
+}
+
+// parseOptions parses the bracketed "[until=YYYY-MM-DD]" option list
+func (d *ignoreDirective) parseOptions(options string) {
+	options, ok := strings.CutSuffix(options, "]")
+	if !ok {
+		d.invalid = "unterminated option list"
+		return
+	}
+
+	for _, option := range strings.Split(options, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch strings.TrimSpace(key) {
+		case "until":
+			until, err := time.Parse(untilDateLayout, strings.TrimSpace(value))
+			if err != nil {
+				d.invalid = "invalid until date " + value
+				return
+			}
+			d.until = until
+		case "":
+		default:
+			d.invalid = "unknown option " + key
+			return
+		}
+	}
+}
+
+func (d *ignoreDirective) addTypeRef(ref string) {
+	ref = strings.TrimSpace(ref)
+	switch ref {
+	case "":
+		return
+	case "*":
+		d.types = append(d.types, "")
+		return
+	}
+
+	issueType, err := models.ParseIssueType(ref)
+	if err != nil {
+		d.unknown = append(d.unknown, ref)
+		return
+	}
+	d.types = append(d.types, issueType.String())
+}
+
+// active reports whether the directive should suppress anything at all
+func (d *ignoreDirective) active(opts IgnoreOptions) bool {
+	if d.invalid != "" {
+		return false
+	}
+	// A directive that only names unknown types must not silently ignore everything
+	if len(d.types) == 0 && len(d.unknown) > 0 {
+		return false
+	}
+	if !d.until.IsZero() && !opts.Now.Before(d.until.AddDate(0, 0, 1)) {
+		d.expired = true
+		return false
+	}
+	if opts.RequireReason && d.reason == "" {
+		d.missing = true
+		return false
+	}
+	return true
+}
+
+func (ic *IgnoreChecker) applyDirective(d *ignoreDirective, cg *ast.CommentGroup, c *ast.Comment) {
+	if !d.active(ic.opts) {
+		return
+	}
+
+	line := d.pos.Line
+	var startLine, endLine int
+	switch d.kind {
+	case directiveIgnoreLine:
+		startLine, endLine = line, line
+	case directiveIgnoreNextLine:
+		startLine, endLine = line+1, line+1
+	case directiveIgnoreFile:
+		startLine, endLine = 1, ic.lastLine()
+	case directiveNolint:
+		if ic.isTrailingComment(c) {
+			startLine, endLine = line, line
+		} else {
+			startLine, endLine = ic.blockRange(ic.fset.Position(cg.End()).Line + 1)
+		}
+	default:
+		// abc:ignore applies to the statement or declaration that follows the comment group
+		startLine, endLine = ic.blockRange(ic.fset.Position(cg.End()).Line + 1)
+	}
+
+	if len(d.types) == 0 {
+		ic.addIgnoreRange("", startLine, endLine, d)
+		return
+	}
+	for _, issueType := range d.types {
+		ic.addIgnoreRange(issueType, startLine, endLine, d)
+	}
+}
+
+func (ic *IgnoreChecker) addIgnoreRange(issueType string, startLine, endLine int, d *ignoreDirective) {
 	ir := ignoreRange{
 		startLine: startLine,
 		endLine:   endLine,
 		issueType: issueType,
+		directive: d,
 	}
 	ic.ignoreRanges[issueType] = append(ic.ignoreRanges[issueType], ir)
 }
 
-func (ic *IgnoreChecker) processSpecificDirective(directive string, issueTypes []string, line int) {
-	switch directive {
-	case "abc:ignore-line":
-		for _, issueType := range issueTypes {
-			ic.addIgnoreRange(strings.TrimSpace(issueType), line, line)
-		}
-	case "abc:ignore-next-line":
-		for _, issueType := range issueTypes {
-			ic.addIgnoreRange(strings.TrimSpace(issueType), line+1, line+1)
+// blockRange returns the line span of the widest statement or declaration
+// starting at line, or just that line when nothing starts there
+func (ic *IgnoreChecker) blockRange(line int) (startLine, endLine int) {
+	ic.indexNodes()
+	if end, ok := ic.nodeEnds[line]; ok {
+		return line, end
+	}
+	return line, line
+}
+
+// isTrailingComment reports whether code precedes the comment on its line
+func (ic *IgnoreChecker) isTrailingComment(c *ast.Comment) bool {
+	ic.indexNodes()
+	pos := ic.fset.Position(c.Pos())
+	col, ok := ic.codeColumns[pos.Line]
+	return ok && col < pos.Column
+}
+
+// indexNodes builds the line indexes used for block scoping in a single pass
+func (ic *IgnoreChecker) indexNodes() {
+	if ic.nodeEnds != nil {
+		return
+	}
+	ic.nodeEnds = make(map[int]int, 64)
+	ic.codeColumns = make(map[int]int, 128)
+
+	ast.Inspect(ic.file, func(n ast.Node) bool {
+		switch n.(type) {
+		case nil:
+			return false
+		case *ast.CommentGroup, *ast.Comment:
+			return false
+		case *ast.File:
+			return true
 		}
-	case "abc:ignore-file":
-		for _, issueType := range issueTypes {
-			if issueType == "" {
-				issueType = "*" // Ignore all types for entire file
-			}
-			ic.addIgnoreRange(strings.TrimSpace(issueType), 0, 999999)
+
+		start := ic.fset.Position(n.Pos())
+		if col, ok := ic.codeColumns[start.Line]; !ok || start.Column < col {
+			ic.codeColumns[start.Line] = start.Column
 		}
-	case "abc:ignore":
-		// Default behavior
-		if len(issueTypes) == 0 {
-			ic.addIgnoreRange("", line+1, line+1)
-		} else {
-			for _, issueType := range issueTypes {
-				issueType = strings.TrimSpace(issueType)
-				// For loop-related issues, ignore a range of lines
-				if strings.Contains(issueType, "_IN_LOOP") || strings.Contains(issueType, "LOOP_") {
-					ic.addIgnoreRange(issueType, line+1, line+10)
-				} else {
-					ic.addIgnoreRange(issueType, line+1, line+1)
-				}
+
+		switch n.(type) {
+		case ast.Stmt, ast.Decl, ast.Spec, *ast.Field:
+			end := ic.fset.Position(n.End()).Line
+			if end > ic.nodeEnds[start.Line] {
+				ic.nodeEnds[start.Line] = end
 			}
 		}
+		return true
+	})
+}
+
+func (ic *IgnoreChecker) lastLine() int {
+	if tf := ic.fset.File(ic.file.Pos()); tf != nil {
+		return tf.LineCount()
 	}
+	return ic.fset.Position(ic.file.End()).Line
 }
 
 // ShouldIgnore checks if an issue at a specific line should be ignored
 func (ic *IgnoreChecker) ShouldIgnore(issueType string, line int) bool {
-	// Check for type-specific ignores
-	if ranges, ok := ic.ignoreRanges[issueType]; ok {
-		for _, r := range ranges {
+	key := issueType
+	if parsed, err := models.ParseIssueType(issueType); err == nil {
+		key = parsed.String()
+	}
+
+	// Check type-specific ignores first, then ignores covering all types.
+	// Every matching directive is marked as used so overlapping ones are not reported stale.
+	ignored := false
+	for _, k := range [...]string{key, ""} {
+		for _, r := range ic.ignoreRanges[k] {
 			if line >= r.startLine && line <= r.endLine {
-				return true
+				r.directive.used = true
+				ignored = true
 			}
 		}
 	}
 
-	// Check for general ignores (all types)
-	if ranges, ok := ic.ignoreRanges[""]; ok {
-		for _, r := range ranges {
-			if line >= r.startLine && line <= r.endLine {
-				return true
-			}
+	return ignored
+}
+
+// Filter removes issues suppressed by directives in this file
+func (ic *IgnoreChecker) Filter(issues []*models.Issue) []*models.Issue {
+	filtered := make([]*models.Issue, 0, len(issues))
+	for _, issue := range issues {
+		if issue == nil {
+			continue
+		}
+		if !ic.ShouldIgnore(issue.Type.String(), issue.Line) {
+			filtered = append(filtered, issue)
 		}
 	}
+	return filtered
+}
 
-	// Check for file-wide ignore of all types
-	if ranges, ok := ic.ignoreRanges["*"]; ok {
-		for _, r := range ranges {
-			if line >= r.startLine && line <= r.endLine {
-				return true
+// StaleDirectives reports directives that suppressed nothing, have expired,
+// are malformed or reference unknown rules. It must be called after Filter
+// so that usage has been recorded.
+func (ic *IgnoreChecker) StaleDirectives() []*models.Issue {
+	issues := make([]*models.Issue, 0, 4)
+	var src []byte
+	for _, d := range ic.directives {
+		message, removable := d.staleReason(ic.opts.Checked)
+		if message == "" {
+			continue
+		}
+		end := ic.fset.Position(d.comment.End())
+
+		var fix *models.SuggestedFix
+		if removable {
+			if src == nil {
+				src = readSource(ic.fset, ic.file, ic.opts.Overlay)
+			}
+			if tf := ic.fset.File(d.comment.Pos()); tf != nil && src != nil {
+				fix = &models.SuggestedFix{
+					Message: "Remove the stale directive",
+					Edits:   []models.TextEdit{directiveRemoval(tf, src, d)},
+				}
 			}
 		}
+		issues = append(issues, &models.Issue{
+			File:       d.pos.Filename,
+			Line:       d.pos.Line,
+			Column:     d.pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
+			Position:   d.pos,
+			Type:       models.IssueStaleIgnoreDirective,
+			Severity:   models.SeverityLevelLow,
+			Message:    message,
+			Suggestion: "Remove the directive or update it to name the rule it is meant to suppress",
+			CanBeFixed: removable,
+			Fix:        fix,
+		})
 	}
+	return issues
+}
 
-	return false
+// staleReason explains why a directive is stale and whether deleting it is
+// safe. checked is IgnoreOptions.Checked.
+func (d *ignoreDirective) staleReason(checked func(models.IssueType) bool) (message string, removable bool) {
+	switch {
+	case d.invalid != "":
+		return fmt.Sprintf("Malformed ignore directive %q: %s", d.text, d.invalid), false
+	case d.expired:
+		return fmt.Sprintf("Ignore directive expired on %s", d.until.Format(untilDateLayout)), true
+	case len(d.unknown) > 0:
+		return fmt.Sprintf("Ignore directive references unknown rule(s): %s", strings.Join(d.unknown, ", ")),
+			len(d.types) == 0
+	case d.missing:
+		return fmt.Sprintf("Ignore directive %q has no reason; add \"-- reason\"", d.text), false
+	case d.used:
+		return "", false
+	case d.kind == directiveNolint && d.shared:
+		// Other linters may still rely on this directive
+		return "", false
+	case !d.usageKnown(checked):
+		// The analyzer that would report the suppressed issue did not run
+		return "", false
+	default:
+		return fmt.Sprintf("Ignore directive %q does not suppress any issue", d.text), true
+	}
 }
 
-// FilterIssuesByComments FilterIssues removes issues that should be ignored based on comments
-func FilterIssuesByComments(issues []*models.Issue, fset *token.FileSet, file *ast.File) []*models.Issue {
-	if file == nil || fset == nil {
-		return issues
+// usageKnown reports whether every type the directive names was checked, so
+// that not being used means the directive suppresses nothing
+func (d *ignoreDirective) usageKnown(checked func(models.IssueType) bool) bool {
+	if checked == nil {
+		return true
 	}
+	if len(d.types) == 0 {
+		return false
+	}
+	for _, name := range d.types {
+		issueType, err := models.ParseIssueType(name)
+		if err != nil || !checked(issueType) {
+			// The empty name of "*" names every type
+			return false
+		}
+	}
+	return true
+}
 
-	ic := NewIgnoreChecker(fset, file)
+// RemoveStaleDirectives returns src with every removable stale directive deleted.
+// Comments on their own line are removed together with the line; trailing
+// comments are cut along with the whitespace before them.
+func (ic *IgnoreChecker) RemoveStaleDirectives(src []byte) []byte {
+	tf := ic.fset.File(ic.file.Pos())
+	if tf == nil || tf.Size() != len(src) {
+		return src
+	}
 
-	filtered := make([]*models.Issue, 0, len(issues))
-	for _, issue := range issues {
-		if issue == nil {
+	edits := make([]models.TextEdit, 0, len(ic.directives))
+	for _, d := range ic.directives {
+		if _, removable := d.staleReason(ic.opts.Checked); !removable {
 			continue
 		}
-		if !ic.ShouldIgnore(issue.Type.String(), issue.Line) {
-			filtered = append(filtered, issue)
+		if fresh, ok := composeEdits(edits, []models.TextEdit{directiveRemoval(tf, src, d)}, len(src)); ok {
+			edits = append(edits, fresh...)
 		}
 	}
+	if len(edits) == 0 {
+		return src
+	}
+	return applyEdits(src, edits)
+}
 
-	return filtered
+// directiveRemoval returns the edit deleting the comment of d
+func directiveRemoval(tf *token.File, src []byte, d *ignoreDirective) models.TextEdit {
+	start, end := tf.Offset(d.comment.Pos()), tf.Offset(d.comment.End())
+	for start > 0 && (src[start-1] == ' ' || src[start-1] == '\t') {
+		start--
+	}
+	if start == 0 || src[start-1] == '\n' {
+		// Own-line comment: drop the line break too
+		if end < len(src) && src[end] == '\n' {
+			end++
+		}
+	}
+	return models.TextEdit{Start: start, End: end}
+}
+
+// FilterIssuesByComments FilterIssues removes issues that should be ignored based on comments
+func FilterIssuesByComments(issues []*models.Issue, fset *token.FileSet, file *ast.File) []*models.Issue {
+	if file == nil || fset == nil {
+		return issues
+	}
+
+	return NewIgnoreChecker(fset, file).Filter(issues)
 }
 
 // Example usage in comments:
-// Ignore next line for all issue types:
+// Ignore the next statement or declaration (its whole body) for all issue types:
 // // abc:ignore
-// Ignore next line for specific issue type:
-// // abc:ignore STRING_CONCAT_IN_LOOP
+// Ignore a specific issue type, by name or PVE ID, with a reason:
+// // abc:ignore DeferInLoop -- closed explicitly after the loop
+// // abc:ignore PVE-003
 // Ignore multiple issue types:
-// // abc:ignore STRING_CONCAT_IN_LOOP,DEFER_IN_LOOP
-// Ignore current line:
+// // abc:ignore StringConcat,DeferInLoop
+// Ignore until a date, after which the directive stops working:
+// // abc:ignore[until=2026-12-31] RegexCompileInLoop -- TICKET-123
+// Ignore the current or the next line only:
+// // abc:ignore-line RaceCondition
+// // abc:ignore-next-line RaceCondition
 // Ignore entire file for specific issues:
-// // abc:ignore-file COMPLEXITY_HIGH
+// // abc:ignore-file HighComplexityO2
 // Ignore entire file for all issues:
 // // abc:ignore-file *
+// golangci-lint style, applies to the line it trails or the block it precedes:
+// //nolint:aibscleaner // reason
@@ -5,6 +5,7 @@ import (
 	"go/parser"
 	"go/token"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -65,19 +66,127 @@ func third() {
 	require.NotContains(t, checker.ignoreRanges, models.IssueHTTPNoTimeout.String())
 }
 
-func TestIgnoreCheckerLoopSpecificRange(t *testing.T) {
+func TestIgnoreCheckerBlockScope(t *testing.T) {
 	src := `package sample
 
-// abc:ignore STRING_CONCAT_IN_LOOP
-func loop() {}
+// abc:ignore DEFER_IN_LOOP
+func loop(files []string) {
+	for _, f := range files {
+		defer println(f)
+	}
+}
+
+func other() {}
 `
 	fset, file := parseFile(t, src)
 	checker := NewIgnoreChecker(fset, file)
 
-	for line := 4; line <= 13; line++ {
-		require.True(t, checker.ShouldIgnore("STRING_CONCAT_IN_LOOP", line))
+	for line := 4; line <= 8; line++ {
+		require.True(t, checker.ShouldIgnore(models.IssueDeferInLoop.String(), line))
 	}
-	require.False(t, checker.ShouldIgnore("STRING_CONCAT_IN_LOOP", 14))
+	require.False(t, checker.ShouldIgnore(models.IssueDeferInLoop.String(), 10))
+	require.False(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 6))
+}
+
+func TestIgnoreCheckerBlockScopeStatement(t *testing.T) {
+	src := `package sample
+
+func loop(files []string) {
+	// abc:ignore
+	// closing happens in the caller
+	for _, f := range files {
+		defer println(f)
+	}
+	defer println("done")
+}
+`
+	fset, file := parseFile(t, src)
+	checker := NewIgnoreChecker(fset, file)
+
+	require.True(t, checker.ShouldIgnore(models.IssueDeferInLoop.String(), 7))
+	require.False(t, checker.ShouldIgnore(models.IssueDeferInLoop.String(), 9))
+}
+
+func TestIgnoreCheckerPVEIDsAndReasons(t *testing.T) {
+	src := `package sample
+
+// abc:ignore ` + models.IssueRaceCondition.GetPVEID() + ` -- guarded by the caller
+func first() {}
+
+// abc:ignore-line NoSuchRule
+func second() {}
+`
+	fset, file := parseFile(t, src)
+	checker := NewIgnoreChecker(fset, file)
+
+	require.True(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 4))
+	require.False(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 6))
+	require.Len(t, checker.directives, 2)
+	require.Equal(t, "guarded by the caller", checker.directives[0].reason)
+	require.Equal(t, []string{"NoSuchRule"}, checker.directives[1].unknown)
+}
+
+func TestIgnoreCheckerRequireReason(t *testing.T) {
+	src := `package sample
+
+// abc:ignore RaceCondition
+func first() {}
+
+// abc:ignore RaceCondition -- single writer
+func second() {}
+`
+	fset, file := parseFile(t, src)
+	checker := NewIgnoreCheckerWithOptions(fset, file, IgnoreOptions{RequireReason: true})
+
+	require.False(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 4))
+	require.True(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 7))
+	require.True(t, checker.directives[0].missing)
+}
+
+func TestIgnoreCheckerExpiry(t *testing.T) {
+	src := `package sample
+
+// abc:ignore[until=2026-12-31] RaceCondition
+func first() {}
+
+// abc:ignore[until=yesterday] RaceCondition
+func second() {}
+`
+	fset, file := parseFile(t, src)
+
+	before := NewIgnoreCheckerWithOptions(fset, file, IgnoreOptions{Now: time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC)})
+	require.True(t, before.ShouldIgnore(models.IssueRaceCondition.String(), 4))
+	require.False(t, before.ShouldIgnore(models.IssueRaceCondition.String(), 7))
+	require.NotEmpty(t, before.directives[1].invalid)
+
+	after := NewIgnoreCheckerWithOptions(fset, file, IgnoreOptions{Now: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)})
+	require.False(t, after.ShouldIgnore(models.IssueRaceCondition.String(), 4))
+	require.True(t, after.directives[0].expired)
+}
+
+func TestIgnoreCheckerNolint(t *testing.T) {
+	src := `package sample
+
+func first() {
+	x := 1 //nolint:aibscleaner // benchmark fixture
+	y := 2 //nolint:errcheck
+	_, _ = x, y
+}
+
+//nolint:gosec,aibscleaner
+func second() {
+	_ = 3
+}
+`
+	fset, file := parseFile(t, src)
+	checker := NewIgnoreChecker(fset, file)
+
+	require.True(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 4))
+	require.False(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 5))
+	require.False(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 6))
+	require.True(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 11))
+	require.Len(t, checker.directives, 2)
+	require.Equal(t, "benchmark fixture", checker.directives[0].reason)
 }
 
 func TestIgnoreCheckerFileWide(t *testing.T) {
@@ -85,12 +194,15 @@ func TestIgnoreCheckerFileWide(t *testing.T) {
 
 // abc:ignore-file *
 func foo() {}
+
+// abc:ignore-file
+func bar() {}
 `
 	fset, file := parseFile(t, src)
 	checker := NewIgnoreChecker(fset, file)
 
 	require.True(t, checker.ShouldIgnore(models.IssueHTTPNoTimeout.String(), 4))
-	require.True(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 100))
+	require.True(t, checker.ShouldIgnore(models.IssueRaceCondition.String(), 7))
 }
 
 func TestFilterIssuesByComments(t *testing.T) {
@@ -118,3 +230,102 @@ func TestFilterIssuesWithNilInputs(t *testing.T) {
 
 	require.Equal(t, issues, FilterIssuesByComments(issues, nil, nil))
 }
+
+func TestIgnoreCheckerStaleDirectives(t *testing.T) {
+	src := `package sample
+
+// abc:ignore RaceCondition
+func used() {}
+
+// abc:ignore HTTPNoTimeout
+func unused() {}
+
+// abc:ignore NoSuchRule
+func unknown() {}
+
+// abc:ignore[until=2020-01-01] RaceCondition
+func expired() {}
+
+//nolint:errcheck,aibscleaner
+func shared() {}
+`
+	fset, file := parseFile(t, src)
+	checker := NewIgnoreChecker(fset, file)
+	checker.Filter([]*models.Issue{{Line: 4, Type: models.IssueRaceCondition}})
+
+	stale := checker.StaleDirectives()
+	require.Len(t, stale, 3)
+
+	lines := make([]int, 0, len(stale))
+	for _, issue := range stale {
+		require.Equal(t, models.IssueStaleIgnoreDirective, issue.Type)
+		require.True(t, issue.CanBeFixed)
+		lines = append(lines, issue.Line)
+	}
+	require.Equal(t, []int{6, 9, 12}, lines)
+	require.Contains(t, stale[1].Message, "NoSuchRule")
+	require.Contains(t, stale[2].Message, "2020-01-01")
+}
+
+func TestIgnoreCheckerUncheckedRulesAreNotStale(t *testing.T) {
+	src := `package sample
+
+// abc:ignore HTTPNoTimeout
+func unchecked() {}
+
+// abc:ignore RaceCondition
+func checked() {}
+
+// abc:ignore RaceCondition,HTTPNoTimeout
+func partly() {}
+
+// abc:ignore
+func everything() {}
+`
+	fset, file := parseFile(t, src)
+	checker := NewIgnoreCheckerWithOptions(fset, file, IgnoreOptions{
+		Checked: func(t models.IssueType) bool { return t == models.IssueRaceCondition },
+	})
+	checker.Filter(nil)
+
+	stale := checker.StaleDirectives()
+	require.Len(t, stale, 1)
+	require.Equal(t, 6, stale[0].Line)
+
+	cleaned := string(checker.RemoveStaleDirectives([]byte(src)))
+	require.NotContains(t, cleaned, "// abc:ignore RaceCondition\n")
+	require.Contains(t, cleaned, "// abc:ignore HTTPNoTimeout\n")
+	require.Contains(t, cleaned, "// abc:ignore RaceCondition,HTTPNoTimeout\n")
+	require.Contains(t, cleaned, "// abc:ignore\n")
+}
+
+func TestIgnoreCheckerRemoveStaleDirectives(t *testing.T) {
+	src := `package sample
+
+// abc:ignore RaceCondition
+func used() {}
+
+// Documented helper.
+// abc:ignore HTTPNoTimeout
+func unused() {
+	x := 1 // abc:ignore-line NoSuchRule
+	_ = x
+}
+`
+	want := `package sample
+
+// abc:ignore RaceCondition
+func used() {}
+
+// Documented helper.
+func unused() {
+	x := 1
+	_ = x
+}
+`
+	fset, file := parseFile(t, src)
+	checker := NewIgnoreChecker(fset, file)
+	checker.Filter([]*models.Issue{{Line: 4, Type: models.IssueRaceCondition}})
+
+	require.Equal(t, want, string(checker.RemoveStaleDirectives([]byte(src))))
+}
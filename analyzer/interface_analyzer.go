@@ -75,10 +75,13 @@ func (v *interfaceVisitor) checkTypeAssert(assert *ast.TypeAssertExpr) {
 		return
 	}
 	pos := v.fset.Position(assert.Pos())
+	end := v.fset.Position(assert.End())
 	v.issues = append(v.issues, &models.Issue{
 		File:       v.filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssueInterfaceAllocation,
 		Severity:   models.SeverityLevelLow,
@@ -93,10 +96,13 @@ func (v *interfaceVisitor) checkInterfaceLiteral(lit *ast.CompositeLit) {
 		return
 	}
 	pos := v.fset.Position(lit.Pos())
+	end := v.fset.Position(lit.End())
 	v.issues = append(v.issues, &models.Issue{
 		File:       v.filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssueInterfaceAllocation,
 		Severity:   models.SeverityLevelLow,
@@ -86,6 +86,8 @@ func (v *ioVisitor) inspectCall(call *ast.CallExpr) {
 		return
 	}
 
+	// io.ReadAll and io.Copy work on readers that are often in memory, so they
+	// are reported below the disk round trips above
 	if matchSelector(call, "io", "ReadAll") || matchSelector(call, "io", "Copy") {
 		v.addIssue(call, models.IssueUnbufferedIO, models.SeverityLevelLow,
 			"io.ReadAll/io.Copy inside loop can create garbage and block",
@@ -95,10 +97,13 @@ func (v *ioVisitor) inspectCall(call *ast.CallExpr) {
 
 func (v *ioVisitor) addIssue(call *ast.CallExpr, issueType models.IssueType, sev models.SeverityLevel, msg, suggestion string) {
 	pos := v.fset.Position(call.Pos())
+	end := v.fset.Position(call.End())
 	v.issues = append(v.issues, &models.Issue{
 		File:       v.filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       issueType,
 		Severity:   sev,
@@ -18,67 +18,72 @@ func (la *LoopAnalyzer) Name() string {
 }
 
 func (la *LoopAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
-	file, ok := node.(*ast.File)
-	if !ok {
-		return nil
-	}
-
-	filename := ""
-	if file.Pos().IsValid() {
-		filename = fset.Position(file.Pos()).Filename
-	}
+	return walkAlone(la, node, fset)
+}
 
-	visitor := &loopVisitor{
-		fset:     fset,
-		filename: filename,
-		issues:   make([]*models.Issue, 0, 8),
+func (la *LoopAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
+	visitor := &loopVisitor{issues: make([]*models.Issue, 0, 8)}
+	w.On(visitor.visitDefer, (*ast.DeferStmt)(nil))
+	return func() []*models.Issue {
+		return append(visitor.issues, checkLoopComplexity(w.File(), w.FileSet(), w.session.thresholds().MaxLoopDepth)...)
 	}
-
-	ast.Walk(visitor, file)
-	return visitor.issues
 }
 
 type loopVisitor struct {
-	fset      *token.FileSet
-	filename  string
-	loopDepth int
-	issues    []*models.Issue
+	issues []*models.Issue
 }
 
-func (v *loopVisitor) Visit(node ast.Node) ast.Visitor {
-	switch n := node.(type) {
-	case *ast.ForStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.RangeStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.DeferStmt:
-		if v.loopDepth > 0 {
-			v.addIssue(n.Pos())
+// visitDefer reports defers whose function returns after the loop. A defer in
+// a function literal inside the loop runs when the literal returns, which is
+// how the fix scopes it.
+func (v *loopVisitor) visitDefer(n ast.Node, wc *WalkContext) {
+	d, _ := n.(*ast.DeferStmt)
+	if inLoop, ok := ssaInLoop(wc, d.Call); ok {
+		if inLoop {
+			v.addIssue(n, wc)
 		}
+		return
 	}
-	return v
+	if !wc.InLoop() {
+		return
+	}
+	if lit, ok := wc.Func.(*ast.FuncLit); ok && lit.Pos() > wc.Loops[len(wc.Loops)-1].Pos() {
+		return
+	}
+	v.addIssue(n, wc)
 }
 
-func (v *loopVisitor) addIssue(pos token.Pos) {
-	position := v.fset.Position(pos)
+func (v *loopVisitor) addIssue(node ast.Node, wc *WalkContext) {
+	position := wc.Fset.Position(node.Pos())
+	end := wc.Fset.Position(node.End())
+	// Loops built with goto have no body to scope the defer to
+	var fix *models.SuggestedFix
+	if wc.InLoop() {
+		fix = deferLoopFix(wc.Fset, loopBody(wc.Loops[len(wc.Loops)-1]))
+	}
 	v.issues = append(v.issues, &models.Issue{
-		File:       v.filename,
+		File:       wc.Filename,
 		Line:       position.Line,
 		Column:     position.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   position,
 		Type:       models.IssueDeferInLoop,
 		Severity:   models.SeverityLevelMedium,
 		Message:    "Defer inside loop runs every iteration",
 		Suggestion: "Move the defer outside loop or replace with explicit cleanup",
+		CanBeFixed: fix != nil,
+		Fix:        fix,
 	})
 }
+
+// loopBody returns the body of a for or range statement
+func loopBody(loop ast.Stmt) *ast.BlockStmt {
+	switch loop := loop.(type) {
+	case *ast.ForStmt:
+		return loop.Body
+	case *ast.RangeStmt:
+		return loop.Body
+	}
+	return nil
+}
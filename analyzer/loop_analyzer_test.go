@@ -3,7 +3,12 @@ package analyzer
 import (
 	"go/parser"
 	"go/token"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
 func TestLoopAnalyzer(t *testing.T) {
@@ -127,3 +132,351 @@ func test(items []int) int {
 		)
 	}
 }
+
+func TestLoopComplexity(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected models.IssueType
+		severity models.SeverityLevel
+		message  string
+	}{
+		{
+			name: "nested range over different inputs",
+			code: `package main
+func join(users []User, orders []Order) {
+	for _, u := range users {
+		for _, o := range orders {
+			if o.UserID == u.ID {
+				_ = o
+			}
+		}
+	}
+}`,
+			expected: models.IssueHighComplexityO2,
+			severity: models.SeverityLevelMedium,
+			message:  "O(n²)",
+		},
+		{
+			name: "inner range over the same slice",
+			code: `package main
+func dups(items []int) {
+	for i := range items {
+		for _, other := range items[i+1:] {
+			_ = other
+		}
+	}
+}`,
+			expected: models.IssueHighComplexityO2,
+			severity: models.SeverityLevelMedium,
+			message:  "ranges over items again",
+		},
+		{
+			name: "triple nesting",
+			code: `package main
+func cube(n int) {
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			for k := 0; k < n; k++ {
+				_ = i + j + k
+			}
+		}
+	}
+}`,
+			expected: models.IssueHighComplexityO3,
+			severity: models.SeverityLevelHigh,
+			message:  "O(n³)",
+		},
+		{
+			name: "slices.Contains in a loop",
+			code: `package main
+
+import "slices"
+
+func filter(items, blocked []string) (out []string) {
+	for _, item := range items {
+		if !slices.Contains(blocked, item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}`,
+			expected: models.IssueHighComplexityO2,
+			severity: models.SeverityLevelMedium,
+			message:  "slices.Contains",
+		},
+		{
+			name: "strings.Contains on a growing string",
+			code: `package main
+
+import "strings"
+
+func unique(words []string) string {
+	seen := ""
+	for _, w := range words {
+		if !strings.Contains(seen, w) {
+			seen += w + ","
+		}
+	}
+	return seen
+}`,
+			expected: models.IssueHighComplexityO2,
+			severity: models.SeverityLevelMedium,
+			message:  "strings.Contains",
+		},
+		{
+			name: "triangular loop",
+			code: `package main
+func pairs(items []int) {
+	for i := 0; i < len(items); i++ {
+		for j := 0; j < i; j++ {
+			_ = items[i] + items[j]
+		}
+	}
+}`,
+			expected: models.IssueHighComplexityO2,
+			severity: models.SeverityLevelMedium,
+			message:  "O(n²)",
+		},
+		{
+			name: "linear search in a nested loop",
+			code: `package main
+
+import "slices"
+
+func f(a, b, c []int) {
+	for _, x := range a {
+		for _, y := range b {
+			_ = slices.Index(c, x+y)
+		}
+	}
+}`,
+			expected: models.IssueHighComplexityO3,
+			severity: models.SeverityLevelHigh,
+			message:  "slices.Index",
+		},
+		{
+			name: "package-level slice that grows",
+			code: `package main
+var seen = []string{}
+func record(items []string) {
+	for _, item := range items {
+		for _, s := range seen {
+			_ = s + item
+		}
+		seen = append(seen, item)
+	}
+}`,
+			expected: models.IssueHighComplexityO2,
+			severity: models.SeverityLevelMedium,
+			message:  "O(n²)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := analyzeLoopComplexity(t, tt.code)
+			if len(issues) != 1 {
+				t.Fatalf("expected one complexity issue, got %d: %+v", len(issues), issues)
+			}
+			issue := issues[0]
+			if issue.Type != tt.expected || issue.Severity != tt.severity {
+				t.Errorf("expected %s/%s, got %s/%s", tt.expected, tt.severity, issue.Type, issue.Severity)
+			}
+			if !strings.Contains(issue.Message, tt.message) {
+				t.Errorf("expected %q in message %q", tt.message, issue.Message)
+			}
+			if !strings.Contains(issue.Suggestion, "map") {
+				t.Errorf("expected a map-based suggestion, got %q", issue.Suggestion)
+			}
+		})
+	}
+}
+
+func TestLoopComplexityIgnoresConstantBounds(t *testing.T) {
+	tests := map[string]string{
+		"constant bounds": `package main
+const size = 8
+func grid() {
+	for i := 0; i < 10; i++ {
+		for j := 0; j < size; j++ {
+			_ = i * j
+		}
+	}
+}`,
+		"literal collection": `package main
+func f(items []string) {
+	for _, item := range items {
+		for _, suffix := range []string{".go", ".mod"} {
+			_ = item + suffix
+		}
+	}
+}`,
+		"search in literal list": `package main
+
+import "slices"
+
+func f(items []string) {
+	for _, item := range items {
+		_ = slices.Contains([]string{"a", "b"}, item)
+	}
+}`,
+		"strings.Contains on a fixed string": `package main
+
+import "strings"
+
+func f(lines []string, needle string) {
+	for _, line := range lines {
+		_ = strings.Contains(line, needle)
+	}
+}`,
+		"walking children of each element": `package main
+func cells(grid [][]int, rows []Row) {
+	for i := range grid {
+		for j := 0; j < len(grid[i]); j++ {
+			_ = grid[i][j]
+		}
+	}
+	for _, row := range rows {
+		for _, cell := range row.Cells {
+			_ = cell
+		}
+	}
+}`,
+		"package-level tables": `package main
+
+import "slices"
+
+var arches = []string{"amd64", "arm64", "riscv64"}
+var ports [4]int
+
+func targets(pkgs []string) {
+	for _, pkg := range pkgs {
+		for _, arch := range arches {
+			_ = pkg + "/" + arch
+		}
+		for i := 0; i < len(ports); i++ {
+			_ = ports[i]
+		}
+		_ = slices.Contains(arches, pkg)
+	}
+}`,
+		"loop in a goroutine": `package main
+func start(workers int, jobs chan int) {
+	for range workers {
+		go func() {
+			for job := range jobs {
+				_ = job
+			}
+		}()
+	}
+}`,
+		"event loop": `package main
+func serve(ch chan int, items []int) {
+	for {
+		for _, item := range items {
+			_ = item
+		}
+	}
+}`,
+	}
+
+	for name, code := range tests {
+		t.Run(name, func(t *testing.T) {
+			if issues := analyzeLoopComplexity(t, code); len(issues) != 0 {
+				t.Fatalf("expected no complexity issues, got %+v", issues)
+			}
+		})
+	}
+}
+
+func TestLoopComplexityHonorsMaxLoopDepth(t *testing.T) {
+	code := `package main
+func f() {
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			for k := 0; k < 2; k++ {
+				_ = i + j + k
+			}
+		}
+	}
+}`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := countIssues(NewLoopAnalyzer().Analyze(file, fset), models.IssueNestedLoop); n != 0 {
+		t.Fatalf("three loops are within the default limit, got %d issues", n)
+	}
+
+	opts := Options{Analyzers: map[string]bool{"loop": true}, Thresholds: Thresholds{MaxLoopDepth: 2}}
+	issues := AnalyzeWithOptions("test.go", file, fset, opts)
+	if n := countIssues(issues, models.IssueNestedLoop); n != 1 {
+		t.Fatalf("expected one NestedLoop issue with a limit of 2, got %+v", issues)
+	}
+}
+
+func analyzeLoopComplexity(t *testing.T, code string) []*models.Issue {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse code: %v", err)
+	}
+	var issues []*models.Issue
+	for _, issue := range NewLoopAnalyzer().Analyze(file, fset) {
+		if issue.Type == models.IssueHighComplexityO2 || issue.Type == models.IssueHighComplexityO3 {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+func countIssues(issues []*models.Issue, issueType models.IssueType) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Type == issueType {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLoopAnalyzerDeferInFuncLit(t *testing.T) {
+	code := `package main
+
+import "os"
+
+func run(names []string) {
+	for _, name := range names {
+		f, _ := os.Open(name)
+		defer f.Close() // returns with run
+	}
+	for _, name := range names {
+		func() {
+			f, _ := os.Open(name)
+			defer f.Close() // returns with the literal
+		}()
+	}
+	go func() {
+		for _, name := range names {
+			f, _ := os.Open(name)
+			defer f.Close() // returns with the literal
+		}
+	}()
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, parser.ParseComments)
+	require.NoError(t, err)
+
+	var lines []int
+	for _, issue := range NewLoopAnalyzer().Analyze(file, fset) {
+		if issue.Type == models.IssueDeferInLoop {
+			lines = append(lines, issue.Line)
+		}
+	}
+	require.Equal(t, []int{8, 19}, lines)
+}
@@ -0,0 +1,601 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// Linear search functions by import path. The strings and bytes ones only
+// count when the searched value grows inside the loop.
+var linearSearchFuncs = map[string]map[string]bool{
+	"slices":                  {"Contains": true, "ContainsFunc": true, "Index": true, "IndexFunc": true},
+	"golang.org/x/exp/slices": {"Contains": true, "ContainsFunc": true, "Index": true, "IndexFunc": true},
+	"strings":                 {"Contains": true, "Index": true, "Count": true},
+	"bytes":                   {"Contains": true, "Index": true, "Count": true},
+}
+
+// Why a loop nest is expensive
+const (
+	costNested = iota // loops over input-sized collections inside each other
+	costRescan        // an inner loop ranges over the collection of an outer one
+	costSearch        // a linear search runs on every iteration
+)
+
+// complexityFrame is an enclosing loop of the complexity walk
+type complexityFrame struct {
+	sized   bool     // the iteration count depends on input sizes
+	ranged  string   // the collection ranged over, "" for three-clause loops
+	vars    []string // the iteration variables
+	growing []string // variables appended to inside the loop
+}
+
+// loopNestCost is the most expensive point found in an outermost loop
+type loopNestCost struct {
+	degree int
+	kind   int
+	node   ast.Node
+	end    token.Pos
+	detail string // the rescanned collection or the search function
+}
+
+// complexityChecker estimates the asymptotic cost of loop nests. A loop counts
+// towards the degree when its bounds are not constants or fixed tables;
+// linear searches add one more.
+type complexityChecker struct {
+	fset     *token.FileSet
+	maxDepth int
+	consts   map[string]bool
+	tables   map[string]bool   // package-level variables of a fixed size
+	imports  map[string]string // local name to import path
+	frames   []complexityFrame
+	depth    int
+	nest     loopNestCost
+	deepest  ast.Node // first loop past maxDepth in the current nest
+	maxSeen  int      // deepest nesting in the current nest
+	issues   []*models.Issue
+}
+
+func checkLoopComplexity(file *ast.File, fset *token.FileSet, maxDepth int) []*models.Issue {
+	c := &complexityChecker{
+		fset:     fset,
+		maxDepth: maxDepth,
+		consts:   fileConstants(file),
+		tables:   fileTables(file),
+		imports:  importNames(file),
+	}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+			c.walk(fn.Body)
+		}
+	}
+	return c.issues
+}
+
+func (c *complexityChecker) walk(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ForStmt:
+			c.loop(n, n.Body, c.sizedFor(n), "", loopVars(n.Init))
+			return false
+		case *ast.RangeStmt:
+			c.loop(n, n.Body, c.sizedRange(n), collectionName(n.X), rangeVars(n))
+			return false
+		case *ast.FuncLit:
+			c.funcLit(n)
+			return false
+		case *ast.CallExpr:
+			c.checkSearch(n)
+		}
+		return true
+	})
+}
+
+// funcLit checks a function literal on its own: it may run once, per
+// iteration or in another goroutine, which the syntax does not tell
+func (c *complexityChecker) funcLit(lit *ast.FuncLit) {
+	frames, depth, nest, deepest, maxSeen := c.frames, c.depth, c.nest, c.deepest, c.maxSeen
+	c.frames, c.depth = nil, 0
+	c.walk(lit.Body)
+	c.frames, c.depth, c.nest, c.deepest, c.maxSeen = frames, depth, nest, deepest, maxSeen
+}
+
+func (c *complexityChecker) loop(stmt ast.Stmt, body *ast.BlockStmt, sized bool, ranged string, vars []string) {
+	outermost := c.depth == 0
+	if outermost {
+		c.nest = loopNestCost{}
+		c.deepest, c.maxSeen = nil, 0
+	}
+
+	if sized {
+		kind, detail := costNested, ""
+		if c.rescans(ranged) {
+			kind, detail = costRescan, ranged
+		} else if c.derivedBound(stmt) {
+			// Walking the children of the outer element, like range row.Cells,
+			// visits every element once over the whole nest
+			sized = false
+		}
+		if sized {
+			c.record(c.sizedDepth()+1, kind, stmt, body.Lbrace, detail)
+		}
+	}
+
+	c.depth++
+	c.maxSeen = max(c.maxSeen, c.depth)
+	if c.depth > c.maxDepth && c.deepest == nil {
+		c.deepest = stmt
+	}
+	c.frames = append(c.frames, complexityFrame{sized: sized, ranged: ranged, vars: vars, growing: growingVars(body)})
+	c.frames[len(c.frames)-1].vars = c.derivedVars(body, vars)
+	c.walk(body)
+	c.frames = c.frames[:len(c.frames)-1]
+	c.depth--
+
+	if outermost {
+		c.report()
+	}
+}
+
+// checkSearch records a linear search run on every iteration of a sized loop
+func (c *complexityChecker) checkSearch(call *ast.CallExpr) {
+	if c.sizedDepth() == 0 || len(call.Args) == 0 {
+		return
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return
+	}
+	path := c.imports[pkg.Name]
+	if !linearSearchFuncs[path][sel.Sel.Name] {
+		return
+	}
+
+	// Searching a literal list or a table costs a constant, and searching a
+	// part of the current element is bounded by its size
+	if _, ok := call.Args[0].(*ast.CompositeLit); ok || c.isConstant(call.Args[0]) || c.derived(call.Args[0]) {
+		return
+	}
+	if (path == "strings" || path == "bytes") && !c.isGrowing(call.Args[0]) {
+		return
+	}
+	c.record(c.sizedDepth()+1, costSearch, call, call.End(), pkg.Name+"."+sel.Sel.Name)
+}
+
+func (c *complexityChecker) record(degree, kind int, node ast.Node, end token.Pos, detail string) {
+	if degree < 2 || degree <= c.nest.degree {
+		return
+	}
+	c.nest = loopNestCost{degree: degree, kind: kind, node: node, end: end, detail: detail}
+}
+
+func (c *complexityChecker) sizedDepth() int {
+	n := 0
+	for _, frame := range c.frames {
+		if frame.sized {
+			n++
+		}
+	}
+	return n
+}
+
+// rescans reports whether an enclosing loop ranges over the same collection
+func (c *complexityChecker) rescans(ranged string) bool {
+	if ranged == "" {
+		return false
+	}
+	for _, frame := range c.frames {
+		if frame.ranged == ranged {
+			return true
+		}
+	}
+	return false
+}
+
+// derived reports whether expr uses the iteration variables of an enclosing loop
+func (c *complexityChecker) derived(expr ast.Expr) bool {
+	for _, frame := range c.frames {
+		if usesAny(expr, frame.vars) {
+			return true
+		}
+	}
+	return false
+}
+
+// derivedBound reports whether a loop walks something reached through an
+// enclosing loop: range row.Cells or j < len(grid[i]). A plain j < i still
+// counts, as it makes the nest triangular rather than linear.
+func (c *complexityChecker) derivedBound(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.RangeStmt:
+		return c.derived(s.X)
+	case *ast.ForStmt:
+		found := false
+		ast.Inspect(s.Cond, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return !found
+			}
+			if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "len" && c.derived(call.Args[0]) {
+				found = true
+			}
+			return !found
+		})
+		return found
+	}
+	return false
+}
+
+// derivedVars adds to vars the variables the loop body declares from them,
+// like vs, ok := spec.(*ast.ValueSpec)
+func (c *complexityChecker) derivedVars(body *ast.BlockStmt, vars []string) []string {
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Tok != token.DEFINE {
+			return true
+		}
+		for _, rhs := range assign.Rhs {
+			if usesAny(rhs, vars) {
+				vars = append(vars, identNames(assign.Lhs...)...)
+				break
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+func usesAny(expr ast.Expr, names []string) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && slices.Contains(names, ident.Name) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func loopVars(init ast.Stmt) []string {
+	assign, ok := init.(*ast.AssignStmt)
+	if !ok {
+		return nil
+	}
+	return identNames(assign.Lhs...)
+}
+
+func rangeVars(r *ast.RangeStmt) []string {
+	return identNames(r.Key, r.Value)
+}
+
+func identNames(exprs ...ast.Expr) []string {
+	var names []string
+	for _, expr := range exprs {
+		if ident, ok := expr.(*ast.Ident); ok && ident.Name != "_" {
+			names = append(names, ident.Name)
+		}
+	}
+	return names
+}
+
+func (c *complexityChecker) isGrowing(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	for _, frame := range c.frames {
+		for _, name := range frame.growing {
+			if name == ident.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sizedFor reports whether a three-clause loop runs a number of times that
+// depends on its input, like i < n or i < len(items)
+func (c *complexityChecker) sizedFor(f *ast.ForStmt) bool {
+	cond, ok := f.Cond.(*ast.BinaryExpr)
+	if !ok {
+		return false // for {} and for cond() loops have no visible bound
+	}
+	switch cond.Op {
+	case token.LSS, token.LEQ, token.GTR, token.GEQ, token.NEQ:
+	default:
+		return false
+	}
+	if !c.isConstant(cond.X) && !c.isConstant(cond.Y) {
+		return true
+	}
+	// A countdown like i := len(s) - 1; i >= 0 takes its bound from the init
+	if init, ok := f.Init.(*ast.AssignStmt); ok {
+		for _, rhs := range init.Rhs {
+			if !c.isConstant(rhs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *complexityChecker) sizedRange(r *ast.RangeStmt) bool {
+	switch x := r.X.(type) {
+	case *ast.CompositeLit:
+		return false
+	case *ast.UnaryExpr:
+		_, ok := x.X.(*ast.CompositeLit) // &[...]T{...}
+		return !ok
+	}
+	return !c.isConstant(r.X)
+}
+
+// isConstant reports whether expr does not depend on the input: constants,
+// fixed tables and their lengths
+func (c *complexityChecker) isConstant(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.Ident:
+		return c.consts[e.Name] || c.tables[e.Name] || e.Name == "true" || e.Name == "false" || e.Name == "nil"
+	case *ast.CallExpr:
+		fn, ok := e.Fun.(*ast.Ident)
+		return ok && (fn.Name == "len" || fn.Name == "cap") && len(e.Args) == 1 && c.isConstant(e.Args[0])
+	case *ast.ParenExpr:
+		return c.isConstant(e.X)
+	case *ast.UnaryExpr:
+		return c.isConstant(e.X)
+	case *ast.BinaryExpr:
+		return c.isConstant(e.X) && c.isConstant(e.Y)
+	}
+	return false
+}
+
+func (c *complexityChecker) report() {
+	if c.deepest != nil {
+		c.issues = append(c.issues, c.issue(
+			models.IssueNestedLoop, models.IssueNestedLoop.Severity(), c.deepest, loopHeaderEnd(c.deepest),
+			fmt.Sprintf("Loops nested %d deep exceed the limit of %d", c.maxSeen, c.maxDepth),
+			"Extract the inner loops into functions or restructure the data to flatten the nest",
+		))
+	}
+
+	nest := c.nest
+	if nest.degree < 2 {
+		return
+	}
+	issueType, severity := models.IssueHighComplexityO2, models.SeverityLevelMedium
+	if nest.degree >= 3 {
+		issueType, severity = models.IssueHighComplexityO3, models.SeverityLevelHigh
+	}
+
+	cost := complexityLabel(nest.degree)
+	var message, suggestion string
+	switch nest.kind {
+	case costRescan:
+		message = fmt.Sprintf("Inner loop ranges over %s again for every element of it: %s", nest.detail, cost)
+		suggestion = fmt.Sprintf(
+			"Index %s in a map keyed by the compared field before the outer loop and look elements up in O(1)", nest.detail,
+		)
+	case costSearch:
+		message = fmt.Sprintf("%s scans the whole collection on every loop iteration: %s", nest.detail, cost)
+		suggestion = "Build a map[T]struct{} of the searched values once before the loop and use a map lookup instead"
+	default:
+		message = fmt.Sprintf("Loops over input-sized collections nested %d deep: %s", nest.degree, cost)
+		suggestion = "Replace the inner loop with a lookup in a map built once before the outer loop"
+	}
+	c.issues = append(c.issues, c.issue(issueType, severity, nest.node, nest.end, message, suggestion))
+}
+
+func (c *complexityChecker) issue(
+	issueType models.IssueType, severity models.SeverityLevel, node ast.Node, endPos token.Pos, message, suggestion string,
+) *models.Issue {
+	pos := c.fset.Position(node.Pos())
+	end := c.fset.Position(endPos)
+	return &models.Issue{
+		File:       pos.Filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
+		Position:   pos,
+		Type:       issueType,
+		Severity:   severity,
+		Message:    message,
+		Suggestion: suggestion,
+	}
+}
+
+// loopHeaderEnd returns the end of the for clause, so that the whole body is
+// not highlighted
+func loopHeaderEnd(node ast.Node) token.Pos {
+	switch n := node.(type) {
+	case *ast.ForStmt:
+		return n.Body.Lbrace
+	case *ast.RangeStmt:
+		return n.Body.Lbrace
+	}
+	return node.End()
+}
+
+func complexityLabel(degree int) string {
+	switch degree {
+	case 2:
+		return "O(n²)"
+	case 3:
+		return "O(n³)"
+	}
+	return "O(n^" + strconv.Itoa(degree) + ")"
+}
+
+// collectionName identifies the collection a range statement walks. Sub-slices
+// and elements of the same variable count as the same collection.
+func collectionName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.SliceExpr:
+			expr = e.X
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.Ident, *ast.SelectorExpr:
+			return exprString(e)
+		default:
+			return ""
+		}
+	}
+}
+
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		if x := exprString(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+	}
+	return ""
+}
+
+// growingVars returns the variables a loop body appends to: s += x,
+// s = s + x and s = append(s, x)
+func growingVars(body *ast.BlockStmt) []string {
+	var names []string
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+			return true
+		}
+		ident, ok := assign.Lhs[0].(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch {
+		case assign.Tok == token.ADD_ASSIGN:
+			names = append(names, ident.Name)
+		case assign.Tok == token.ASSIGN && extendsVar(assign.Rhs[0], ident.Name):
+			names = append(names, ident.Name)
+		}
+		return true
+	})
+	return names
+}
+
+func extendsVar(expr ast.Expr, name string) bool {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		ident, ok := e.X.(*ast.Ident)
+		return e.Op == token.ADD && ok && ident.Name == name
+	case *ast.CallExpr:
+		fn, ok := e.Fun.(*ast.Ident)
+		if !ok || fn.Name != "append" || len(e.Args) == 0 {
+			return false
+		}
+		arg, ok := e.Args[0].(*ast.Ident)
+		return ok && arg.Name == name
+	}
+	return false
+}
+
+func fileConstants(file *ast.File) map[string]bool {
+	consts := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST {
+			return true
+		}
+		for _, spec := range decl.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				consts[name.Name] = true
+			}
+		}
+		return false
+	})
+	return consts
+}
+
+// importNames maps the names files refer to imports by to the import paths
+// fileTables returns the package-level variables of the file whose size is
+// fixed: arrays and variables initialized with a composite literal, like
+// var arches = []string{"amd64", "arm64"}. Variables assigned in a function
+// may grow and are left out, as are names a function declares again.
+func fileTables(file *ast.File) map[string]bool {
+	tables := make(map[string]bool)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if array, ok := vs.Type.(*ast.ArrayType); ok && array.Len != nil {
+					tables[name.Name] = true
+				} else if i < len(vs.Values) {
+					_, literal := ast.Unparen(vs.Values[i]).(*ast.CompositeLit)
+					tables[name.Name] = literal
+				}
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil || len(tables) == 0 {
+			continue
+		}
+		ast.Inspect(fn, func(n ast.Node) bool {
+			var names []string
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				names = identNames(n.Lhs...)
+			case *ast.ValueSpec:
+				names = identNames(identExprs(n.Names)...)
+			case *ast.Field:
+				names = identNames(identExprs(n.Names)...)
+			case *ast.RangeStmt:
+				names = identNames(n.Key, n.Value)
+			}
+			for _, name := range names {
+				delete(tables, name)
+			}
+			return true
+		})
+	}
+	return tables
+}
+
+func identExprs(idents []*ast.Ident) []ast.Expr {
+	exprs := make([]ast.Expr, len(idents))
+	for i, ident := range idents {
+		exprs[i] = ident
+	}
+	return exprs
+}
+
+func importNames(file *ast.File) map[string]string {
+	names := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		names[name] = path
+	}
+	return names
+}
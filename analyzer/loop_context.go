@@ -8,76 +8,63 @@ import (
 type LoopContext struct {
 }
 
-// IsInLoop checks if a node is inside a loop by traversing the AST
+// IsInLoop checks if a node is inside a loop statement under root
 func IsInLoop(root, target ast.Node) bool {
-	var inLoop bool
-	var targetFound bool
-
-	ast.Inspect(root, func(n ast.Node) bool {
-		if n == target {
-			targetFound = true
-			return false
-		}
-
+	for _, n := range ancestorsOf(root, target) {
 		switch n.(type) {
 		case *ast.ForStmt, *ast.RangeStmt:
-			// Check if target is inside this loop
-			ast.Inspect(n, func(inner ast.Node) bool {
-				if inner == target {
-					inLoop = true
-					targetFound = true
-					return false
-				}
-				return true
-			})
-			if targetFound {
-				return false
-			}
+			return true
 		}
-
-		return !targetFound
-	})
-
-	return inLoop
+	}
+	return false
 }
 
 // GetLoopDepth returns the nesting depth of loops for a node
 func GetLoopDepth(root, target ast.Node) int {
+	path := append(ancestorsOf(root, target), target)
 	depth := 0
-	var measure func(ast.Node, int) bool
-
-	measure = func(n ast.Node, currentDepth int) bool {
-		if n == target {
-			depth = currentDepth
-			return false
-		}
-
-		switch node := n.(type) {
+	for i, n := range path[:len(path)-1] {
+		switch loop := n.(type) {
 		case *ast.ForStmt:
-			if node.Body != nil {
-				ast.Inspect(node.Body, func(inner ast.Node) bool {
-					return measure(inner, currentDepth+1)
-				})
+			if path[i+1] == loop.Body {
+				depth++
 			}
-			return false
-
 		case *ast.RangeStmt:
-			if node.Body != nil {
-				ast.Inspect(node.Body, func(inner ast.Node) bool {
-					return measure(inner, currentDepth+1)
-				})
+			if path[i+1] == loop.Body {
+				depth++
 			}
-			return false
 		}
-
-		return true
 	}
+	return depth
+}
 
+// ancestorsOf returns the nodes from root down to the parent of target, or
+// nil when target is not under root. Only the nodes spanning target are
+// visited.
+func ancestorsOf(root, target ast.Node) []ast.Node {
+	if root == nil || target == nil {
+		return nil
+	}
+	var stack, path []ast.Node
+	found := false
 	ast.Inspect(root, func(n ast.Node) bool {
-		return measure(n, 0)
+		switch {
+		case found:
+			return false
+		case n == nil:
+			stack = stack[:len(stack)-1]
+			return false
+		case n == target:
+			found = true
+			path = append(path, stack...)
+			return false
+		case n.Pos() > target.Pos() || n.End() < target.End():
+			return false
+		}
+		stack = append(stack, n)
+		return true
 	})
-
-	return depth
+	return path
 }
 
 // AnalyzerWithContext provides context-aware analysis
@@ -85,10 +85,13 @@ func (v *mapVisitor) inspectCall(call *ast.CallExpr) {
 	// map created inside loop without capacity hint
 	if v.loopDepth > 0 && len(call.Args) == 1 {
 		pos := v.fset.Position(call.Pos())
+		end := v.fset.Position(call.End())
 		v.issues = append(v.issues, &models.Issue{
 			File:       v.filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueMapCapacity,
 			Severity:   models.SeverityLevelMedium,
@@ -98,13 +101,17 @@ func (v *mapVisitor) inspectCall(call *ast.CallExpr) {
 		return
 	}
 
-	// map literal created per iteration; flag as GC pressure
+	// map created per iteration with a capacity hint; it does not rehash, so
+	// only the allocation is left and it is reported below the rule's severity
 	if v.loopDepth > 0 {
 		pos := v.fset.Position(call.Pos())
+		end := v.fset.Position(call.End())
 		v.issues = append(v.issues, &models.Issue{
 			File:       v.filename,
 			Line:       pos.Line,
 			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
 			Position:   pos,
 			Type:       models.IssueMapCapacity,
 			Severity:   models.SeverityLevelLow,
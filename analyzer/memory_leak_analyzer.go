@@ -94,13 +94,16 @@ func (v *memoryVisitor) inspectAssign(assign *ast.AssignStmt) {
 	}
 
 	pos := v.fset.Position(assign.Pos())
+	end := v.fset.Position(assign.End())
 	v.issues = append(v.issues, &models.Issue{
 		File:       v.filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssueMemoryLeak,
-		Severity:   models.SeverityLevelMedium,
+		Severity:   models.IssueMemoryLeak.Severity(),
 		Message:    "Resource opened without corresponding Close",
 		Suggestion: "Call defer resource.Close() or close explicitly on all paths",
 	})
@@ -87,10 +87,13 @@ func (v *networkVisitor) inspectCall(call *ast.CallExpr) {
 	if pkgIdent.Name == "net" || pkgIdent.Name == "http" {
 		if sel.Sel.Name == methodDial || sel.Sel.Name == "DialTimeout" || sel.Sel.Name == methodListen || sel.Sel.Name == methodGet || sel.Sel.Name == methodPost {
 			pos := v.fset.Position(call.Pos())
+			end := v.fset.Position(call.End())
 			v.issues = append(v.issues, &models.Issue{
 				File:       v.filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueNetworkInLoop,
 				Severity:   models.SeverityLevelMedium,
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"sync"
+)
+
+// Options configure an analysis with AnalyzeWithOptions. The zero value runs
+// every analyzer with the default thresholds on the files on disk.
+type Options struct {
+	// Analyzers limits the analysis to the analyzers mapped to true; nil runs
+	// all of them
+	Analyzers map[string]bool
+	// Context stops the analysis between analyzers once it is done
+	Context context.Context
+	// Overlay supplies sources read before the disk, so type checking, source
+	// snippets and fixes all see the overlaid contents
+	Overlay Overlay
+	// Profile, when set, records the cost of each analyzer
+	Profile *Profile
+	// Thresholds are the limits analyzers report against
+	Thresholds Thresholds
+	// Escapes, when set, confirms allocation findings with the compiler's
+	// decisions
+	Escapes *EscapeAnalysis
+	// CustomRules are evaluated by the Custom Rules analyzer
+	CustomRules []CustomRule
+	// NoMemoryCache keeps the results out of the in-memory cache, for callers
+	// that stream results and would otherwise hold every file's issues.
+	// Results still go to the persistent cache when it is enabled.
+	NoMemoryCache bool
+}
+
+// session is what the analyzers of one AnalyzeWithOptions call share: its
+// options and the SSA form of the package, built by the first analyzer that
+// asks for it. Analyzers run on their own have a nil session, which has the
+// zero options and builds the SSA form on every request.
+type session struct {
+	opts    Options
+	ssaOnce sync.Once
+	ssa     *SSA
+}
+
+// sessionAnalyzer is implemented by analyzers that depend on the session they
+// run in. AnalyzeWithOptions passes it before calling Analyze.
+type sessionAnalyzer interface {
+	useSession(s *session)
+}
+
+// inSession is embedded by analyzers to implement sessionAnalyzer
+type inSession struct {
+	session *session
+}
+
+func (is *inSession) useSession(s *session) {
+	is.session = s
+}
+
+func (s *session) context() context.Context {
+	if s == nil || s.opts.Context == nil {
+		return context.Background()
+	}
+	return s.opts.Context
+}
+
+func (s *session) overlay() Overlay {
+	if s == nil {
+		return nil
+	}
+	return s.opts.Overlay
+}
+
+func (s *session) profile() *Profile {
+	if s == nil {
+		return nil
+	}
+	return s.opts.Profile
+}
+
+// thresholds returns the thresholds of the session with defaults filled in
+func (s *session) thresholds() Thresholds {
+	if s == nil {
+		return Thresholds{}.withDefaults()
+	}
+	return s.opts.Thresholds.withDefaults()
+}
+
+func (s *session) customRules() []CustomRule {
+	if s == nil {
+		return nil
+	}
+	return s.opts.CustomRules
+}
+
+// escapeFacts returns the compiler decisions for file attached to its nodes,
+// or nil when escape analysis is off or the package does not build
+func (s *session) escapeFacts(fset *token.FileSet, file *ast.File) *FileEscapes {
+	if s == nil || s.opts.Escapes == nil || !file.Pos().IsValid() {
+		return nil
+	}
+	filename := fset.Position(file.Pos()).Filename
+	facts := s.opts.Escapes.packageFacts(filename, s.opts.Overlay)
+	if facts == nil {
+		return nil
+	}
+	return facts.Attach(fset, file)
+}
+
+// ssaOf returns the SSA form of the package of file, or nil when it does not
+// type-check
+func (s *session) ssaOf(fset *token.FileSet, file *ast.File) *SSA {
+	if file == nil {
+		return nil
+	}
+	if s == nil {
+		ssa, _ := BuildSSA(fset, file)
+		return ssa
+	}
+	s.ssaOnce.Do(func() {
+		s.ssa, _ = buildPackageSSA(fset, file, s.opts.Overlay)
+	})
+	return s.ssa
+}
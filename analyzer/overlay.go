@@ -0,0 +1,31 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Overlay maps absolute file paths to contents that replace the files on disk,
+// in the same way as packages.Config.Overlay
+type Overlay map[string][]byte
+
+// Lookup returns the overlaid content of filename, which may be relative
+func (o Overlay) Lookup(filename string) ([]byte, bool) {
+	if src, ok := o[filename]; ok {
+		return src, true
+	}
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, false
+	}
+	src, ok := o[abs]
+	return src, ok
+}
+
+// readFile returns the content of filename from overlay or the disk
+func readFile(overlay Overlay, filename string) ([]byte, error) {
+	if src, ok := overlay.Lookup(filename); ok {
+		return src, nil
+	}
+	return os.ReadFile(filename)
+}
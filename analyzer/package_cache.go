@@ -0,0 +1,167 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// packageCache memoizes a result computed for a whole package directory
+// until the contents of the package change. Concurrent callers for the same
+// package wait for a single computation.
+type packageCache[T any] struct {
+	mu    sync.Mutex
+	dirs  map[string]*packageEntry[T]
+	limit int // packages kept at most; no limit when 0
+}
+
+type packageEntry[T any] struct {
+	once  sync.Once
+	hash  uint64
+	value T
+}
+
+func newPackageCache[T any]() *packageCache[T] {
+	return &packageCache[T]{dirs: make(map[string]*packageEntry[T])}
+}
+
+// newBoundedPackageCache returns a cache that keeps at most limit packages,
+// for results too large to keep for every package of a tree. Files are
+// analyzed roughly package by package, so dropping an arbitrary package
+// rarely drops one still in use.
+func newBoundedPackageCache[T any](limit int) *packageCache[T] {
+	return &packageCache[T]{dirs: make(map[string]*packageEntry[T]), limit: limit}
+}
+
+// get returns the result for the package in dir whose files are sources,
+// computing it with build if the sources changed since the last call
+func (c *packageCache[T]) get(dir string, sources map[string][]byte, build func() T) T {
+	hash := sourcesHash(sources)
+
+	c.mu.Lock()
+	entry := c.dirs[dir]
+	if entry == nil || entry.hash != hash {
+		if entry == nil && c.limit > 0 && len(c.dirs) >= c.limit {
+			for other := range c.dirs {
+				delete(c.dirs, other)
+				break
+			}
+		}
+		entry = &packageEntry[T]{hash: hash}
+		c.dirs[dir] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() { entry.value = build() })
+	return entry.value
+}
+
+// packageSources reads the non-test Go files in the directory of filename,
+// from overlay before the disk, or returns nil if filename itself cannot be read
+func packageSources(overlay Overlay, filename string) map[string][]byte {
+	src, err := readFile(overlay, filename)
+	if err != nil {
+		return nil
+	}
+	sources := map[string][]byte{filename: src}
+
+	dir := filepath.Dir(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return sources
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if filepath.Base(filename) == name {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if src, err := readFile(overlay, path); err == nil {
+			sources[path] = src
+		}
+	}
+	return sources
+}
+
+// sourcesHash identifies the contents of a package as read by packageSources
+func sourcesHash(sources map[string][]byte) uint64 {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := fnv.New64a()
+	for _, name := range names {
+		_, _ = h.Write([]byte(name))
+		_, _ = h.Write(sources[name])
+	}
+	return h.Sum64()
+}
+
+// compilerOutput builds the package in dir with gcflags and returns what the
+// compiler printed. Overlaid files in dir replace the ones on disk. The build
+// may fail after printing diagnostics, as it does for packages with type
+// errors, so the output is returned either way.
+func compilerOutput(dir string, overlay Overlay, gcflags string) []byte {
+	args := []string{"build", "-gcflags=" + gcflags, "-o", os.DevNull}
+	if replace := dirOverlay(dir, overlay); len(replace) > 0 {
+		tmp, err := os.MkdirTemp("", "aibscleaner-build")
+		if err != nil {
+			return nil
+		}
+		defer func() { _ = os.RemoveAll(tmp) }()
+		overlayFile, err := writeBuildOverlay(tmp, replace)
+		if err != nil {
+			return nil
+		}
+		args = append(args, "-overlay="+overlayFile)
+	}
+	args = append(args, ".")
+
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	// Diagnostics are printed on stderr, with build errors if any
+	out, _ := cmd.CombinedOutput()
+	return out
+}
+
+// dirOverlay returns the overlaid files that are directly in dir
+func dirOverlay(dir string, overlay Overlay) map[string][]byte {
+	replace := make(map[string][]byte)
+	for path, src := range overlay {
+		if filepath.Dir(path) == dir {
+			replace[path] = src
+		}
+	}
+	return replace
+}
+
+// writeBuildOverlay writes the contents of replace to tmp and returns the
+// overlay file that tells `go build` to use them
+func writeBuildOverlay(tmp string, replace map[string][]byte) (string, error) {
+	spec := struct{ Replace map[string]string }{Replace: make(map[string]string, len(replace))}
+	i := 0
+	for path, src := range replace {
+		name := filepath.Join(tmp, strconv.Itoa(i)+".go")
+		if err := os.WriteFile(name, src, 0o600); err != nil {
+			return "", err
+		}
+		spec.Replace[path] = name
+		i++
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	overlayFile := filepath.Join(tmp, "overlay.json")
+	return overlayFile, os.WriteFile(overlayFile, data, 0o600)
+}
@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// FileResult is the outcome of analyzing one file in a pipeline run
+type FileResult struct {
+	Filename string
+	Issues   []*models.Issue
+	Lines    int
+	Err      error
+	Duration time.Duration // wall time of the analysis, set by RunPipeline
+}
+
+// FileFunc analyzes a single file. It should give up early once ctx is done.
+type FileFunc func(ctx context.Context, filename string) FileResult
+
+// PipelineOptions configures RunPipeline
+type PipelineOptions struct {
+	Concurrency int           // files analyzed at once; runtime.NumCPU() when <= 0
+	FileTimeout time.Duration // time budget per file; no limit when <= 0
+}
+
+// RunPipeline analyzes files with a bounded number of workers and passes the
+// results to emit in the order of files, each as soon as it and every file
+// before it are done. At most twice the concurrency results are held at once.
+// A file that exceeds the time budget is reported with an IssueAnalysisTimeout
+// issue and its analysis is abandoned: the worker moves on to the next file and
+// nothing waits for the abandoned analysis, so one that never returns cannot
+// hang the run. It keeps its goroutine until it returns on its own.
+// RunPipeline stops at the first error returned by emit or when ctx is
+// cancelled and returns that error.
+func RunPipeline(
+	ctx context.Context, files []string, analyze FileFunc, opts PipelineOptions, emit func(FileResult) error,
+) error {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = max(1, min(workers, len(files)))
+
+	// Deferred before cancel so that workers are waited for after cancellation
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan FileResult, len(files))
+	for i := range results {
+		results[i] = make(chan FileResult, 1)
+	}
+	// Each dispatched file holds a slot until its result is emitted
+	window := make(chan struct{}, 2*workers)
+	jobs := make(chan int)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				start := time.Now()
+				res := analyzeWithBudget(ctx, files[i], analyze, opts.FileTimeout)
+				res.Duration = time.Since(start)
+				results[i] <- res
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range files {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := range files {
+		select {
+		case res := <-results[i]:
+			<-window
+			if err := emit(res); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return parent.Err()
+		}
+	}
+	return nil
+}
+
+// analyzeWithBudget analyzes filename within budget. When the budget is
+// exceeded it returns without waiting for the analysis, which is left to
+// notice its cancelled context.
+func analyzeWithBudget(ctx context.Context, filename string, analyze FileFunc, budget time.Duration) FileResult {
+	if budget <= 0 {
+		return analyze(ctx, filename)
+	}
+
+	fileCtx, cancel := context.WithTimeout(ctx, budget)
+	done := make(chan FileResult, 1)
+	go func() {
+		defer cancel()
+		done <- analyze(fileCtx, filename)
+	}()
+
+	select {
+	case res := <-done:
+		return res
+	case <-fileCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return FileResult{Filename: filename, Err: err}
+		}
+		return FileResult{Filename: filename, Issues: []*models.Issue{timeoutIssue(filename, budget)}}
+	}
+}
+
+func timeoutIssue(filename string, budget time.Duration) *models.Issue {
+	pos := token.Position{Filename: filename, Line: 1, Column: 1}
+	return &models.Issue{
+		File:       filename,
+		Line:       pos.Line,
+		Column:     pos.Column,
+		Position:   pos,
+		Type:       models.IssueAnalysisTimeout,
+		Severity:   models.IssueAnalysisTimeout.Severity(),
+		Message:    fmt.Sprintf("Analysis did not finish within %s", budget),
+		Suggestion: "Raise the per-file time budget or exclude the file from analysis",
+	}
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func pipelineFiles(n int) []string {
+	files := make([]string, n)
+	for i := range files {
+		files[i] = fmt.Sprintf("file%02d.go", i)
+	}
+	return files
+}
+
+func TestRunPipelineOrderAndConcurrency(t *testing.T) {
+	files := pipelineFiles(20)
+	var running, peak atomic.Int32
+	analyze := func(_ context.Context, filename string) FileResult {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		// Later files finish first to shake the ordering
+		time.Sleep(time.Duration(len(files)-len(filename)%7) * 100 * time.Microsecond)
+		running.Add(-1)
+		return FileResult{Filename: filename, Lines: 1}
+	}
+
+	var got []string
+	err := RunPipeline(context.Background(), files, analyze, PipelineOptions{Concurrency: 3}, func(res FileResult) error {
+		got = append(got, res.Filename)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, files, got)
+	require.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+func TestRunPipelineFileTimeout(t *testing.T) {
+	analyze := func(ctx context.Context, filename string) FileResult {
+		if filename == "file01.go" {
+			<-ctx.Done()
+		}
+		return FileResult{Filename: filename}
+	}
+
+	var results []FileResult
+	opts := PipelineOptions{Concurrency: 2, FileTimeout: 20 * time.Millisecond}
+	err := RunPipeline(context.Background(), pipelineFiles(3), analyze, opts, func(res FileResult) error {
+		results = append(results, res)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Empty(t, results[0].Issues)
+	require.Len(t, results[1].Issues, 1)
+	require.Equal(t, models.IssueAnalysisTimeout, results[1].Issues[0].Type)
+	require.Equal(t, "file01.go", results[1].Issues[0].Position.Filename)
+}
+
+func TestRunPipelineDoesNotWaitForAbandonedAnalyses(t *testing.T) {
+	// file01.go ignores its context and never returns while the test runs
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+	analyze := func(_ context.Context, filename string) FileResult {
+		if filename == "file01.go" {
+			<-release
+		}
+		return FileResult{Filename: filename}
+	}
+
+	returned := make(chan error, 1)
+	var results []FileResult
+	opts := PipelineOptions{Concurrency: 1, FileTimeout: 10 * time.Millisecond}
+	go func() {
+		returned <- RunPipeline(context.Background(), pipelineFiles(4), analyze, opts, func(res FileResult) error {
+			results = append(results, res)
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-returned:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunPipeline waits for an analysis that never returns")
+	}
+	require.Len(t, results, 4)
+	require.Len(t, results[1].Issues, 1)
+	require.Equal(t, models.IssueAnalysisTimeout, results[1].Issues[0].Type)
+	for _, i := range []int{0, 2, 3} {
+		require.Empty(t, results[i].Issues)
+	}
+}
+
+func TestRunPipelineStops(t *testing.T) {
+	analyze := func(_ context.Context, filename string) FileResult {
+		return FileResult{Filename: filename}
+	}
+
+	stop := errors.New("stop")
+	emitted := 0
+	err := RunPipeline(context.Background(), pipelineFiles(10), analyze, PipelineOptions{}, func(FileResult) error {
+		emitted++
+		if emitted == 2 {
+			return stop
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, stop)
+	require.Equal(t, 2, emitted)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = RunPipeline(ctx, pipelineFiles(10), analyze, PipelineOptions{Concurrency: 1}, func(FileResult) error {
+		cancel()
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAnalyzeStopsWhenContextDone(t *testing.T) {
+	code := `package main
+
+import "os"
+
+func main() {
+	for _, name := range os.Args {
+		f, _ := os.Open(name)
+		defer f.Close()
+	}
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "stop.go", code, parser.ParseComments)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := Options{Analyzers: map[string]bool{"loop": true}, Context: ctx}
+	require.Empty(t, AnalyzeWithOptions("stop.go", file, fset, opts))
+}
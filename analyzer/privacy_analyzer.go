@@ -139,11 +139,14 @@ func (p *PrivacyAnalyzer) inspectLiteral(
 
 	if jwtPattern.MatchString(value) || awsKeyPattern.MatchString(value) {
 		pos := fset.Position(lit.Pos())
+		end := fset.Position(lit.End())
 		return []*models.Issue{
 			{
 				File:       filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssuePrivacyHardcodedSecret,
 				Severity:   models.SeverityLevelHigh,
@@ -167,10 +170,13 @@ func (p *PrivacyAnalyzer) newSecretIssue(lit *ast.BasicLit, name string, fset *t
 	}
 
 	pos := fset.Position(lit.Pos())
+	end := fset.Position(lit.End())
 	return &models.Issue{
 		File:       filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssuePrivacyHardcodedSecret,
 		Severity:   models.SeverityLevelHigh,
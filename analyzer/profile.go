@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"runtime/metrics"
+	"sort"
+	"sync"
+	"time"
+)
+
+// allocsMetric counts the bytes allocated on the heap by the whole process
+const allocsMetric = "/gc/heap/allocs:bytes"
+
+// AnalyzerStats is the work done by one analyzer over a run
+type AnalyzerStats struct {
+	Name     string
+	Files    int           // files analyzed; cached results do not count
+	Issues   int           // issues reported, before ignore directives apply
+	Duration time.Duration // wall time spent in the analyzer
+	Allocs   uint64        // bytes allocated while the analyzer ran
+}
+
+// Profile collects AnalyzerStats for the analyses it is passed to in
+// Options.Profile. Allocations are read from process-wide counters that the
+// runtime updates in batches, so they are approximate: small amounts may show
+// up as zero, and other files analyzed at the same time add to them.
+type Profile struct {
+	mu        sync.Mutex
+	analyzers map[string]*AnalyzerStats
+}
+
+// NewProfile returns an empty Profile
+func NewProfile() *Profile {
+	return &Profile{analyzers: make(map[string]*AnalyzerStats)}
+}
+
+// measure runs an analyzer and records its cost. A nil profile only runs it.
+func (p *Profile) measure(name string, run func() int) {
+	if p == nil {
+		run()
+		return
+	}
+
+	sample := []metrics.Sample{{Name: allocsMetric}}
+	metrics.Read(sample)
+	allocsBefore := sample[0].Value.Uint64()
+	start := time.Now()
+
+	issues := run()
+
+	elapsed := time.Since(start)
+	metrics.Read(sample)
+	allocs := sample[0].Value.Uint64() - allocsBefore
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats, ok := p.analyzers[name]
+	if !ok {
+		stats = &AnalyzerStats{Name: name}
+		p.analyzers[name] = stats
+	}
+	stats.Files++
+	stats.Issues += issues
+	stats.Duration += elapsed
+	stats.Allocs += allocs
+}
+
+// moveTime moves time from the stats of one analyzer to those of others, as
+// the time of the shared walk spent in each node analyzer's handlers.
+// Allocations stay where they were measured: the runtime counters are too
+// coarse to split between handlers.
+func (p *Profile) moveTime(from string, to map[string]time.Duration) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	source := p.analyzers[from]
+	for name, d := range to {
+		stats, ok := p.analyzers[name]
+		if source == nil || !ok {
+			continue
+		}
+		source.Duration -= d
+		stats.Duration += d
+	}
+}
+
+// Analyzers returns the stats of every analyzer that ran, slowest first
+func (p *Profile) Analyzers() []AnalyzerStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]AnalyzerStats, 0, len(p.analyzers))
+	for _, s := range p.analyzers {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Duration != stats[j].Duration {
+			return stats[i].Duration > stats[j].Duration
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
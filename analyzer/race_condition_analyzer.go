@@ -8,7 +8,9 @@ import (
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
-type RaceConditionAnalyzer struct{}
+type RaceConditionAnalyzer struct {
+	inSession
+}
 
 func NewRaceConditionAnalyzer() Analyzer {
 	return &RaceConditionAnalyzer{}
@@ -29,7 +31,7 @@ func (rca *RaceConditionAnalyzer) Analyze(node interface{}, fset *token.FileSet)
 		filename = fset.Position(file.Pos()).Filename
 	}
 
-	info, _ := LoadTypes(fset, file, filename)
+	info, _ := loadTypes(fset, file, filename, rca.session.overlay())
 	if info == nil {
 		return nil
 	}
@@ -76,7 +78,7 @@ func (v *raceVisitor) Visit(node ast.Node) ast.Visitor {
 }
 
 func (v *raceVisitor) inspectGo(goStmt *ast.GoStmt) {
-	writes := make(map[types.Object]token.Pos)
+	writes := make(map[types.Object]*ast.Ident)
 
 	ast.Inspect(goStmt.Call, func(n ast.Node) bool {
 		switch stmt := n.(type) {
@@ -92,12 +94,16 @@ func (v *raceVisitor) inspectGo(goStmt *ast.GoStmt) {
 
 	v.inspectFunctionCall(goStmt.Call.Fun, writes)
 
-	for _, pos := range writes {
+	for _, ident := range writes {
+		pos := v.fset.Position(ident.Pos())
+		end := v.fset.Position(ident.End())
 		v.issues = append(v.issues, &models.Issue{
 			File:       v.filename,
-			Line:       v.fset.Position(pos).Line,
-			Column:     v.fset.Position(pos).Column,
-			Position:   v.fset.Position(pos),
+			Line:       pos.Line,
+			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
+			Position:   pos,
 			Type:       models.IssueRaceCondition,
 			Severity:   models.SeverityLevelHigh,
 			Message:    "Write to package-level variable inside goroutine",
@@ -106,7 +112,7 @@ func (v *raceVisitor) inspectGo(goStmt *ast.GoStmt) {
 	}
 }
 
-func (v *raceVisitor) inspectFunctionCall(fun ast.Expr, writes map[types.Object]token.Pos) {
+func (v *raceVisitor) inspectFunctionCall(fun ast.Expr, writes map[types.Object]*ast.Ident) {
 	switch callee := fun.(type) {
 	case *ast.Ident:
 		if obj, ok := v.info.Uses[callee].(*types.Func); ok {
@@ -129,7 +135,7 @@ func (v *raceVisitor) inspectFunctionCall(fun ast.Expr, writes map[types.Object]
 	}
 }
 
-func (v *raceVisitor) inspectFuncBody(fn *types.Func, writes map[types.Object]token.Pos) {
+func (v *raceVisitor) inspectFuncBody(fn *types.Func, writes map[types.Object]*ast.Ident) {
 	decl, ok := v.funcDecls[fn]
 	if !ok || decl.Body == nil {
 		return
@@ -148,7 +154,7 @@ func (v *raceVisitor) inspectFuncBody(fn *types.Func, writes map[types.Object]to
 	})
 }
 
-func (v *raceVisitor) recordWrite(expr ast.Expr, writes map[types.Object]token.Pos) {
+func (v *raceVisitor) recordWrite(expr ast.Expr, writes map[types.Object]*ast.Ident) {
 	ident, ok := expr.(*ast.Ident)
 	if !ok {
 		return
@@ -164,7 +170,7 @@ func (v *raceVisitor) recordWrite(expr ast.Expr, writes map[types.Object]token.P
 
 	if pkgVar(obj) {
 		if _, exists := writes[obj]; !exists {
-			writes[obj] = ident.Pos()
+			writes[obj] = ident
 		}
 	}
 }
@@ -18,60 +18,25 @@ func (ra *ReflectionAnalyzer) Name() string {
 }
 
 func (ra *ReflectionAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
-	file, ok := node.(*ast.File)
-	if !ok {
-		return nil
-	}
-
-	filename := ""
-	if file.Pos().IsValid() {
-		filename = fset.Position(file.Pos()).Filename
-	}
-
-	visitor := &reflectVisitor{
-		fset:      fset,
-		filename:  filename,
-		loopDepth: 0,
-		issues:    make([]*models.Issue, 0, 4),
-	}
-
-	ast.Walk(visitor, file)
-	return visitor.issues
+	return walkAlone(ra, node, fset)
 }
 
-type reflectVisitor struct {
-	fset      *token.FileSet
-	filename  string
-	loopDepth int
-	issues    []*models.Issue
+func (ra *ReflectionAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
+	visitor := &reflectVisitor{issues: make([]*models.Issue, 0, 4)}
+	w.On(visitor.visitCall, (*ast.CallExpr)(nil))
+	return func() []*models.Issue { return visitor.issues }
 }
 
-func (v *reflectVisitor) Visit(node ast.Node) ast.Visitor {
-	switch n := node.(type) {
-	case *ast.ForStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.RangeStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.CallExpr:
-		v.inspectCall(n)
-	}
-	return v
+type reflectVisitor struct {
+	issues []*models.Issue
 }
 
-func (v *reflectVisitor) inspectCall(call *ast.CallExpr) {
-	if v.loopDepth == 0 {
+func (v *reflectVisitor) visitCall(n ast.Node, wc *WalkContext) {
+	call, _ := n.(*ast.CallExpr)
+	if !wc.InLoop() {
 		return
 	}
+
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
@@ -81,11 +46,14 @@ func (v *reflectVisitor) inspectCall(call *ast.CallExpr) {
 		return
 	}
 
-	pos := v.fset.Position(call.Pos())
+	pos := wc.Fset.Position(call.Pos())
+	end := wc.Fset.Position(call.End())
 	v.issues = append(v.issues, &models.Issue{
-		File:       v.filename,
+		File:       wc.Filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssueReflection,
 		Severity:   models.SeverityLevelMedium,
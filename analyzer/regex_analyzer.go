@@ -3,6 +3,7 @@ package analyzer
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
 
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
@@ -18,61 +19,44 @@ func (ra *RegexAnalyzer) Name() string {
 }
 
 func (ra *RegexAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
-	file, ok := node.(*ast.File)
-	if !ok {
-		return nil
-	}
-
-	filename := ""
-	if file.Pos().IsValid() {
-		filename = fset.Position(file.Pos()).Filename
-	}
-
-	visitor := &regexVisitor{
-		fset:      fset,
-		filename:  filename,
-		loopDepth: 0,
-		issues:    make([]*models.Issue, 0, 4),
-	}
+	return walkAlone(ra, node, fset)
+}
 
-	ast.Walk(visitor, file)
-	return visitor.issues
+func (ra *RegexAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
+	visitor := &regexVisitor{issues: make([]*models.Issue, 0, 4)}
+	w.On(visitor.visitCall, (*ast.CallExpr)(nil))
+	return func() []*models.Issue { return visitor.issues }
 }
 
 type regexVisitor struct {
-	fset      *token.FileSet
-	filename  string
-	loopDepth int
-	issues    []*models.Issue
+	issues []*models.Issue
+	// info is loaded for the first fix that needs it
+	info       *types.Info
+	infoLoaded bool
 }
 
-func (v *regexVisitor) Visit(node ast.Node) ast.Visitor {
-	switch n := node.(type) {
-	case *ast.ForStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.RangeStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.CallExpr:
-		v.inspectCall(n)
+func (v *regexVisitor) typeInfo(wc *WalkContext) *types.Info {
+	if !v.infoLoaded {
+		v.infoLoaded = true
+		v.info, _ = loadTypes(wc.Fset, wc.File, wc.Filename, wc.session.overlay())
 	}
-	return v
+	return v.info
 }
 
-func (v *regexVisitor) inspectCall(call *ast.CallExpr) {
-	if v.loopDepth == 0 {
-		return
+// regexpInLoop reports whether call compiles on every iteration of a loop,
+// including in a function literal made in the loop, which usually runs there
+func regexpInLoop(call *ast.CallExpr, wc *WalkContext) bool {
+	if lit, ok := wc.Func.(*ast.FuncLit); ok && wc.InLoop() && lit.Pos() > wc.Loops[len(wc.Loops)-1].Pos() {
+		return true
+	}
+	if inLoop, ok := ssaInLoop(wc, call); ok {
+		return inLoop
 	}
+	return wc.InLoop()
+}
 
+func (v *regexVisitor) visitCall(n ast.Node, wc *WalkContext) {
+	call, _ := n.(*ast.CallExpr)
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
 		return
@@ -86,16 +70,28 @@ func (v *regexVisitor) inspectCall(call *ast.CallExpr) {
 	if sel.Sel.Name != methodCompile && sel.Sel.Name != methodMustCompile {
 		return
 	}
+	if !regexpInLoop(call, wc) {
+		return
+	}
 
-	pos := v.fset.Position(call.Pos())
+	pos := wc.Fset.Position(call.Pos())
+	end := wc.Fset.Position(call.End())
+	var fix *models.SuggestedFix
+	if isHoistableRegexp(call) {
+		fix = hoistRegexpFix(wc.Fset, wc.File, v.typeInfo(wc), call)
+	}
 	v.issues = append(v.issues, &models.Issue{
-		File:       v.filename,
+		File:       wc.Filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssueRegexCompileInLoop,
-		Severity:   models.SeverityLevelMedium,
+		Severity:   models.IssueRegexCompileInLoop.Severity(),
 		Message:    "Regular expression compiled inside loop",
 		Suggestion: "Compile regex once outside loop and reuse",
+		CanBeFixed: fix != nil,
+		Fix:        fix,
 	})
 }
@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// Registration is an analyzer Analyze can run
+type Registration struct {
+	// Name selects the analyzer in enabled-analyzer maps and in the
+	// analyzers.plugins configuration section, e.g. "ourlog"
+	Name string
+	// Description is shown by list-analyzers
+	Description string
+	// New returns the analyzer for one file
+	New func() Analyzer
+	// Disabled keeps the analyzer off unless the configuration enables it
+	Disabled bool
+	// Rules are the issue types the analyzer reports, usually added with
+	// models.RegisterRule
+	Rules []models.IssueType
+}
+
+var (
+	registryMu sync.RWMutex
+	// registry holds the built-in analyzers in the order they run, followed by
+	// the analyzers added with Register
+	registry = []Registration{
+		// Performance analyzers (unique to this tool)
+		{Name: "loop", Description: "Deeply nested loops, quadratic loop nests and defers in loops", New: NewLoopAnalyzer,
+			Rules: rulesOf(models.AnalyzerLoop)},
+		{Name: "deferoptimization", Description: "Defers that cost more than calling the cleanup directly", New: NewDeferOptimizationAnalyzer,
+			Rules: rulesOf(models.AnalyzerDeferOptimization, models.IssueDeferInLoop)},
+		{Name: "slice", Description: "Slices grown in loops without capacity", New: NewSliceAnalyzer,
+			Rules: rulesOf(models.AnalyzerSlice)},
+		{Name: "map", Description: "Maps created in loops without a size hint", New: NewMapAnalyzer,
+			Rules: rulesOf(models.AnalyzerMap)},
+		{Name: "reflection", Description: "Reflection in loops", New: NewReflectionAnalyzer,
+			Rules: rulesOf(models.AnalyzerReflection)},
+		{Name: "interface", Description: "Interface conversions and assertions in loops", New: NewInterfaceAnalyzer,
+			Rules: rulesOf(models.AnalyzerInterface)},
+		{Name: "regex", Description: "Regular expressions compiled in loops", New: NewRegexAnalyzer,
+			Rules: rulesOf(models.AnalyzerRegex)},
+		{Name: "time", Description: "Clock reads and timers in loops", New: NewTimeAnalyzer,
+			Rules: rulesOf(models.AnalyzerTime)},
+		{Name: "memoryleak", Description: "Resources opened without a matching Close", New: NewMemoryLeakAnalyzer,
+			Rules: rulesOf(models.AnalyzerMemoryLeak)},
+		{Name: "database", Description: "N+1 queries, unclosed rows and transactions without rollback", New: NewDatabaseAnalyzer,
+			Rules: rulesOf(models.AnalyzerDatabase)},
+
+		// Specialized analyzers (not covered by standard linters)
+		{Name: "apimisuse", Description: "Misused standard library APIs such as Sprintf joins, recover and pprof", New: NewAPIMisuseAnalyzer,
+			Rules: rulesOf(models.AnalyzerAPIMisuse, models.IssueRegexCompileInLoop, models.IssueTimeNowInLoop)},
+		{Name: "aibullshit", Description: "Over-engineered and generated-looking code", New: NewAIBullshitAnalyzer,
+			Rules: rulesOf(models.AnalyzerAIBullshit)},
+		{Name: "goroutine", Description: "Unbounded goroutines and goroutines capturing loop variables", New: NewGoroutineAnalyzer,
+			Rules: rulesOf(models.AnalyzerGoroutine)},
+		{Name: "channel", Description: "Channel deadlocks, double closes and sends on closed channels", New: NewChannelAnalyzer,
+			Rules: rulesOf(models.AnalyzerChannel)},
+		{Name: "httpclient", Description: "HTTP clients without timeouts", New: NewHTTPClientAnalyzer,
+			Rules: rulesOf(models.AnalyzerHTTPClient)},
+		{Name: "context", Description: "Context parameter order and context keys", New: NewContextAnalyzer,
+			Rules: rulesOf(models.AnalyzerContext)},
+		{Name: "racecondition", Description: "Unsynchronized writes to shared variables from goroutines", New: NewRaceConditionAnalyzer,
+			Rules: rulesOf(models.AnalyzerRaceCondition)},
+		{Name: "concurrencypatterns", Description: "WaitGroup, context and closure mistakes around goroutines", New: NewConcurrencyPatternsAnalyzer,
+			Rules: rulesOf(models.AnalyzerConcurrencyPatterns, models.IssueGoroutineCapturesLoop)},
+		{Name: "networkpatterns", Description: "Dials and requests in loops", New: NewNetworkPatternsAnalyzer,
+			Rules: rulesOf(models.AnalyzerNetworkPatterns)},
+		{Name: "cpuoptimization", Description: "Work recomputed in loop conditions", New: NewCPUOptimizationAnalyzer,
+			Rules: rulesOf(models.AnalyzerCPUOptimization)},
+		{Name: "gcpressure", Description: "Allocations in loops that keep the garbage collector busy", New: NewGCPressureAnalyzer,
+			Rules: rulesOf(models.AnalyzerGCPressure, models.IssueAllocInLoop)},
+		{Name: "syncpool", Description: "sync.Pool objects that are never put back", New: NewSyncPoolAnalyzer,
+			Rules: rulesOf(models.AnalyzerSyncPool)},
+
+		// New performance analyzers
+		{Name: "cgo", Description: "cgo calls in loops and C memory that is never freed", New: NewCGOAnalyzer,
+			Rules: rulesOf(models.AnalyzerCGO)},
+		{Name: "serialization", Description: "Encoding and decoding in loops", New: NewSerializationAnalyzer,
+			Rules: rulesOf(models.AnalyzerSerialization)},
+		{Name: "crypto", Description: "Weak hashes and predictable random numbers", New: NewCryptoAnalyzer,
+			Rules: rulesOf(models.AnalyzerCrypto)},
+		{Name: "httpreuse", Description: "HTTP requests that bypass a shared client", New: NewHTTPReuseAnalyzer,
+			Rules: rulesOf(models.AnalyzerHTTPReuse)},
+		{Name: "iobuffer", Description: "Whole-file reads and writes in loops", New: NewIOBufferAnalyzer,
+			Rules: rulesOf(models.AnalyzerIOBuffer)},
+
+		// Security/privacy (specialized)
+		{Name: "privacy", Description: "Hard-coded secrets and leaked personal data", New: NewPrivacyAnalyzer,
+			Rules: rulesOf(models.AnalyzerPrivacy)},
+
+		// Struct layout optimization
+		{Name: "structlayout", Description: "Struct fields ordered with avoidable padding", New: NewStructLayoutAnalyzer,
+			Rules: rulesOf(models.AnalyzerStructLayout)},
+
+		// CPU cache optimization
+		{Name: "cpucache", Description: "False sharing and cache-unfriendly data layouts", New: NewCPUCacheAnalyzer,
+			Rules: rulesOf(models.AnalyzerCPUCache)},
+
+		// Expensive calls one or more function calls away from a loop
+		{Name: "hotpath", Description: "Expensive calls one or more function calls away from a loop", New: NewHotPathAnalyzer,
+			Rules: rulesOf(models.AnalyzerHotPath, models.IssueRegexCompileInLoop, models.IssueTimeNowInLoop, models.IssueJSONMarshalInLoop)},
+
+		// Declarative rules from the configuration, idle without any
+		{Name: CustomRulesAnalyzerName, Description: "Rules from the custom_rules configuration section", New: NewCustomRulesAnalyzer},
+
+		// Compiler bounds checks in loops (builds the package, disabled by default in config)
+		{Name: "bce", Description: "Bounds checks the compiler keeps inside loops", New: NewBoundsCheckAnalyzer, Disabled: true,
+			Rules: rulesOf(models.AnalyzerBoundsCheck)},
+
+		// Testing (usually noisy, disabled by default in config)
+		{Name: "testcoverage", Description: "Exported code without tests, examples or benchmarks", New: NewTestCoverageAnalyzer, Disabled: true,
+			Rules: rulesOf(models.AnalyzerTestCoverage)},
+	}
+	builtinCount = len(registry)
+)
+
+// rulesOf returns the issue types analyzer a reports according to the rule
+// registry, followed by the types of other analyzers it reports as well
+func rulesOf(a models.AnalyzerType, shared ...models.IssueType) []models.IssueType {
+	return append(models.RulesOf(a), shared...)
+}
+
+// Register adds an analyzer that Analyze runs after the built-in ones, so
+// other modules can ship analyzers without changing this one. It is meant to
+// be called from init functions and panics if reg has no name or
+// constructor, or if its name is taken.
+func Register(reg Registration) {
+	if reg.Name == "" || reg.New == nil {
+		panic("analyzer: Register needs a name and a constructor")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, existing := range registry {
+		if existing.Name == reg.Name {
+			panic(fmt.Sprintf("analyzer: Register called twice for %q", reg.Name))
+		}
+	}
+	registry = append(registry, reg)
+}
+
+// Registered returns the built-in and registered analyzers in the order
+// Analyze runs them
+func Registered() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[:len(registry):len(registry)]
+}
+
+// Plugins returns the analyzers added with Register
+func Plugins() []Registration {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[builtinCount:len(registry):len(registry)]
+}
+
+// CheckedRules returns the issue types Analyze looks for when it runs the
+// analyzers in enabledAnalyzers (all of them when nil) with the custom rules
+// rules. Ignore directives for other types cannot be known to be unused.
+func CheckedRules(enabledAnalyzers map[string]bool, rules []CustomRule) map[models.IssueType]bool {
+	checked := make(map[models.IssueType]bool)
+	for _, reg := range Registered() {
+		if enabledAnalyzers != nil && !enabledAnalyzers[reg.Name] {
+			continue
+		}
+		for _, t := range reg.Rules {
+			checked[t] = true
+		}
+		if reg.Name == CustomRulesAnalyzerName {
+			for _, rule := range rules {
+				checked[rule.Type] = true
+			}
+		}
+	}
+	return checked
+}
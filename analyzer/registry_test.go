@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+type registryTestAnalyzer struct{}
+
+func (registryTestAnalyzer) Name() string { return "Registry Test" }
+
+func (registryTestAnalyzer) Analyze(interface{}, *token.FileSet) []*models.Issue { return nil }
+
+func TestRegister(t *testing.T) {
+	Register(Registration{
+		Name:     "registrytest",
+		New:      func() Analyzer { return registryTestAnalyzer{} },
+		Disabled: true,
+	})
+
+	all := Registered()
+	if last := all[len(all)-1]; last.Name != "registrytest" {
+		t.Errorf("last registered analyzer = %q, want registrytest", last.Name)
+	}
+	if all[0].Name != "loop" {
+		t.Errorf("first analyzer = %q, want the built-in loop analyzer", all[0].Name)
+	}
+	plugins := Plugins()
+	if len(plugins) != 1 || plugins[0].Name != "registrytest" || !plugins[0].Disabled {
+		t.Errorf("plugins = %+v", plugins)
+	}
+
+	for _, reg := range []Registration{
+		{Name: "registrytest", New: func() Analyzer { return registryTestAnalyzer{} }},
+		{Name: "loop", New: NewLoopAnalyzer},
+		{Name: "nonew"},
+		{New: NewLoopAnalyzer},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register(%q) did not panic", reg.Name)
+				}
+			}()
+			Register(reg)
+		}()
+	}
+}
+
+// scaledSeverities lists the issues analyzers report with a severity other
+// than their rule's, by analyzer, issue type and severity, with the reason
+var scaledSeverities = map[string]string{
+	"aibullshit AIUnnecessaryComplexity Medium": "deep nesting without many branches",
+	"cgo CGOInLoop Medium":                      "call in a loop; the default covers functions on hot paths",
+	"cgo CGOInLoop High":                        "call in nested loops",
+	"database SQLNPlusOne Low":                  "SELECT * only wastes bandwidth",
+	"httpreuse HTTPNoConnectionReuse Low":       "RoundTrip in a loop may reuse its transport",
+	"iobuffer UnbufferedIO Low":                 "io.ReadAll and io.Copy often read from memory",
+	"map MapCapacity Low":                       "map created in a loop with a capacity hint",
+}
+
+// TestBuiltinRulesMatchIssues runs the built-in analyzers over the code
+// samples of this package's tests and fails when one reports an issue type
+// its registration does not list, a severity other than its rule's that
+// scaledSeverities does not explain, a fix for a type the rule registry does
+// not mark fixable, or a type that explain has no documentation for
+func TestBuiltinRulesMatchIssues(t *testing.T) {
+	samples := testSamples(t)
+	if len(samples) < 100 {
+		t.Fatalf("found only %d code samples", len(samples))
+	}
+
+	reported := make(map[models.IssueType]bool)
+	problems := make(map[string]bool)
+	for _, reg := range Registered()[:builtinCount] {
+		if reg.Description == "" {
+			t.Errorf("%s: no description", reg.Name)
+		}
+		if reg.Name == CustomRulesAnalyzerName {
+			continue
+		}
+		if len(reg.Rules) == 0 {
+			t.Errorf("%s: no rules", reg.Name)
+		}
+		for _, sample := range samples {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "", sample, parser.ParseComments)
+			if err != nil {
+				continue
+			}
+			for _, issue := range reg.New().Analyze(file, fset) {
+				reported[issue.Type] = true
+				if !slices.Contains(reg.Rules, issue.Type) {
+					problems[reg.Name+" reports "+issue.Type.String()+", which its registration does not list"] = true
+				}
+				scaled := reg.Name + " " + issue.Type.String() + " " + issue.Severity.String()
+				if _, explained := scaledSeverities[scaled]; !explained && issue.Severity != issue.Type.Severity() {
+					problems[reg.Name+" reports "+issue.Type.String()+" as "+issue.Severity.String()+", the rule registry says "+
+						issue.Type.Severity().String()] = true
+				}
+				if issue.Fix != nil && !issue.Type.Fixable() {
+					problems[reg.Name+" fixes "+issue.Type.String()+", which the rule registry does not mark fixable"] = true
+				}
+				if rule, _ := issue.Type.Rule(); rule.Bad == "" || rule.Why == "" {
+					problems[reg.Name+" reports "+issue.Type.String()+", which has no examples or models/ruledocs entry"] = true
+				}
+			}
+		}
+	}
+	for _, problem := range slices.Sorted(maps.Keys(problems)) {
+		t.Error(problem)
+	}
+	if len(reported) < 40 {
+		t.Errorf("samples only exercise %d issue types", len(reported))
+	}
+}
+
+// testSamples returns the string literals of this package's tests that hold
+// a Go file
+func testSamples(t *testing.T) []string {
+	t.Helper()
+	names, err := filepath.Glob("*_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var samples []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		file, err := parser.ParseFile(token.NewFileSet(), name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+			s, err := strconv.Unquote(lit.Value)
+			if err == nil && strings.HasPrefix(strings.TrimSpace(s), "package ") && !seen[s] {
+				seen[s] = true
+				samples = append(samples, s)
+			}
+			return true
+		})
+	}
+	return samples
+}
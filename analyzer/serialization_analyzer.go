@@ -86,10 +86,13 @@ func (v *serializationVisitor) inspectCall(call *ast.CallExpr) {
 	if pkgIdent.Name == pkgJSON {
 		if sel.Sel.Name == methodMarshal || sel.Sel.Name == methodUnmarshal {
 			pos := v.fset.Position(call.Pos())
+			end := v.fset.Position(call.End())
 			v.issues = append(v.issues, &models.Issue{
 				File:       v.filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueSerializationInLoop,
 				Severity:   models.SeverityLevelMedium,
@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// attachSourceCode fills Issue.Code with the source span each issue points at.
+// Issues that already carry code or have no line are left untouched.
+func attachSourceCode(filename string, overlay Overlay, issues []*models.Issue) {
+	if filename == "" || len(issues) == 0 {
+		return
+	}
+
+	src, err := readFile(overlay, filename)
+	if err != nil {
+		return
+	}
+	FillIssueCode(issues, src)
+}
+
+// FillIssueCode fills Issue.Code from src for issues that do not have it yet.
+// Code is the reported span, from the start position to the end position,
+// with both ends clamped to their lines. Issues without an end position get
+// the rest of their line. The column range Code covers is stored in Column,
+// EndLine and EndColumn.
+func FillIssueCode(issues []*models.Issue, src []byte) {
+	var lines [][]byte
+	for _, issue := range issues {
+		if issue == nil || issue.Code != "" {
+			continue
+		}
+		line := issue.Line
+		if line == 0 {
+			line = issue.Position.Line
+		}
+		if line <= 0 {
+			continue
+		}
+
+		if lines == nil {
+			lines = bytes.Split(src, []byte("\n"))
+		}
+		if line > len(lines) {
+			continue
+		}
+
+		column := issue.Position.Column
+		if column == 0 {
+			column = issue.Column
+		}
+		endLine, endColumn := issue.EndLine, issue.EndColumn
+		if endLine < line || endLine > len(lines) || (endLine == line && endColumn <= column) {
+			endLine, endColumn = line, 0
+		}
+
+		start := lineColumn(lines[line-1], column)
+		if endColumn == 0 {
+			endColumn = len(bytes.TrimRight(lines[endLine-1], " \t\r")) + 1
+		}
+		end := lineColumn(lines[endLine-1], endColumn)
+
+		var code []byte
+		if endLine == line {
+			code = lines[line-1][start-1 : end-1]
+		} else {
+			code = append(code, lines[line-1][start-1:]...)
+			for _, l := range lines[line : endLine-1] {
+				code = append(append(code, '\n'), l...)
+			}
+			code = append(append(code, '\n'), lines[endLine-1][:end-1]...)
+		}
+
+		issue.Code = string(bytes.TrimRight(code, " \t\r"))
+		issue.Line = line
+		issue.Column = start
+		issue.EndLine = endLine
+		issue.EndColumn = end
+	}
+}
+
+// lineColumn clamps the 1-based byte column to the bounds of l, where
+// len(l)+1 is the column just past its last byte
+func lineColumn(l []byte, column int) int {
+	l = bytes.TrimRight(l, "\r")
+	return min(max(column, 1), len(l)+1)
+}
+
+// readSource returns the source file was parsed from, read from overlay before
+// the disk, or nil when it is not available or has changed since parsing
+func readSource(fset *token.FileSet, file *ast.File, overlay Overlay) []byte {
+	if file == nil {
+		return nil
+	}
+	return readTokenFile(fset.File(file.Pos()), overlay)
+}
+
+// readTokenFile returns the source of tf, read from overlay before the disk,
+// or nil when it is not available or has changed since parsing
+func readTokenFile(tf *token.File, overlay Overlay) []byte {
+	if tf == nil {
+		return nil
+	}
+	src, err := readFile(overlay, tf.Name())
+	if err != nil || len(src) != tf.Size() {
+		return nil
+	}
+	return src
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func TestFillIssueCode(t *testing.T) {
+	src := []byte("package main\n\nfunc main() {\n\tx := 1   \r\n}\n")
+	issues := []*models.Issue{
+		{Line: 4},
+		{Position: token.Position{Line: 3}},
+		{Line: 4, Code: "kept"},
+		{Line: 42},
+		nil,
+	}
+
+	FillIssueCode(issues, src)
+
+	require.Equal(t, "\tx := 1", issues[0].Code)
+	require.Equal(t, "func main() {", issues[1].Code)
+	require.Equal(t, "kept", issues[2].Code)
+	require.Empty(t, issues[3].Code)
+}
+
+func TestFillIssueCodeSlicesSpan(t *testing.T) {
+	src := []byte("package main\n\nfunc main() {\n\tx := fmt.Sprintf(\"%d\",\n\t\tn)\n}\n")
+	issues := []*models.Issue{
+		{Position: token.Position{Line: 4, Column: 7}, EndLine: 4, EndColumn: 18},
+		{Position: token.Position{Line: 4, Column: 7}, EndLine: 5, EndColumn: 5},
+		{Position: token.Position{Line: 4, Column: 7}, EndLine: 4, EndColumn: 80},
+		{Position: token.Position{Line: 3, Column: 6}},
+	}
+
+	FillIssueCode(issues, src)
+
+	require.Equal(t, "fmt.Sprintf", issues[0].Code)
+	require.Equal(t, []int{4, 7, 4, 18}, []int{issues[0].Line, issues[0].Column, issues[0].EndLine, issues[0].EndColumn})
+	require.Equal(t, "fmt.Sprintf(\"%d\",\n\t\tn)", issues[1].Code)
+	require.Equal(t, []int{7, 5, 5}, []int{issues[1].Column, issues[1].EndLine, issues[1].EndColumn})
+	require.Equal(t, "fmt.Sprintf(\"%d\",", issues[2].Code)
+	require.Equal(t, 24, issues[2].EndColumn)
+	require.Equal(t, "main() {", issues[3].Code)
+	require.Equal(t, []int{6, 3, 14}, []int{issues[3].Column, issues[3].EndLine, issues[3].EndColumn})
+}
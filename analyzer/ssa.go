@@ -0,0 +1,500 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/static"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// SSA is the SSA form of the package an analyzed file belongs to. It answers
+// the control-flow questions analyzers otherwise answer by walking the syntax
+// tree: which loops enclose an expression, whether one expression always runs
+// before another, where a value flows and which functions call which. Every
+// query is a map lookup or a walk over the values involved, not over the file.
+//
+// The form of a package is built once per version of its sources and shared
+// by the analyses of all its files, so it usually comes from another parse
+// than the nodes it is queried with; nodes are matched by file and extent.
+type SSA struct {
+	Package *ssa.Package
+	// Fset holds the positions of Package
+	Fset *token.FileSet
+
+	fset  *token.FileSet // holds the positions of the nodes queries are given
+	index *ssaIndex
+}
+
+// ssaIndex maps the syntax of a package to its SSA form
+type ssaIndex struct {
+	refs  map[nodeKey]*ssa.DebugRef // expressions to the code computing them
+	loops map[*ssa.Function]*loopNest
+	decls map[nodeKey]*ssa.Function
+
+	callsOnce sync.Once
+	calls     *callgraph.Graph
+
+	// facts holds what analyzers derive from the whole package, by name
+	facts sync.Map
+}
+
+// nodeKey identifies a node across parses of the same source
+type nodeKey struct {
+	file       string
+	start, end int
+	kind       reflect.Type
+}
+
+func keyOf(fset *token.FileSet, n ast.Node) (nodeKey, bool) {
+	tf := fset.File(n.Pos())
+	if tf == nil || !n.End().IsValid() {
+		return nodeKey{}, false
+	}
+	return nodeKey{file: tf.Name(), start: tf.Offset(n.Pos()), end: tf.Offset(n.End()), kind: reflect.TypeOf(n)}, true
+}
+
+// Loop is a natural loop of a function: the blocks from which a back edge to
+// Header can be reached without passing through Header
+type Loop struct {
+	Header *ssa.BasicBlock
+	Parent *Loop // enclosing loop; nil for outermost loops
+	Depth  int   // 1 for outermost loops
+	blocks map[*ssa.BasicBlock]bool
+}
+
+// Contains reports whether b is part of the loop, including nested loops
+func (l *Loop) Contains(b *ssa.BasicBlock) bool {
+	return l.blocks[b]
+}
+
+// loopNest holds the loops of one function and the innermost loop of each
+// block, indexed by block index
+type loopNest struct {
+	loops     []*Loop
+	innermost []*Loop
+}
+
+// ssaBuild is the SSA form of a package or the error building it
+type ssaBuild struct {
+	ssa *SSA
+	err error
+}
+
+// ssaPackages caches the SSA form of the packages analyzed last
+var ssaPackages = newBoundedPackageCache[ssaBuild](ssaPackageLimit)
+
+// ssaPackageLimit is the number of packages whose SSA form is kept
+const ssaPackageLimit = 32
+
+// BuildSSA type-checks the package of file, reading its other files from the
+// same directory, and builds its SSA form. Imports are resolved from compiled
+// export data, so the package and its dependencies must type-check, and
+// packages using cgo have no form. The form
+// is reused until the sources of the package change; a file that cannot be
+// read back is built on its own.
+func BuildSSA(fset *token.FileSet, file *ast.File) (*SSA, error) {
+	return buildPackageSSA(fset, file, nil)
+}
+
+// buildPackageSSA is BuildSSA with the sources of the package read from
+// overlay before the disk
+func buildPackageSSA(fset *token.FileSet, file *ast.File, overlay Overlay) (*SSA, error) {
+	var sources map[string][]byte
+	if file.Pos().IsValid() {
+		sources = packageSources(overlay, fset.Position(file.Pos()).Filename)
+	}
+	if sources == nil {
+		return buildSSA(fset, []*ast.File{file})
+	}
+
+	filename := fset.Position(file.Pos()).Filename
+	// Files of another package in the directory are left out, so the form
+	// depends on the package name as well
+	key := filepath.Dir(filename) + string(filepath.Separator) + file.Name.Name
+	built := ssaPackages.get(key, sources, func() ssaBuild {
+		names := make([]string, 0, len(sources))
+		for name := range sources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		pkgFset := token.NewFileSet()
+		files := make([]*ast.File, 0, len(names))
+		for _, name := range names {
+			parsed, err := parser.ParseFile(pkgFset, name, sources[name], parser.SkipObjectResolution)
+			if err == nil && parsed.Name.Name == file.Name.Name {
+				files = append(files, parsed)
+			}
+		}
+		s, err := buildSSA(pkgFset, files)
+		return ssaBuild{ssa: s, err: err}
+	})
+	if built.err != nil {
+		return nil, built.err
+	}
+	view := *built.ssa
+	view.fset = fset
+	return &view, nil
+}
+
+// buildSSA builds the SSA form of a package from its files
+func buildSSA(fset *token.FileSet, files []*ast.File) (*SSA, error) {
+	// The type checker can fake the C package, but the SSA builder has no
+	// code for its members
+	for _, file := range files {
+		for _, imp := range file.Imports {
+			if imp.Path.Value == `"C"` {
+				return nil, fmt.Errorf("package %s uses cgo", files[0].Name.Name)
+			}
+		}
+	}
+
+	conf := &types.Config{Importer: importer.Default()}
+	pkg := types.NewPackage(files[0].Name.Name, files[0].Name.Name)
+	ssaPkg, _, err := ssautil.BuildPackage(conf, fset, pkg, files, ssa.GlobalDebug)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SSA{
+		Package: ssaPkg,
+		Fset:    fset,
+		fset:    fset,
+		index: &ssaIndex{
+			refs:  make(map[nodeKey]*ssa.DebugRef),
+			loops: make(map[*ssa.Function]*loopNest),
+			decls: make(map[nodeKey]*ssa.Function),
+		},
+	}
+	for _, member := range ssaPkg.Members {
+		switch member := member.(type) {
+		case *ssa.Function:
+			s.addFunc(member)
+		case *ssa.Type:
+			named, ok := member.Type().(*types.Named)
+			if !ok {
+				continue
+			}
+			for i := 0; i < named.NumMethods(); i++ {
+				if fn := ssaPkg.Prog.FuncValue(named.Method(i)); fn != nil {
+					s.addFunc(fn)
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// addFunc records the code and loops of fn and the closures it contains
+func (s *SSA) addFunc(fn *ssa.Function) {
+	if decl, ok := fn.Syntax().(*ast.FuncDecl); ok && fn.Parent() == nil {
+		if key, ok := keyOf(s.Fset, decl); ok {
+			s.index.decls[key] = fn
+		}
+	}
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			if ref, ok := instr.(*ssa.DebugRef); ok {
+				key, ok := keyOf(s.Fset, ref.Expr)
+				if _, seen := s.index.refs[key]; ok && !seen {
+					s.index.refs[key] = ref
+				}
+			}
+		}
+	}
+	s.index.loops[fn] = findLoops(fn)
+	for _, anon := range fn.AnonFuncs {
+		s.addFunc(anon)
+	}
+}
+
+// Func returns the SSA function built for decl, or nil
+func (s *SSA) Func(decl *ast.FuncDecl) *ssa.Function {
+	if key, ok := keyOf(s.fset, decl); ok {
+		return s.index.decls[key]
+	}
+	return nil
+}
+
+// Value returns the value expr evaluates to, or nil if expr has no code of
+// its own, like constants and dead code
+func (s *SSA) Value(expr ast.Expr) ssa.Value {
+	if ref := s.ref(expr); ref != nil {
+		return ref.X
+	}
+	return nil
+}
+
+// Block returns the basic block that evaluates expr, or nil
+func (s *SSA) Block(expr ast.Expr) *ssa.BasicBlock {
+	if ref := s.ref(expr); ref != nil {
+		return ref.Block()
+	}
+	return nil
+}
+
+// ref finds the code for expr, falling back to its first operand with code
+// for expressions that have none themselves, like go and defer calls
+func (s *SSA) ref(expr ast.Expr) *ssa.DebugRef {
+	lookup := func(e ast.Expr) *ssa.DebugRef {
+		if key, ok := keyOf(s.fset, e); ok {
+			return s.index.refs[key]
+		}
+		return nil
+	}
+	if ref := lookup(ast.Unparen(expr)); ref != nil {
+		return ref
+	}
+	var found *ssa.DebugRef
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if e, ok := n.(ast.Expr); ok && found == nil {
+			found = lookup(e)
+		}
+		return found == nil
+	})
+	return found
+}
+
+// Loop returns the innermost loop that evaluates expr, or nil
+func (s *SSA) Loop(expr ast.Expr) *Loop {
+	b := s.Block(expr)
+	if b == nil {
+		return nil
+	}
+	return s.loopOf(b)
+}
+
+// loopOf returns the innermost loop containing b, or nil
+func (s *SSA) loopOf(b *ssa.BasicBlock) *Loop {
+	nest, ok := s.index.loops[b.Parent()]
+	if !ok {
+		return nil
+	}
+	return nest.innermost[b.Index]
+}
+
+// LoopDepth returns the number of loops around expr within its function.
+// ok is false when expr has no code to look at.
+func (s *SSA) LoopDepth(expr ast.Expr) (depth int, ok bool) {
+	b := s.Block(expr)
+	if b == nil {
+		return 0, false
+	}
+	if loop := s.loopOf(b); loop != nil {
+		return loop.Depth, true
+	}
+	return 0, true
+}
+
+// Loops returns the loops of fn, each after the loops that enclose it
+func (s *SSA) Loops(fn *ssa.Function) []*Loop {
+	if nest, ok := s.index.loops[fn]; ok {
+		return nest.loops
+	}
+	return nil
+}
+
+// Dominates reports whether a is evaluated before b on every path to b in
+// the same function
+func (s *SSA) Dominates(a, b ast.Expr) bool {
+	refA, refB := s.ref(a), s.ref(b)
+	if refA == nil || refB == nil || refA.Parent() != refB.Parent() {
+		return false
+	}
+	blockA, blockB := refA.Block(), refB.Block()
+	if blockA != blockB {
+		return blockA.Dominates(blockB)
+	}
+	for _, instr := range blockA.Instrs {
+		switch instr {
+		case refA:
+			return true
+		case refB:
+			return false
+		}
+	}
+	return false
+}
+
+// Flows returns the values v reaches within its function and the closures it
+// creates: the results of operations that take v, the loads of variables v is
+// stored into, and the free variables v is captured as
+func (s *SSA) Flows(v ssa.Value) []ssa.Value {
+	seen := map[ssa.Value]bool{v: true}
+	queue := []ssa.Value{v}
+	var reached []ssa.Value
+	visit := func(next ssa.Value) {
+		if !seen[next] {
+			seen[next] = true
+			reached = append(reached, next)
+			queue = append(queue, next)
+		}
+	}
+
+	for len(queue) > 0 {
+		value := queue[0]
+		queue = queue[1:]
+		refs := value.Referrers()
+		if refs == nil {
+			continue
+		}
+		for _, instr := range *refs {
+			switch instr := instr.(type) {
+			case *ssa.Store:
+				// The loads of the address follow from its referrers
+				if instr.Val == value {
+					visit(instr.Addr)
+				}
+			case *ssa.MakeClosure:
+				fn, _ := instr.Fn.(*ssa.Function)
+				for i, binding := range instr.Bindings {
+					if binding == value && fn != nil {
+						visit(fn.FreeVars[i])
+					}
+				}
+				visit(instr)
+			case ssa.Value:
+				visit(instr)
+			}
+		}
+	}
+	return reached
+}
+
+// Callees returns the functions fn calls directly, through static calls
+func (s *SSA) Callees(fn *ssa.Function) []*ssa.Function {
+	node := s.callGraph().Nodes[fn]
+	if node == nil {
+		return nil
+	}
+	callees := make([]*ssa.Function, 0, len(node.Out))
+	for _, edge := range node.Out {
+		callees = append(callees, edge.Callee.Func)
+	}
+	return callees
+}
+
+// Callers returns the functions that call fn directly, through static calls
+func (s *SSA) Callers(fn *ssa.Function) []*ssa.Function {
+	node := s.callGraph().Nodes[fn]
+	if node == nil {
+		return nil
+	}
+	callers := make([]*ssa.Function, 0, len(node.In))
+	for _, edge := range node.In {
+		callers = append(callers, edge.Caller.Func)
+	}
+	return callers
+}
+
+// callSites returns the static calls fn makes, in the order of its
+// instructions
+func (s *SSA) callSites(fn *ssa.Function) []*callgraph.Edge {
+	if node := s.callGraph().Nodes[fn]; node != nil {
+		return node.Out
+	}
+	return nil
+}
+
+func (s *SSA) callGraph() *callgraph.Graph {
+	s.index.callsOnce.Do(func() {
+		s.index.calls = static.CallGraph(s.Package.Prog)
+	})
+	return s.index.calls
+}
+
+// ssaFact is a result derived from the whole package, computed once
+type ssaFact struct {
+	once  sync.Once
+	value any
+}
+
+// packageFact returns what build derives from the package of s, computing it
+// once per form and sharing it with the analyses of the other files. build
+// must only use the positions of s.Fset.
+func packageFact[T any](s *SSA, name string, build func() T) T {
+	v, _ := s.index.facts.LoadOrStore(name, &ssaFact{})
+	fact := v.(*ssaFact)
+	fact.once.Do(func() {
+		fact.value = build()
+	})
+	return fact.value.(T)
+}
+
+// findLoops finds the natural loops of fn from its back edges, the edges to
+// a block that dominates their source, and nests them
+func findLoops(fn *ssa.Function) *loopNest {
+	nest := &loopNest{innermost: make([]*Loop, len(fn.Blocks))}
+	byHeader := make(map[*ssa.BasicBlock]*Loop)
+	for _, b := range fn.Blocks {
+		for _, header := range b.Succs {
+			if !header.Dominates(b) {
+				continue
+			}
+			loop := byHeader[header]
+			if loop == nil {
+				loop = &Loop{Header: header, blocks: map[*ssa.BasicBlock]bool{header: true}}
+				byHeader[header] = loop
+				nest.loops = append(nest.loops, loop)
+			}
+			// Everything that reaches the back edge without the header
+			stack := []*ssa.BasicBlock{b}
+			for len(stack) > 0 {
+				block := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if loop.blocks[block] {
+					continue
+				}
+				loop.blocks[block] = true
+				stack = append(stack, block.Preds...)
+			}
+		}
+	}
+
+	// A loop nests in the smallest other loop containing its header, and
+	// larger loops come first so parents have their depth already
+	sort.SliceStable(nest.loops, func(i, j int) bool {
+		return len(nest.loops[i].blocks) > len(nest.loops[j].blocks)
+	})
+	for i, loop := range nest.loops {
+		for j := i - 1; j >= 0; j-- {
+			if nest.loops[j].blocks[loop.Header] {
+				loop.Parent = nest.loops[j]
+				break
+			}
+		}
+		loop.Depth = 1
+		if loop.Parent != nil {
+			loop.Depth = loop.Parent.Depth + 1
+		}
+		for b := range loop.blocks {
+			nest.innermost[b.Index] = loop
+		}
+	}
+	return nest
+}
+
+// ssaInLoop reports whether expr, visited with wc, can run more than once per
+// call of the function containing it. The SSA form sees loops the syntax
+// hides, like goto loops, and knows that a loop body ending in an
+// unconditional break runs once. ok is false when the package does not
+// type-check or expr has no code, and the syntax has to decide.
+func ssaInLoop(wc *WalkContext, expr ast.Expr) (inLoop, ok bool) {
+	s := wc.session.ssaOf(wc.Fset, wc.File)
+	if s == nil {
+		return false, false
+	}
+	depth, ok := s.LoopDepth(expr)
+	return depth > 0, ok
+}
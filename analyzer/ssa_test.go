@@ -0,0 +1,323 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func buildTestSSA(t *testing.T, code string) (*SSA, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	s, err := BuildSSA(fset, file)
+	if err != nil {
+		t.Fatalf("BuildSSA: %v", err)
+	}
+	return s, file
+}
+
+// callsTo returns the calls of the named function in file, in source order
+func callsTo(file *ast.File, name string) []*ast.CallExpr {
+	var calls []*ast.CallExpr
+	ast.Inspect(file, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == name {
+				calls = append(calls, call)
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+func TestSSALoopDepth(t *testing.T) {
+	s, file := buildTestSSA(t, `package main
+func work(int) {}
+func main() {
+	work(0)
+	for i := 0; i < 3; i++ {
+		work(1)
+		for j := range 3 {
+			work(j)
+		}
+	}
+	n := 0
+retry:
+	work(2)
+	n++
+	if n < 3 {
+		goto retry
+	}
+	for {
+		work(3)
+		break
+	}
+	go func() {
+		for {
+			work(4)
+		}
+	}()
+}`)
+
+	want := []int{0, 1, 2, 1, 0, 1}
+	calls := callsTo(file, "work")
+	if len(calls) != len(want) {
+		t.Fatalf("found %d calls, want %d", len(calls), len(want))
+	}
+	for i, call := range calls {
+		depth, ok := s.LoopDepth(call)
+		if !ok || depth != want[i] {
+			t.Errorf("work call %d: depth = %d, %v; want %d", i, depth, ok, want[i])
+		}
+	}
+
+	main := s.Func(file.Decls[1].(*ast.FuncDecl))
+	if main == nil {
+		t.Fatal("no SSA function for main")
+	}
+	loops := s.Loops(main)
+	if len(loops) != 3 {
+		t.Fatalf("main has %d loops, want 3", len(loops))
+	}
+	inner := s.Loop(calls[2])
+	if inner == nil || inner.Depth != 2 || inner.Parent != s.Loop(calls[1]) {
+		t.Errorf("inner loop = %+v, want depth 2 nested in the outer loop", inner)
+	}
+}
+
+func TestSSADominates(t *testing.T) {
+	s, file := buildTestSSA(t, `package main
+func open() int  { return 0 }
+func close(int)  {}
+func check(int)  {}
+func main() {
+	f := open()
+	if f > 0 {
+		check(f)
+	}
+	close(f)
+}`)
+
+	open, check, closeCall := callsTo(file, "open")[0], callsTo(file, "check")[0], callsTo(file, "close")[0]
+	if !s.Dominates(open, closeCall) {
+		t.Error("open should dominate close")
+	}
+	if !s.Dominates(open, check) {
+		t.Error("open should dominate check")
+	}
+	if s.Dominates(check, closeCall) {
+		t.Error("check runs conditionally and should not dominate close")
+	}
+	if s.Dominates(closeCall, open) {
+		t.Error("close should not dominate open")
+	}
+}
+
+func TestSSAFlows(t *testing.T) {
+	s, file := buildTestSSA(t, `package main
+func source() []byte { return nil }
+func sink([]byte)    {}
+func main() {
+	var buf []byte
+	buf = source()
+	func() {
+		sink(buf[1:])
+	}()
+}`)
+
+	v := s.Value(callsTo(file, "source")[0])
+	if v == nil {
+		t.Fatal("no value for source()")
+	}
+	reached := false
+	for _, next := range s.Flows(v) {
+		if call, ok := next.(*ssa.Call); ok && call.Call.StaticCallee() != nil && call.Call.StaticCallee().Name() == "sink" {
+			reached = true
+		}
+	}
+	if !reached {
+		t.Error("source() should flow into the sink call through the captured variable")
+	}
+}
+
+func TestSSACallGraph(t *testing.T) {
+	s, file := buildTestSSA(t, `package main
+type T struct{}
+func (T) helper() {}
+func a() { b(); T{}.helper() }
+func b() {}
+func main() { a(); b() }`)
+
+	funcs := make(map[string]*ssa.Function)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			funcs[fn.Name.Name] = s.Func(fn)
+		}
+	}
+
+	names := func(fns []*ssa.Function) []string {
+		var out []string
+		for _, fn := range fns {
+			out = append(out, fn.Name())
+		}
+		sort.Strings(out)
+		return out
+	}
+	if got := names(s.Callees(funcs["a"])); len(got) != 2 || got[0] != "b" || got[1] != "helper" {
+		t.Errorf("callees of a = %v, want [b helper]", got)
+	}
+	if got := names(s.Callers(funcs["b"])); len(got) != 2 || got[0] != "a" || got[1] != "main" {
+		t.Errorf("callers of b = %v, want [a main]", got)
+	}
+}
+
+func TestBuildSSARejectsCgo(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", `package main
+// #include <string.h>
+import "C"
+func main() {
+	for i := 0; i < 3; i++ {
+		C.strlen(C.CString("a"))
+	}
+}`, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if s, err := BuildSSA(fset, file); err == nil {
+		t.Fatalf("expected an error for a cgo package, got %v", s)
+	}
+}
+
+func TestDatabaseAnalyzerUsesSSALoops(t *testing.T) {
+	code := `package main
+import "database/sql"
+func once(db *sql.DB, ids []int) {
+	for _, id := range ids {
+		db.Query("SELECT name FROM users WHERE id = ?", id)
+		break
+	}
+}
+func retry(db *sql.DB, id int) {
+again:
+	if _, err := db.Query("SELECT name FROM users WHERE id = ?", id); err != nil {
+		goto again
+	}
+}`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	var lines []int
+	for _, issue := range NewDatabaseAnalyzer().Analyze(file, fset) {
+		if issue.Type.String() == "SQLNPlusOne" {
+			lines = append(lines, issue.Line)
+		}
+	}
+	// The loop that always breaks runs its query once; the goto loop does not
+	if len(lines) != 1 || lines[0] != 11 {
+		t.Errorf("N+1 issues on lines %v, want [11]", lines)
+	}
+}
+
+func TestSSASharedByFilesOfPackage(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.go")
+	b := filepath.Join(dir, "b.go")
+	writeFile := func(name, code string) {
+		if err := os.WriteFile(name, []byte(code), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(a, "package lib\n\nfunc step() {}\n")
+	writeFile(b, "package lib\n\nfunc run(n int) {\n\tfor i := 0; i < n; i++ {\n\t\tstep()\n\t}\n}\n")
+
+	build := func(name string) (*SSA, *ast.File) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("parse error: %v", err)
+		}
+		s, err := BuildSSA(fset, file)
+		if err != nil {
+			t.Fatalf("BuildSSA: %v", err)
+		}
+		return s, file
+	}
+
+	sa, _ := build(a)
+	sb, fileB := build(b)
+	if sa.Package != sb.Package {
+		t.Fatal("files of one package built separate SSA forms")
+	}
+	// Queries take the nodes of the file's own parse
+	if depth, ok := sb.LoopDepth(callsTo(fileB, "step")[0]); !ok || depth != 1 {
+		t.Errorf("LoopDepth = %d, %v; want 1, true", depth, ok)
+	}
+
+	writeFile(a, "package lib\n\nfunc step() { _ = 1 }\n")
+	if sc, _ := build(b); sc.Package == sb.Package {
+		t.Error("SSA form was not rebuilt after the package changed")
+	}
+}
+
+func TestLoopAndRegexAnalyzersUseSSALoops(t *testing.T) {
+	code := `package main
+import (
+	"os"
+	"regexp"
+)
+func first(names []string) {
+	for _, name := range names {
+		f, _ := os.Open(name)
+		defer f.Close()
+		_ = regexp.MustCompile("a")
+		break
+	}
+}
+func retry(name string) {
+again:
+	f, err := os.Open(name)
+	defer f.Close()
+	_ = regexp.MustCompile("b")
+	if err != nil {
+		goto again
+	}
+}`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", code, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	lines := make(map[models.IssueType][]int)
+	for _, a := range []Analyzer{NewLoopAnalyzer(), NewRegexAnalyzer()} {
+		for _, issue := range a.Analyze(file, fset) {
+			lines[issue.Type] = append(lines[issue.Type], issue.Line)
+			if issue.Type == models.IssueDeferInLoop && issue.Fix != nil {
+				t.Errorf("defer in a goto loop has a fix")
+			}
+		}
+	}
+	// The loop that always breaks runs once; the goto loop repeats
+	if got := lines[models.IssueDeferInLoop]; len(got) != 1 || got[0] != 17 {
+		t.Errorf("DeferInLoop on lines %v, want [17]", got)
+	}
+	if got := lines[models.IssueRegexCompileInLoop]; len(got) != 1 || got[0] != 18 {
+		t.Errorf("RegexCompileInLoop on lines %v, want [18]", got)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"os"
+	"runtime"
+	"sort"
 
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
-type StructLayoutAnalyzer struct{}
+type StructLayoutAnalyzer struct {
+	inSession
+}
 
 func NewStructLayoutAnalyzer() Analyzer {
 	return &StructLayoutAnalyzer{}
@@ -30,13 +35,14 @@ func (s *StructLayoutAnalyzer) Analyze(node interface{}, fset *token.FileSet) []
 		filename = fset.Position(file.Pos()).Filename
 	}
 
-	info, _ := LoadTypes(fset, file, filename)
+	info, _ := loadTypes(fset, file, filename, s.session.overlay())
 	if info == nil {
 		return nil
 	}
 
 	issues := make([]*models.Issue, 0, 4)
-	sizes := &types.StdSizes{WordSize: 8, MaxAlign: 8}
+	sizes, arch := targetSizes()
+	var src []byte
 
 	ast.Inspect(file, func(n ast.Node) bool {
 		typeSpec, ok := n.(*ast.TypeSpec)
@@ -63,18 +69,39 @@ func (s *StructLayoutAnalyzer) Analyze(node interface{}, fset *token.FileSet) []
 			return true
 		}
 
-		wasted := computePadding(st, sizes)
+		fieldTypes := structFieldTypes(st)
+		size, wasted := layoutOf(fieldTypes, sizes)
 		if wasted >= 8 {
+			optimal, _ := layoutOf(permuteTypes(fieldTypes, optimalOrder(fieldTypes, sizes)), sizes)
+			message := fmt.Sprintf("Struct %s wastes %d bytes due to padding (%d bytes on %s)", typeSpec.Name.Name, wasted, size, arch)
+			var fix *models.SuggestedFix
+			if optimal < size {
+				message = fmt.Sprintf(
+					"Struct %s wastes %d bytes due to padding (%d → %d bytes on %s)",
+					typeSpec.Name.Name, wasted, size, optimal, arch,
+				)
+				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+					if src == nil {
+						src = readSource(fset, file, s.session.overlay())
+					}
+					fix = structReorderFix(fset, file, src, s.session.overlay(), info, sizes, typeSpec.Name, structType)
+				}
+			}
 			pos := fset.Position(typeSpec.Pos())
+			end := fset.Position(typeSpec.Name.End())
 			issues = append(issues, &models.Issue{
 				File:       filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueStructLayoutUnoptimized,
 				Severity:   models.SeverityLevelLow,
-				Message:    fmt.Sprintf("Struct %s wastes %d bytes due to padding", typeSpec.Name.Name, wasted),
+				Message:    message,
 				Suggestion: "Reorder fields to place larger types first and reduce padding",
+				CanBeFixed: fix != nil,
+				Fix:        fix,
 			})
 		}
 		return true
@@ -83,25 +110,132 @@ func (s *StructLayoutAnalyzer) Analyze(node interface{}, fset *token.FileSet) []
 	return issues
 }
 
+// targetSizes returns the gc type sizes of the architecture named by GOARCH,
+// defaulting to the host architecture
+func targetSizes() (types.Sizes, string) {
+	arch := os.Getenv("GOARCH")
+	if arch == "" {
+		arch = runtime.GOARCH
+	}
+	if sizes := types.SizesFor("gc", arch); sizes != nil {
+		return sizes, arch
+	}
+	return types.SizesFor("gc", "amd64"), "amd64"
+}
+
 func computePadding(st *types.Struct, sizes types.Sizes) int64 {
-	var wasted int64
+	_, wasted := layoutOf(structFieldTypes(st), sizes)
+	return wasted
+}
+
+func structFieldTypes(st *types.Struct) []types.Type {
+	fieldTypes := make([]types.Type, st.NumFields())
+	for i := range fieldTypes {
+		fieldTypes[i] = st.Field(i).Type()
+	}
+	return fieldTypes
+}
+
+func permuteTypes(fieldTypes []types.Type, order []int) []types.Type {
+	permuted := make([]types.Type, len(order))
+	for i, idx := range order {
+		permuted[i] = fieldTypes[idx]
+	}
+	return permuted
+}
+
+// layoutOf returns the size and the padding bytes of a struct with fields of
+// the given types in order
+func layoutOf(fieldTypes []types.Type, sizes types.Sizes) (size, wasted int64) {
 	var offset int64
 	var maxAlign int64 = 1
 
-	for i := 0; i < st.NumFields(); i++ {
-		ft := st.Field(i).Type()
-		size := sizes.Sizeof(ft)
+	for _, ft := range fieldTypes {
+		fieldSize := sizes.Sizeof(ft)
 		align := sizes.Alignof(ft)
 		if align > maxAlign {
 			maxAlign = align
 		}
 		padding := modPadding(offset, align)
 		wasted += padding
-		offset += padding + size
+		offset += padding + fieldSize
 	}
 
-	wasted += modPadding(offset, maxAlign)
-	return wasted
+	tail := modPadding(offset, maxAlign)
+	return offset + tail, wasted + tail
+}
+
+// optimalOrder returns the field order that minimizes padding and, among
+// equally aligned fields, keeps pointer data at the front so the GC scans
+// as few bytes as possible. Zero-sized fields go first since a trailing one
+// is padded.
+func optimalOrder(fieldTypes []types.Type, sizes types.Sizes) []int {
+	order := make([]int, len(fieldTypes))
+	fieldSizes := make([]int64, len(fieldTypes))
+	aligns := make([]int64, len(fieldTypes))
+	ptrs := make([]int64, len(fieldTypes))
+	for i, ft := range fieldTypes {
+		order[i] = i
+		fieldSizes[i] = sizes.Sizeof(ft)
+		aligns[i] = sizes.Alignof(ft)
+		ptrs[i] = pointerBytes(ft, sizes)
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if (fieldSizes[i] == 0) != (fieldSizes[j] == 0) {
+			return fieldSizes[i] == 0
+		}
+		if aligns[i] != aligns[j] {
+			return aligns[i] > aligns[j]
+		}
+		if (ptrs[i] == 0) != (ptrs[j] == 0) {
+			return ptrs[j] == 0
+		}
+		if ptrs[i] != 0 {
+			// Less trailing scalar data keeps the pointer prefix of the struct short
+			if trailI, trailJ := fieldSizes[i]-ptrs[i], fieldSizes[j]-ptrs[j]; trailI != trailJ {
+				return trailI < trailJ
+			}
+		}
+		return fieldSizes[i] > fieldSizes[j]
+	})
+	return order
+}
+
+// pointerBytes returns the length of the prefix of a value of type t that can
+// contain pointers, which is the part the GC has to scan
+func pointerBytes(t types.Type, sizes types.Sizes) int64 {
+	wordSize := sizes.Sizeof(types.Typ[types.UnsafePointer])
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if u.Kind() == types.String || u.Kind() == types.UnsafePointer {
+			return wordSize
+		}
+		return 0
+	case *types.Chan, *types.Map, *types.Pointer, *types.Signature, *types.Slice:
+		return wordSize
+	case *types.Interface:
+		return 2 * wordSize
+	case *types.Array:
+		elem := pointerBytes(u.Elem(), sizes)
+		if u.Len() == 0 || elem == 0 {
+			return 0
+		}
+		return (u.Len()-1)*sizes.Sizeof(u.Elem()) + elem
+	case *types.Struct:
+		var offset, ptrs int64
+		for i := range u.NumFields() {
+			ft := u.Field(i).Type()
+			offset += modPadding(offset, sizes.Alignof(ft))
+			if p := pointerBytes(ft, sizes); p != 0 {
+				ptrs = offset + p
+			}
+			offset += sizes.Sizeof(ft)
+		}
+		return ptrs
+	}
+	return 0
 }
 
 func modPadding(offset, alignment int64) int64 {
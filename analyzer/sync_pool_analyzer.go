@@ -8,7 +8,9 @@ import (
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
-type SyncPoolAnalyzer struct{}
+type SyncPoolAnalyzer struct {
+	inSession
+}
 
 func NewSyncPoolAnalyzer() Analyzer {
 	return &SyncPoolAnalyzer{}
@@ -29,7 +31,7 @@ func (spa *SyncPoolAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*m
 		filename = fset.Position(file.Pos()).Filename
 	}
 
-	info, _ := LoadTypes(fset, file, filename)
+	info, _ := loadTypes(fset, file, filename, spa.session.overlay())
 	if info == nil {
 		return nil
 	}
@@ -97,10 +99,13 @@ func (v *poolVisitor) inspectFunction(fn *ast.FuncDecl) {
 	for name, count := range gets {
 		if count > puts[name] {
 			pos := v.fset.Position(fn.Pos())
+			end := v.fset.Position(fn.End())
 			v.issues = append(v.issues, &models.Issue{
 				File:       v.filename,
 				Line:       pos.Line,
 				Column:     pos.Column,
+				EndLine:    end.Line,
+				EndColumn:  end.Column,
 				Position:   pos,
 				Type:       models.IssueSyncPoolOpportunity,
 				Severity:   models.SeverityLevelMedium,
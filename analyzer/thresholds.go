@@ -0,0 +1,15 @@
+package analyzer
+
+// Thresholds are the configurable limits analyzers report against. Zero
+// fields keep the defaults.
+type Thresholds struct {
+	MaxLoopDepth int // loops nested deeper are reported as NestedLoop
+}
+
+// withDefaults returns t with its zero fields set to the defaults
+func (t Thresholds) withDefaults() Thresholds {
+	if t.MaxLoopDepth <= 0 {
+		t.MaxLoopDepth = MaxNestedLoops
+	}
+	return t
+}
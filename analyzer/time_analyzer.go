@@ -18,58 +18,22 @@ func (ta *TimeAnalyzer) Name() string {
 }
 
 func (ta *TimeAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
-	file, ok := node.(*ast.File)
-	if !ok {
-		return nil
-	}
-
-	filename := ""
-	if file.Pos().IsValid() {
-		filename = fset.Position(file.Pos()).Filename
-	}
-
-	visitor := &timeVisitor{
-		fset:      fset,
-		filename:  filename,
-		loopDepth: 0,
-		issues:    make([]*models.Issue, 0, 4),
-	}
-
-	ast.Walk(visitor, file)
-	return visitor.issues
+	return walkAlone(ta, node, fset)
 }
 
-type timeVisitor struct {
-	fset      *token.FileSet
-	filename  string
-	loopDepth int
-	issues    []*models.Issue
+func (ta *TimeAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
+	visitor := &timeVisitor{issues: make([]*models.Issue, 0, 4)}
+	w.On(visitor.visitCall, (*ast.CallExpr)(nil))
+	return func() []*models.Issue { return visitor.issues }
 }
 
-func (v *timeVisitor) Visit(node ast.Node) ast.Visitor {
-	switch n := node.(type) {
-	case *ast.ForStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.RangeStmt:
-		v.loopDepth++
-		if n.Body != nil {
-			ast.Walk(v, n.Body)
-		}
-		v.loopDepth--
-		return nil
-	case *ast.CallExpr:
-		v.inspectCall(n)
-	}
-	return v
+type timeVisitor struct {
+	issues []*models.Issue
 }
 
-func (v *timeVisitor) inspectCall(call *ast.CallExpr) {
-	if v.loopDepth == 0 {
+func (v *timeVisitor) visitCall(n ast.Node, wc *WalkContext) {
+	call, _ := n.(*ast.CallExpr)
+	if !wc.InLoop() {
 		return
 	}
 
@@ -82,11 +46,14 @@ func (v *timeVisitor) inspectCall(call *ast.CallExpr) {
 		return
 	}
 
-	pos := v.fset.Position(call.Pos())
+	pos := wc.Fset.Position(call.Pos())
+	end := wc.Fset.Position(call.End())
 	v.issues = append(v.issues, &models.Issue{
-		File:       v.filename,
+		File:       wc.Filename,
 		Line:       pos.Line,
 		Column:     pos.Column,
+		EndLine:    end.Line,
+		EndColumn:  end.Column,
 		Position:   pos,
 		Type:       models.IssueTimeNowInLoop,
 		Severity:   models.SeverityLevelLow,
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"go/ast"
 	"go/importer"
+	"go/parser"
 	"go/token"
 	"go/types"
 	"path/filepath"
@@ -17,8 +18,14 @@ var errNoTypesInfo = errors.New("no type information returned")
 // It attempts to load package information via go/packages and falls back to
 // single-file type checking if necessary.
 func LoadTypes(fset *token.FileSet, file *ast.File, filename string) (*types.Info, error) {
+	return loadTypes(fset, file, filename, nil)
+}
+
+// loadTypes is LoadTypes with the sources of the package read from overlay
+// before the disk
+func loadTypes(fset *token.FileSet, file *ast.File, filename string, overlay Overlay) (*types.Info, error) {
 	if filename != "" {
-		info, err := loadTypesWithPackages(fset, filename)
+		info, err := loadTypesWithPackages(fset, file, filename, overlay)
 		switch {
 		case info != nil:
 			return info, err
@@ -29,15 +36,24 @@ func LoadTypes(fset *token.FileSet, file *ast.File, filename string) (*types.Inf
 	return loadTypesSingleFile(fset, file)
 }
 
-func loadTypesWithPackages(fset *token.FileSet, filename string) (*types.Info, error) {
+func loadTypesWithPackages(fset *token.FileSet, file *ast.File, filename string, overlay Overlay) (*types.Info, error) {
 	abs, err := filepath.Abs(filename)
 	if err != nil {
 		abs = filename
 	}
 
 	cfg := &packages.Config{
-		Fset: fset,
-		Mode: packages.NeedCompiledGoFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:     filepath.Dir(abs),
+		Fset:    fset,
+		Overlay: overlay,
+		Mode:    packages.NeedCompiledGoFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		// Type-check the caller's syntax tree so the returned info is keyed by its nodes
+		ParseFile: func(fset *token.FileSet, name string, src []byte) (*ast.File, error) {
+			if file != nil && name == abs {
+				return file, nil
+			}
+			return parser.ParseFile(fset, name, src, parser.AllErrors|parser.ParseComments)
+		},
 	}
 
 	pkgs, err := packages.Load(cfg, "file="+abs)
@@ -52,9 +68,10 @@ func loadTypesWithPackages(fset *token.FileSet, filename string) (*types.Info, e
 
 func loadTypesSingleFile(fset *token.FileSet, file *ast.File) (*types.Info, error) {
 	info := &types.Info{
-		Types: make(map[ast.Expr]types.TypeAndValue),
-		Defs:  make(map[*ast.Ident]types.Object),
-		Uses:  make(map[*ast.Ident]types.Object),
+		Types:  make(map[ast.Expr]types.TypeAndValue),
+		Defs:   make(map[*ast.Ident]types.Object),
+		Uses:   make(map[*ast.Ident]types.Object),
+		Scopes: make(map[ast.Node]*types.Scope),
 	}
 
 	conf := types.Config{
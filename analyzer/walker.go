@@ -0,0 +1,266 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// NodeAnalyzer is an Analyzer that looks at the nodes it subscribes to during
+// the one traversal of a file that Analyze shares between analyzers, instead
+// of walking the file itself
+type NodeAnalyzer interface {
+	Analyzer
+	// Subscribe registers handlers on w and returns a function that reports
+	// the issues they found once w has walked its file
+	Subscribe(w *Walker) func() []*models.Issue
+}
+
+// WalkHandler is called for a node with what the traversal knows about it
+type WalkHandler func(n ast.Node, wc *WalkContext)
+
+// Walker dispatches the nodes of one file to the handlers subscribed to their
+// type, in a single depth-first traversal
+type Walker struct {
+	fset     *token.FileSet
+	file     *ast.File
+	session  *session // nil outside AnalyzeWithOptions
+	handlers map[reflect.Type][]ownedHandler
+	all      []ownedHandler
+
+	// subscribers names the analyzers handlers belong to; handlers registered
+	// outside subscribe belong to the last one, or to none
+	subscribers []string
+	// times, when not nil, accumulates the time spent in the handlers of each
+	// subscriber
+	times []time.Duration
+}
+
+// ownedHandler is a handler and the index of its subscriber, -1 for none
+type ownedHandler struct {
+	handle     WalkHandler
+	subscriber int
+}
+
+// NewWalker returns a Walker for file without handlers
+func NewWalker(fset *token.FileSet, file *ast.File) *Walker {
+	return &Walker{fset: fset, file: file, handlers: make(map[reflect.Type][]ownedHandler)}
+}
+
+// File returns the file the walker traverses
+func (w *Walker) File() *ast.File {
+	return w.file
+}
+
+// FileSet returns the file set the file was parsed into
+func (w *Walker) FileSet() *token.FileSet {
+	return w.fset
+}
+
+// On calls handler for every node of the types of nodes, given as typed nils
+// like (*ast.CallExpr)(nil). Without nodes handler is called for every node.
+// Handlers of one node run in the order they were registered.
+func (w *Walker) On(handler WalkHandler, nodes ...ast.Node) {
+	owned := ownedHandler{handle: handler, subscriber: len(w.subscribers) - 1}
+	if len(nodes) == 0 {
+		w.all = append(w.all, owned)
+		return
+	}
+	for _, node := range nodes {
+		t := reflect.TypeOf(node)
+		w.handlers[t] = append(w.handlers[t], owned)
+	}
+}
+
+// subscribe subscribes na to w under name, the analyzer its handlers are
+// timed for
+func (w *Walker) subscribe(name string, na NodeAnalyzer) func() []*models.Issue {
+	w.subscribers = append(w.subscribers, name)
+	return na.Subscribe(w)
+}
+
+// timeHandlers makes Walk measure the time spent in the handlers of each
+// subscriber, which handlerTimes reports
+func (w *Walker) timeHandlers() {
+	w.times = make([]time.Duration, len(w.subscribers))
+}
+
+// handlerTimes returns the time spent in the handlers of each subscriber
+// during Walk, by name
+func (w *Walker) handlerTimes() map[string]time.Duration {
+	times := make(map[string]time.Duration, len(w.times))
+	for i, d := range w.times {
+		times[w.subscribers[i]] += d
+	}
+	return times
+}
+
+// Walk traverses the file once, calling the handlers of each node
+func (w *Walker) Walk() {
+	if w.file == nil || (len(w.all) == 0 && len(w.handlers) == 0) {
+		return
+	}
+	call := func(h ownedHandler, n ast.Node, wc *WalkContext) {
+		if w.times == nil || h.subscriber < 0 || h.subscriber >= len(w.times) {
+			h.handle(n, wc)
+			return
+		}
+		start := time.Now()
+		h.handle(n, wc)
+		w.times[h.subscriber] += time.Since(start)
+	}
+	walkTreeIn(w.session, w.fset, w.file, func(n ast.Node, wc *WalkContext) bool {
+		for _, handler := range w.all {
+			call(handler, n, wc)
+		}
+		for _, handler := range w.handlers[reflect.TypeOf(n)] {
+			call(handler, n, wc)
+		}
+		return true
+	})
+}
+
+// walkAlone runs na over node on a walker of its own; it implements Analyze
+// for analyzers called outside Analyze
+func walkAlone(na NodeAnalyzer, node interface{}, fset *token.FileSet) []*models.Issue {
+	file, ok := node.(*ast.File)
+	if !ok {
+		return nil
+	}
+	w := NewWalker(fset, file)
+	issues := na.Subscribe(w)
+	w.Walk()
+	return issues()
+}
+
+// WalkContext is what the traversal knows about the node being visited. It is
+// updated in place as the traversal moves and must not be kept by handlers.
+type WalkContext struct {
+	Fset     *token.FileSet
+	File     *ast.File // nil when the traversal did not start at a file
+	Filename string    // empty when the file has no position
+
+	// Stack holds the ancestors of the node, outermost first
+	Stack []ast.Node
+	// Loops holds the for and range statements whose iterations run the node,
+	// outermost first. The init statement of a for loop and the collection of
+	// a range loop run once and are not part of their loop. Function literals
+	// keep the loops around them, since a closure made in a loop usually runs
+	// in it.
+	Loops []ast.Stmt
+	// Func is the innermost *ast.FuncDecl or *ast.FuncLit around the node and
+	// FuncDecl the declared function around it; both nil at package level
+	Func     ast.Node
+	FuncDecl *ast.FuncDecl
+	// Go and Defer are the innermost go and defer statements whose call
+	// contains the node, nil outside them
+	Go    *ast.GoStmt
+	Defer *ast.DeferStmt
+
+	session *session
+	saved   []walkState
+}
+
+// walkState is the part of WalkContext a node changes for its children
+type walkState struct {
+	loops     int
+	fn        ast.Node
+	decl      *ast.FuncDecl
+	goStmt    *ast.GoStmt
+	deferStmt *ast.DeferStmt
+}
+
+// InLoop reports whether the node runs on every iteration of a loop
+func (wc *WalkContext) InLoop() bool {
+	return len(wc.Loops) > 0
+}
+
+// LoopDepth returns the number of loops whose iterations run the node
+func (wc *WalkContext) LoopDepth() int {
+	return len(wc.Loops)
+}
+
+// Parent returns the node's parent, or nil for the root
+func (wc *WalkContext) Parent() ast.Node {
+	if len(wc.Stack) == 0 {
+		return nil
+	}
+	return wc.Stack[len(wc.Stack)-1]
+}
+
+// enter updates the context for n, a child of the top of the stack
+func (wc *WalkContext) enter(n ast.Node) {
+	wc.saved = append(wc.saved, walkState{
+		loops:     len(wc.Loops),
+		fn:        wc.Func,
+		decl:      wc.FuncDecl,
+		goStmt:    wc.Go,
+		deferStmt: wc.Defer,
+	})
+
+	switch parent := wc.Parent().(type) {
+	case *ast.ForStmt:
+		if n != parent.Init {
+			wc.Loops = append(wc.Loops, parent)
+		}
+	case *ast.RangeStmt:
+		if n != parent.X {
+			wc.Loops = append(wc.Loops, parent)
+		}
+	case *ast.GoStmt:
+		wc.Go = parent
+	case *ast.DeferStmt:
+		wc.Defer = parent
+	}
+
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		wc.Func, wc.FuncDecl = n, n
+	case *ast.FuncLit:
+		wc.Func = n
+	}
+}
+
+// leave restores the context saved when the last node was entered
+func (wc *WalkContext) leave() {
+	state := wc.saved[len(wc.saved)-1]
+	wc.saved = wc.saved[:len(wc.saved)-1]
+	wc.Loops = wc.Loops[:state.loops]
+	wc.Func, wc.FuncDecl = state.fn, state.decl
+	wc.Go, wc.Defer = state.goStmt, state.deferStmt
+}
+
+// walkTree traverses root depth-first in source order, calling visit for each
+// node with its context. Returning false skips the node's children.
+func walkTree(fset *token.FileSet, root ast.Node, visit func(n ast.Node, wc *WalkContext) bool) {
+	walkTreeIn(nil, fset, root, visit)
+}
+
+// walkTreeIn is walkTree for the analyzers of session s
+func walkTreeIn(s *session, fset *token.FileSet, root ast.Node, visit func(n ast.Node, wc *WalkContext) bool) {
+	wc := &WalkContext{Fset: fset, Stack: make([]ast.Node, 0, 32), session: s}
+	if file, ok := root.(*ast.File); ok {
+		wc.File = file
+		if fset != nil && file.Pos().IsValid() {
+			wc.Filename = fset.Position(file.Pos()).Filename
+		}
+	}
+
+	ast.Inspect(root, func(n ast.Node) bool {
+		if n == nil {
+			wc.Stack = wc.Stack[:len(wc.Stack)-1]
+			wc.leave()
+			return true
+		}
+		wc.enter(n)
+		if !visit(n, wc) {
+			wc.leave()
+			return false
+		}
+		wc.Stack = append(wc.Stack, n)
+		return true
+	})
+}
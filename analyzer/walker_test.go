@@ -0,0 +1,273 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const walkerTestCode = `package main
+
+func work(int) {}
+
+func main() {
+	for i := init0(); cond(i); i = post(i) {
+		work(1)
+		for _, v := range items() {
+			work(v)
+		}
+	}
+	go func() {
+		work(2)
+	}()
+	defer work(3)
+}
+
+func init0() int     { return 0 }
+func cond(int) bool  { return false }
+func post(i int) int { return i }
+func items() []int   { return nil }
+`
+
+// walkerContext records what the walker reports for each call by callee name
+type walkerContext struct {
+	depth   int
+	fn      string
+	inGo    bool
+	inDefer bool
+}
+
+func TestWalkerContext(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", walkerTestCode, 0)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	got := make(map[string][]walkerContext)
+	w := NewWalker(fset, file)
+	w.On(func(n ast.Node, wc *WalkContext) {
+		call, _ := n.(*ast.CallExpr)
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok {
+			return
+		}
+		fn := ""
+		if wc.FuncDecl != nil {
+			fn = wc.FuncDecl.Name.Name
+		}
+		if _, lit := wc.Func.(*ast.FuncLit); lit {
+			fn += " literal"
+		}
+		got[ident.Name] = append(got[ident.Name], walkerContext{
+			depth:   wc.LoopDepth(),
+			fn:      fn,
+			inGo:    wc.Go != nil,
+			inDefer: wc.Defer != nil,
+		})
+		if wc.Parent() == nil || wc.Filename != "test.go" {
+			t.Errorf("%s: parent %v, filename %q", ident.Name, wc.Parent(), wc.Filename)
+		}
+	}, (*ast.CallExpr)(nil))
+	w.Walk()
+
+	want := map[string][]walkerContext{
+		"init0": {{depth: 0, fn: "main"}},
+		"cond":  {{depth: 1, fn: "main"}},
+		"post":  {{depth: 1, fn: "main"}},
+		"items": {{depth: 1, fn: "main"}},
+		"work": {
+			{depth: 1, fn: "main"},
+			{depth: 2, fn: "main"},
+			{depth: 0, fn: "main literal", inGo: true},
+			{depth: 0, fn: "main", inDefer: true},
+		},
+	}
+	for name, contexts := range want {
+		if len(got[name]) != len(contexts) {
+			t.Errorf("%s: got %d calls, want %d", name, len(got[name]), len(contexts))
+			continue
+		}
+		for i, ctx := range contexts {
+			if got[name][i] != ctx {
+				t.Errorf("%s call %d: got %+v, want %+v", name, i, got[name][i], ctx)
+			}
+		}
+	}
+}
+
+func TestWalkerDispatch(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", walkerTestCode, 0)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	var all, loops, calls int
+	w := NewWalker(fset, file)
+	w.On(func(ast.Node, *WalkContext) { all++ })
+	w.On(func(ast.Node, *WalkContext) { loops++ }, (*ast.ForStmt)(nil), (*ast.RangeStmt)(nil))
+	w.On(func(ast.Node, *WalkContext) { calls++ }, (*ast.CallExpr)(nil))
+	w.Walk()
+
+	inspected := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		if n != nil {
+			inspected++
+		}
+		return true
+	})
+	if all != inspected {
+		t.Errorf("untyped handler saw %d nodes, want %d", all, inspected)
+	}
+	if loops != 2 || calls != 9 {
+		t.Errorf("got %d loops and %d calls, want 2 and 9", loops, calls)
+	}
+}
+
+func TestAnalyzeSharesWalkWithNodeAnalyzers(t *testing.T) {
+	code := `package main
+import "time"
+func run(items []int) {
+	for range items {
+		_ = time.Now()
+		defer println()
+	}
+}`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "shared_walk.go", code, 0)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	enabled := map[string]bool{"loop": true, "time": true, "gcpressure": true}
+	shared := Analyze("shared_walk.go", file, fset, enabled)
+	InvalidateCache("shared_walk.go")
+
+	var alone []*models.Issue
+	for _, a := range []Analyzer{NewLoopAnalyzer(), NewTimeAnalyzer(), NewGCPressureAnalyzer()} {
+		alone = append(alone, a.Analyze(file, fset)...)
+	}
+	if len(shared) != len(alone) {
+		t.Fatalf("shared walk found %d issues, separate walks %d", len(shared), len(alone))
+	}
+	for i := range shared {
+		if shared[i].Type != alone[i].Type || shared[i].Line != alone[i].Line {
+			t.Errorf("issue %d: shared %s at %d, alone %s at %d",
+				i, shared[i].Type, shared[i].Line, alone[i].Type, alone[i].Line)
+		}
+	}
+}
+
+func BenchmarkNodeAnalyzers(b *testing.B) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bench.go", benchmarkCode, parser.ParseComments)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var analyzers []NodeAnalyzer
+	for _, a := range []Analyzer{
+		NewLoopAnalyzer(), NewSliceAnalyzer(), NewReflectionAnalyzer(), NewRegexAnalyzer(),
+		NewTimeAnalyzer(), NewCPUOptimizationAnalyzer(), NewCryptoAnalyzer(),
+	} {
+		if na, ok := a.(NodeAnalyzer); ok {
+			analyzers = append(analyzers, na)
+		}
+	}
+
+	b.Run("shared", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			w := NewWalker(fset, file)
+			collect := make([]func() []*models.Issue, 0, len(analyzers))
+			for _, na := range analyzers {
+				collect = append(collect, na.Subscribe(w))
+			}
+			w.Walk()
+			for _, issues := range collect {
+				issues()
+			}
+		}
+	})
+	b.Run("separate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, na := range analyzers {
+				na.Analyze(file, fset)
+			}
+		}
+	})
+}
+
+// sleepyAnalyzer is a node analyzer that spends time in its handler
+type sleepyAnalyzer struct{ delay time.Duration }
+
+func (a sleepyAnalyzer) Name() string { return "sleepy" }
+
+func (a sleepyAnalyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
+	return walkAlone(a, node, fset)
+}
+
+func (a sleepyAnalyzer) Subscribe(w *Walker) func() []*models.Issue {
+	w.On(func(ast.Node, *WalkContext) { time.Sleep(a.delay) }, (*ast.FuncDecl)(nil))
+	return func() []*models.Issue { return nil }
+}
+
+func TestWalkerTimesHandlersPerSubscriber(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", walkerTestCode, 0)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	w := NewWalker(fset, file)
+	w.subscribe("sleepy", sleepyAnalyzer{delay: time.Millisecond})
+	w.subscribe("loop", NewLoopAnalyzer().(NodeAnalyzer))
+	w.timeHandlers()
+	w.Walk()
+
+	// walkerTestCode declares six functions
+	times := w.handlerTimes()
+	if times["sleepy"] < 6*time.Millisecond {
+		t.Errorf("sleepy handlers took %s, want at least 6ms", times["sleepy"])
+	}
+	if _, ok := times["loop"]; !ok || times["loop"] >= times["sleepy"] {
+		t.Errorf("loop handlers took %s of %s", times["loop"], times["sleepy"])
+	}
+
+	profile := NewProfile()
+	profile.measure(sharedWalkName, func() int { return 0 })
+	profile.measure("sleepy", func() int { return 0 })
+	profile.analyzers[sharedWalkName].Duration = 10 * time.Millisecond
+	profile.moveTime(sharedWalkName, map[string]time.Duration{"sleepy": 6 * time.Millisecond})
+	if walk, sleepy := profile.analyzers[sharedWalkName].Duration, profile.analyzers["sleepy"].Duration; walk > 4*time.Millisecond ||
+		sleepy < 6*time.Millisecond {
+		t.Errorf("after moving, walk took %s and sleepy %s", walk, sleepy)
+	}
+}
+
+// BenchmarkAnalyzeSharedWalk compares Analyze, where node analyzers share one
+// traversal, with every registered analyzer walking the file on its own
+func BenchmarkAnalyzeSharedWalk(b *testing.B) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "bench_shared.go", benchmarkCode, parser.ParseComments)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("analyze", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			InvalidateCache("bench_shared.go")
+			Analyze("bench_shared.go", file, fset, nil)
+		}
+	})
+	b.Run("separate", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, entry := range Registered() {
+				entry.New().Analyze(file, fset)
+			}
+		}
+	})
+}
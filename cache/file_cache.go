@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -18,7 +19,7 @@ import (
 const (
 	CacheDir     = ".abscleaner"
 	CacheFile    = "cache.json"
-	CacheVersion = "1.0"
+	CacheVersion = "1.2"
 )
 
 type FileCache struct {
@@ -36,9 +37,33 @@ type CacheData struct {
 	LastUpdated  time.Time     `json:"last_updated"`
 }
 
+// IgnoredRule suppresses specific rule types for files matching a pattern
 type IgnoredRule struct {
-	FilePattern string   `json:"file_pattern"`
-	RuleTypes   []string `json:"rule_types"`
+	FilePattern string   `json:"file_pattern" yaml:"file_pattern"`
+	RuleTypes   []string `json:"rule_types" yaml:"rule_types"`
+}
+
+// Matches reports whether the rule suppresses ruleType for filePath. Rule types
+// may be given as issue type names or PVE IDs; "*" matches every type.
+func (r IgnoredRule) Matches(filePath, ruleType string) bool {
+	if !matchPattern(filePath, r.FilePattern) {
+		return false
+	}
+
+	want := canonicalRuleType(ruleType)
+	for _, rt := range r.RuleTypes {
+		if rt == "*" || canonicalRuleType(rt) == want {
+			return true
+		}
+	}
+	return false
+}
+
+func canonicalRuleType(ruleType string) string {
+	if issueType, err := models.ParseIssueType(ruleType); err == nil {
+		return issueType.String()
+	}
+	return ruleType
 }
 
 type FileRecord struct {
@@ -463,43 +488,68 @@ func (fc *FileCache) ShouldIgnoreRule(filePath, ruleType string) bool {
 	defer fc.mu.RUnlock()
 
 	for _, rule := range fc.data.IgnoredRules {
-		if matchPattern(filePath, rule.FilePattern) {
-			for _, rt := range rule.RuleTypes {
-				if rt == ruleType || rt == "*" {
-					return true
-				}
-			}
+		if rule.Matches(filePath, ruleType) {
+			return true
 		}
 	}
 	return false
 }
 
-// matchPattern checks if a file path matches a pattern
+// matchPattern checks if a file path matches a gitignore-style pattern.
+// "*" and "?" stay within one path segment, "**" spans segments, a pattern
+// without a slash matches at any depth and a matched directory covers
+// everything below it.
 func matchPattern(path, pattern string) bool {
-	// Simple pattern matching
-	// Support * for wildcards and ** for recursive
 	if pattern == "*" || pattern == "**" {
 		return true
 	}
 
-	// Handle ** for recursive matching
-	if strings.Contains(pattern, "**") {
-		parts := strings.Split(pattern, "**")
-		if len(parts) == 2 {
-			return strings.HasPrefix(path, parts[0]) && strings.HasSuffix(path, parts[1])
-		}
+	re := patternRegexp(pattern)
+	if re == nil {
+		return false
 	}
+	return re.MatchString(strings.TrimPrefix(filepath.ToSlash(path), "./"))
+}
+
+var patternCache sync.Map // pattern -> *regexp.Regexp
 
-	// Handle * for single-level wildcard
-	if strings.Contains(pattern, "*") {
-		// Convert pattern to regex-like matching
-		pattern = strings.ReplaceAll(pattern, "*", ".*")
-		// This is simplified - in production, use proper glob matching
-		return strings.Contains(path, strings.ReplaceAll(pattern, ".*", ""))
+func patternRegexp(pattern string) *regexp.Regexp {
+	if cached, ok := patternCache.Load(pattern); ok {
+		re, _ := cached.(*regexp.Regexp)
+		return re
 	}
 
-	// Exact match or prefix match
-	return path == pattern || strings.HasPrefix(path, pattern)
+	glob := strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(pattern), "./"), "/")
+	var sb strings.Builder
+	if strings.Contains(glob, "/") {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("(^|.*/)")
+	}
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("($|/)")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		re = nil
+	}
+	patternCache.Store(pattern, re)
+	return re
 }
 
 // Helper function to convert generic issues to cache Issue
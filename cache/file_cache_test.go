@@ -172,6 +172,43 @@ func TestShouldIgnoreRuleWithWildcard(t *testing.T) {
 	require.True(t, fc.ShouldIgnoreRule("test/file.go", "AnyIssue"))
 }
 
+func TestIgnoredRuleMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"internal/legacy/**", "internal/legacy/a/b.go", true},
+		{"internal/legacy/**", "./internal/legacy/b.go", true},
+		{"internal/legacy/**", "pkg/internal/legacy/b.go", false},
+		{"*.pb.go", "api/v1/service.pb.go", true},
+		{"*.pb.go", "api/v1/service.go", false},
+		{"**/generated", "pkg/generated/x.go", true},
+		{"cmd/*/main.go", "cmd/tool/main.go", true},
+		{"cmd/*/main.go", "cmd/tool/sub/main.go", false},
+		{"vendor/", "vendor/github.com/x/y.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			rule := IgnoredRule{FilePattern: tt.pattern, RuleTypes: []string{"*"}}
+			require.Equal(t, tt.expected, rule.Matches(tt.path, "RaceCondition"))
+		})
+	}
+}
+
+func TestIgnoredRuleMatchesPVEIDs(t *testing.T) {
+	rule := IgnoredRule{
+		FilePattern: "internal/**",
+		RuleTypes:   []string{models.IssueRaceCondition.GetPVEID(), "AIGeneratedComment"},
+	}
+
+	require.True(t, rule.Matches("internal/x.go", models.IssueRaceCondition.String()))
+	require.True(t, rule.Matches("internal/x.go", models.IssueAIGeneratedComment.String()))
+	require.False(t, rule.Matches("internal/x.go", models.IssueMemoryLeak.String()))
+	require.False(t, rule.Matches("cmd/x.go", models.IssueRaceCondition.String()))
+}
+
 func TestSaveRawRecord(t *testing.T) {
 	tdir := t.TempDir()
 	fc, err := New(tdir)
@@ -1,14 +1,10 @@
 package cmd
 
 import (
-	"bufio"
-	"go/ast"
-	"go/parser"
 	"go/token"
+	"io"
 	"log/slog"
-	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/SergeiSkv/AiBsCleaner/cache"
 	"github.com/SergeiSkv/AiBsCleaner/models"
@@ -84,78 +80,6 @@ func convertDBIssueToAnalyzerIssue(filename string, dbIssue *models.Issue) *mode
 	}
 }
 
-// parseGoFile parses a Go source file
-func parseGoFile(filename string) (*token.FileSet, *ast.File, error) {
-	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
-	if err != nil {
-		slog.Warn("Error parsing file", "file", filename, "error", err)
-		return nil, nil, err
-	}
-	return fset, node, nil
-}
-
-// buildEnabledAnalyzers builds the map of enabled analyzers from config
-func buildEnabledAnalyzers(config *Config) map[string]bool {
-	if config == nil {
-		return nil // nil means run all
-	}
-
-	enabledAnalyzers := make(map[string]bool)
-	analyzerMapping := getAnalyzerMapping()
-
-	for configName, analyzerName := range analyzerMapping {
-		analyzerConfig := config.GetAnalyzerConfig(configName)
-		if analyzerConfig.Enabled {
-			enabledAnalyzers[analyzerName] = true
-		}
-	}
-
-	// If no analyzers enabled (shouldn't happen with default config), run all
-	if len(enabledAnalyzers) == 0 {
-		return nil
-	}
-
-	return enabledAnalyzers
-}
-
-// getAnalyzerMapping returns the mapping between config names and analyzer names
-func getAnalyzerMapping() map[string]string {
-	return map[string]string{
-		"loop":                "loop",
-		"deferoptimization":   "deferoptimization",
-		"slice":               "slice",
-		"map":                 "map",
-		"reflection":          "reflection",
-		"interface":           "interface",
-		"regex":               "regex",
-		"time":                "time",
-		"memoryleak":          "memoryleak",
-		"database":            "database",
-		"apimisuse":           "apimisuse",
-		"aibullshit":          "aibullshit",
-		"goroutine":           "goroutine",
-		"channel":             "channel",
-		"httpclient":          "httpclient",
-		"context":             "context",
-		"racecondition":       "racecondition",
-		"concurrencypatterns": "concurrencypatterns",
-		"networkpatterns":     "networkpatterns",
-		"cpuoptimization":     "cpuoptimization",
-		"gcpressure":          "gcpressure",
-		"syncpool":            "syncpool",
-		"cgo":                 "cgo",
-		"serialization":       "serialization",
-		"crypto":              "crypto",
-		"httpreuse":           "httpreuse",
-		"iobuffer":            "iobuffer",
-		"privacy":             "privacy",
-		"testcoverage":        "testcoverage",
-		"structlayout":        "structlayout",
-		"cpucache":            "cpucache",
-	}
-}
-
 // saveToCacheDB saves issues to the cache database
 func saveToCacheDB(filename string, issues []*models.Issue, cacheDB *cache.FileCache) {
 	if cacheDB == nil || noCache {
@@ -172,69 +96,51 @@ func saveToCacheDB(filename string, issues []*models.Issue, cacheDB *cache.FileC
 	}
 }
 
-// shouldSkipPath checks if a path should be skipped based on exclusion rules
-func shouldSkipPath(path string, info os.FileInfo, excludes []string) (skip, skipDir bool) {
-	cleanPath := filepath.Clean(path)
-
-	for _, exclude := range excludes {
-		cleanExclude := filepath.Clean(exclude)
-
-		if strings.HasSuffix(exclude, ".go") {
-			// File pattern (e.g., "_test.go")
-			if !info.IsDir() && strings.HasSuffix(cleanPath, exclude) {
-				return true, false
-			}
-			continue
-		}
-
-		// Check if path contains or matches the exclude pattern
-		if strings.Contains(cleanPath, cleanExclude) {
-			if info.IsDir() {
-				return false, true
-			}
-			return true, false
-		}
+// fileCache adapts the cache database to aibscleaner.Cache
+type fileCache struct {
+	db *cache.FileCache
+}
 
-		// Check base name matching
-		if filepath.Base(cleanPath) == exclude {
-			if info.IsDir() {
-				return false, true
-			}
-			return true, false
-		}
-	}
+func (c fileCache) Load(filename string) ([]*models.Issue, bool) {
+	return loadCachedIssues(filename, c.db)
+}
 
-	return false, false
+func (c fileCache) Store(filename string, issues []*models.Issue) {
+	saveToCacheDB(filename, issues, c.db)
 }
 
-// isGoFile checks if a file is a Go source file
-func isGoFile(path string, info os.FileInfo) bool {
-	return !info.IsDir() && strings.HasSuffix(path, ".go")
+func (c fileCache) IgnoresRule(relPath, rule string) bool {
+	return c.db.ShouldIgnoreRule(relPath, rule)
 }
 
-// countLines counts the number of lines in a file using streaming for better memory efficiency
-func countLines(path string) int {
-	file, err := os.Open(path)
-	if err != nil {
-		return 0
-	}
-	defer func() {
-		_ = file.Close()
-	}()
-
-	scanner := bufio.NewScanner(file)
-	lineCount := 0
-	for scanner.Scan() {
-		lineCount++
+// removeStaleDirectives deletes the removable stale ignore directives reported
+// in issues from their files and returns the issues that are left
+func removeStaleDirectives(issues []*models.Issue) []*models.Issue {
+	byDir := make(map[string][]*models.Issue)
+	for _, issue := range issues {
+		if issue.Type == models.IssueStaleIgnoreDirective && issue.Fix != nil {
+			dir := filepath.Dir(issue.Position.Filename)
+			byDir[dir] = append(byDir[dir], issue)
+		}
 	}
 
-	// Handle files that don't end with newline
-	if lineCount == 0 {
-		// Check if file has any content
-		if stat, err := file.Stat(); err == nil && stat.Size() > 0 {
-			lineCount = 1
+	removed := make(map[*models.Issue]bool)
+	for dir, stale := range byDir {
+		applied, _, err := fixPackage(stale, false, io.Discard)
+		if err != nil {
+			slog.Warn("Failed to remove stale ignore directives", "dir", dir, "error", err)
+			continue
+		}
+		for _, issue := range applied {
+			removed[issue] = true
 		}
 	}
 
-	return lineCount
+	remaining := issues[:0]
+	for _, issue := range issues {
+		if !removed[issue] {
+			remaining = append(remaining, issue)
+		}
+	}
+	return remaining
 }
@@ -1,32 +1,19 @@
 package cmd
 
 import (
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"testing"
-	"time"
 
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
 	"github.com/SergeiSkv/AiBsCleaner/cache"
 	"github.com/SergeiSkv/AiBsCleaner/models"
 )
 
 const sampleGoFile = "sample.go"
 
-type stubFileInfo struct {
-	name string
-	size int64
-	mode os.FileMode
-	mod  time.Time
-	dir  bool
-}
-
-func (s stubFileInfo) Name() string       { return s.name }
-func (s stubFileInfo) Size() int64        { return s.size }
-func (s stubFileInfo) Mode() os.FileMode  { return s.mode }
-func (s stubFileInfo) ModTime() time.Time { return s.mod }
-func (s stubFileInfo) IsDir() bool        { return s.dir }
-func (s stubFileInfo) Sys() any           { return nil }
-
 func TestReconstructIssuesFromCacheFiltersIgnored(t *testing.T) {
 	record := &cache.FileRecord{
 		Issues: []*models.Issue{
@@ -62,67 +49,33 @@ func TestConvertDBIssueToAnalyzerIssueDefaultsSeverity(t *testing.T) {
 	}
 }
 
-func TestBuildEnabledAnalyzersUsesConfig(t *testing.T) {
-	cfg := &Config{}
-	cfg.Analyzers.Loop.Enabled = true
-	cfg.Analyzers.Map.Enabled = false
-
-	enabled := buildEnabledAnalyzers(cfg)
-	if enabled == nil {
-		t.Fatalf("expected map of enabled analyzers")
-	}
-
-	if !enabled["loop"] {
-		t.Fatalf("loop analyzer should be enabled")
-	}
-	if enabled["map"] {
-		t.Fatalf("map analyzer should not be enabled")
-	}
-}
-
-func TestBuildEnabledAnalyzersReturnsNilWhenNoneEnabled(t *testing.T) {
-	cfg := &Config{}
-	if result := buildEnabledAnalyzers(cfg); result != nil {
-		t.Fatalf("expected nil when no analyzers enabled, got %v", result)
-	}
-}
-
-func TestShouldSkipPath(t *testing.T) {
-	excludes := []string{"vendor", "_test.go"}
-
-	skip, skipDir := shouldSkipPath(filepath.Join("project", "vendor"), stubFileInfo{dir: true}, excludes)
-	if skip || !skipDir {
-		t.Fatalf("expected to skip directory traversal for vendor")
-	}
-
-	skip, skipDir = shouldSkipPath(filepath.Join("project", "handler_test.go"), stubFileInfo{name: "handler_test.go"}, excludes)
-	if !skip || skipDir {
-		t.Fatalf("expected to skip test file")
+func TestRemoveStaleDirectives(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, sampleGoFile)
+	src := "package sample\n\n// abc:ignore HTTPNoTimeout\nfunc f() {}\n\n// abc:ignore[until=bad] RaceCondition\nfunc g() {}\n"
+	if err := os.WriteFile(filePath, []byte(src), 0o644); err != nil {
+		t.Fatalf("failed writing temp file: %v", err)
 	}
 
-	skip, skipDir = shouldSkipPath(filepath.Join("project", "main.go"), stubFileInfo{name: "main.go"}, excludes)
-	if skip || skipDir {
-		t.Fatalf("expected to keep regular file")
+	fset := token.NewFileSet()
+	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse temp file: %v", err)
 	}
-}
+	checker := analyzer.NewIgnoreChecker(fset, node)
+	checker.Filter(nil)
 
-func TestIsGoFile(t *testing.T) {
-	if !isGoFile("main.go", stubFileInfo{name: "main.go"}) {
-		t.Fatalf("expected .go file to be detected")
-	}
-	if isGoFile("data.txt", stubFileInfo{name: "data.txt"}) {
-		t.Fatalf("expected non-go file to be skipped")
+	remaining := removeStaleDirectives(checker.StaleDirectives())
+	if len(remaining) != 1 || remaining[0].Line != 6 {
+		t.Fatalf("expected only the malformed directive to be reported, got %+v", remaining)
 	}
-}
 
-func TestCountLinesHandlesMissingNewline(t *testing.T) {
-	dir := t.TempDir()
-	filePath := filepath.Join(dir, "sample.go")
-	if err := os.WriteFile(filePath, []byte("line1\nline2"), 0o644); err != nil {
-		t.Fatalf("failed writing temp file: %v", err)
+	got, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed reading temp file: %v", err)
 	}
-
-	if got := countLines(filePath); got != 2 {
-		t.Fatalf("expected 2 lines, got %d", got)
+	want := "package sample\n\nfunc f() {}\n\n// abc:ignore[until=bad] RaceCondition\nfunc g() {}\n"
+	if string(got) != want {
+		t.Fatalf("unexpected file content:\n%s", got)
 	}
 }
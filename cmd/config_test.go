@@ -102,3 +102,31 @@ func TestLoadIgnoreFile(t *testing.T) {
 		t.Fatalf("unexpected patterns: %v", patterns)
 	}
 }
+
+func TestParseIgnoreRules(t *testing.T) {
+	lines := []string{
+		"# comment",
+		"vendor/",
+		"internal/legacy/** PVE-080,PVE-001",
+		"*.pb.go AIGeneratedComment # generated code",
+		"api/** RaceCondition GoroutineLeak",
+	}
+
+	if patterns := parseIgnoreLines(lines); len(patterns) != 1 || patterns[0] != "vendor" {
+		t.Fatalf("rule entries must not become path excludes, got %v", patterns)
+	}
+
+	rules := parseIgnoreRules(lines)
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(rules))
+	}
+	if rules[0].FilePattern != "internal/legacy/**" || len(rules[0].RuleTypes) != 2 || rules[0].RuleTypes[1] != "PVE-001" {
+		t.Fatalf("unexpected first rule: %+v", rules[0])
+	}
+	if len(rules[1].RuleTypes) != 1 || rules[1].RuleTypes[0] != "AIGeneratedComment" {
+		t.Fatalf("trailing comment should be dropped: %+v", rules[1])
+	}
+	if len(rules[2].RuleTypes) != 2 {
+		t.Fatalf("space separated rules should be accepted: %+v", rules[2])
+	}
+}
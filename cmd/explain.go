@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
+)
+
+// explainWidth is the column explain wraps prose at
+const explainWidth = 80
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <PVE-ID|rule>",
+	Short: "Show the documentation of a rule",
+	Long: `Shows why a rule's issues are a problem, code it reports and how to fix it,
+the typical cost, how to suppress it and related rules.
+The rule is named by PVE ID (PVE-003) or name (DeferInLoop, DEFER_IN_LOOP).`,
+	Example: "  aibscleaner explain PVE-003\n  aibscleaner explain DeferInLoop --json",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueType, err := models.ParseIssueType(args[0])
+		if err != nil {
+			slog.Error("Unknown rule, see list-rules", "rule", args[0], "error", err)
+			os.Exit(1)
+		}
+		rule, _ := issueType.Rule()
+		if jsonOutput {
+			err = writeRulesJSON(os.Stdout, aibscleaner.NewRuleInfo(rule))
+		} else {
+			_, err = io.WriteString(os.Stdout, explainRule(rule))
+		}
+		if err != nil {
+			slog.Error("Failed to write rule", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var listRulesCmd = &cobra.Command{
+	Use:   "list-rules",
+	Short: "List all rules",
+	Long: `Lists every built-in and registered rule with its ID, severity and description.
+With --json it prints the full documentation of every rule as a JSON array.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		if jsonOutput {
+			err = writeRulesJSON(os.Stdout, aibscleaner.RuleInfos())
+		} else {
+			_, err = io.WriteString(os.Stdout, ruleList(models.Rules()))
+		}
+		if err != nil {
+			slog.Error("Failed to write rules", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func writeRulesJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// ruleList lists rules one per line
+func ruleList(rules []models.Rule) string {
+	var sb strings.Builder
+	for _, rule := range rules {
+		fmt.Fprintf(&sb, "%-8s %-6s %-32s %s\n", rule.ID, strings.ToUpper(rule.Severity.String()), rule.Name, rule.Description)
+	}
+	return sb.String()
+}
+
+// explainRule renders the documentation of rule for the terminal
+func explainRule(rule models.Rule) string {
+	var sb strings.Builder
+	title := rule.ID + ": " + rule.Name
+	fmt.Fprintf(&sb, "%s\n%s\n\n", title, strings.Repeat("=", len(title)))
+	fmt.Fprintf(&sb, "Severity: %s\nAnalyzer: %s\nGroup:    %s %s\n",
+		strings.ToUpper(rule.Severity.String()), rule.Analyzer, groupIcon(rule.Group), rule.Group)
+	if len(rule.Tags) > 0 {
+		fmt.Fprintf(&sb, "Tags:     %s\n", strings.Join(rule.Tags, ", "))
+	}
+	if rule.Fixable {
+		sb.WriteString("Fixable:  yes, with --fix\n")
+	}
+	sb.WriteString("\n" + wrapText(rule.Description, explainWidth, "") + "\n")
+
+	if rule.Why != "" {
+		explainSection(&sb, "Why it's bad", wrapText(rule.Why, explainWidth, "  "))
+	}
+	if rule.Bad != "" {
+		explainSection(&sb, "Bad", indentLines(rule.Bad, "    "))
+		explainSection(&sb, "Good", indentLines(rule.Good, "    "))
+	}
+	if rule.Cost != "" {
+		explainSection(&sb, "Typical cost", wrapText(rule.Cost, explainWidth, "  "))
+	}
+
+	explainSection(&sb, "How to suppress", strings.Join([]string{
+		"  Statement:   // abc:ignore " + rule.ID + " -- reason",
+		"  Line:        x := f() // abc:ignore-line " + rule.ID,
+		"  .abcignore:  path/** " + rule.ID,
+		"  Config:      paths.ignore_rules: [{file_pattern: \"path/**\", rule_types: [" + rule.ID + "]}]",
+	}, "\n"))
+
+	if len(rule.Related) > 0 {
+		var related []string
+		for _, t := range rule.Related {
+			r, _ := t.Rule()
+			related = append(related, "  "+r.ID+" "+r.Name, wrapText(r.Description, explainWidth, "    "))
+		}
+		explainSection(&sb, "Related rules", strings.Join(related, "\n"))
+	}
+	return sb.String()
+}
+
+func explainSection(sb *strings.Builder, heading, body string) {
+	fmt.Fprintf(sb, "\n%s:\n%s\n", heading, body)
+}
+
+// wrapText wraps each paragraph of text at width columns, prefixing every
+// line with indent
+func wrapText(text string, width int, indent string) string {
+	var lines []string
+	for i, paragraph := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		line := indent
+		for _, word := range strings.Fields(paragraph) {
+			if line != indent && len(line)+1+len(word) > width {
+				lines = append(lines, line)
+				line = indent
+			}
+			if line != indent {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines prefixes every non-empty line of code with indent
+func indentLines(code, indent string) string {
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func TestExplainRuleShowsFullDocumentation(t *testing.T) {
+	rule, _ := models.IssueDeferInLoop.Rule()
+	text := explainRule(rule)
+	for _, want := range []string{
+		"PVE-003: DeferInLoop",
+		"Fixable:  yes, with --fix",
+		"Why it's bad:\n  A deferred call runs when the surrounding function returns",
+		"Bad:\n    for _, name := range names {",
+		"Good:\n",
+		"Typical cost:\n",
+		"// abc:ignore PVE-003 -- reason",
+		"path/** PVE-003",
+		"Related rules:\n  PVE-019 MemoryLeak\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("explain output misses %q:\n%s", want, text)
+		}
+	}
+	prose, _, _ := strings.Cut(text, "How to suppress:")
+	for line := range strings.Lines(prose) {
+		if !strings.HasPrefix(line, "    ") && len(line) > explainWidth+1 {
+			t.Errorf("line is not wrapped: %q", line)
+		}
+	}
+}
+
+func TestExplainRuleWithoutLongFormDocs(t *testing.T) {
+	rule, _ := models.IssueRaceConditionGlobal.Rule()
+	rule.Why, rule.Cost, rule.Related = "", "", nil
+	text := explainRule(rule)
+	if !strings.Contains(text, rule.Description[:40]) || !strings.Contains(text, "How to suppress:") {
+		t.Errorf("explain output misses description or suppression:\n%s", text)
+	}
+	if strings.Contains(text, "Why it's bad:") || strings.Contains(text, "Related rules:") {
+		t.Errorf("explain output has empty sections:\n%s", text)
+	}
+}
+
+func TestRuleListHasOneLinePerRule(t *testing.T) {
+	rules := models.Rules()
+	list := ruleList(rules)
+	if got := strings.Count(list, "\n"); got != len(rules) {
+		t.Fatalf("list-rules prints %d lines for %d rules", got, len(rules))
+	}
+	if !strings.HasPrefix(list, "PVE-000  ") {
+		t.Errorf("list-rules does not start with PVE-000:\n%s", list[:80])
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	got := wrapText("aaa bbb ccc\n\nddd", 9, "  ")
+	if want := "  aaa bbb\n  ccc\n\n  ddd"; got != want {
+		t.Errorf("wrapText = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const diffContextLines = 3
+
+// applyFixes applies the suggested fixes of issues package by package. With
+// dryRun the changes are written to out as unified diffs and no file is touched.
+// It returns the issues that are still present afterwards.
+func applyFixes(issues []*models.Issue, dryRun bool, out io.Writer) []*models.Issue {
+	byDir := make(map[string][]*models.Issue)
+	for _, issue := range issues {
+		if issue.Fix != nil && issue.Position.Filename != "" {
+			dir := filepath.Dir(issue.Position.Filename)
+			byDir[dir] = append(byDir[dir], issue)
+		}
+	}
+	dirs := make([]string, 0, len(byDir))
+	for dir := range byDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	fixed := make(map[*models.Issue]bool)
+	files := 0
+	for _, dir := range dirs {
+		applied, changed, err := fixPackage(byDir[dir], dryRun, out)
+		if err != nil {
+			slog.Warn("Failed to apply fixes", "dir", dir, "error", err)
+			continue
+		}
+		files += changed
+		for _, issue := range applied {
+			fixed[issue] = true
+		}
+	}
+
+	if dryRun {
+		return issues
+	}
+	if len(fixed) > 0 {
+		fmt.Fprintf(os.Stderr, "Fixed %d issues in %d files\n", len(fixed), files)
+	}
+
+	remaining := make([]*models.Issue, 0, len(issues)-len(fixed))
+	for _, issue := range issues {
+		if !fixed[issue] {
+			remaining = append(remaining, issue)
+		}
+	}
+	return remaining
+}
+
+// fixPackage applies the fixes of issues from one package directory together,
+// since a fix may edit other files of the package. It returns the applied
+// issues and the number of changed files.
+func fixPackage(issues []*models.Issue, dryRun bool, out io.Writer) ([]*models.Issue, int, error) {
+	sources := make(map[string][]byte)
+	modes := make(map[string]os.FileMode)
+	seen := make(map[string]bool)
+	read := func(filename string) error {
+		abs, err := filepath.Abs(filename)
+		if err != nil {
+			abs = filename
+		}
+		if seen[abs] {
+			return nil
+		}
+		seen[abs] = true
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		sources[filename] = src
+		modes[filename] = info.Mode().Perm()
+		return nil
+	}
+	for _, issue := range issues {
+		if err := read(issue.Position.Filename); err != nil {
+			return nil, 0, err
+		}
+	}
+	for _, issue := range issues {
+		for _, edit := range issue.Fix.Edits {
+			if edit.Filename == "" {
+				continue
+			}
+			if err := read(edit.Filename); err != nil {
+				slog.Debug("Skipping fix for unreadable file", "file", edit.Filename, "error", err)
+			}
+		}
+	}
+
+	result, applied, err := analyzer.ApplyFixes(sources, issues)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filenames := make([]string, 0, len(result))
+	for filename, fixed := range result {
+		if !bytes.Equal(sources[filename], fixed) {
+			filenames = append(filenames, filename)
+		}
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		if dryRun {
+			if _, err := io.WriteString(out, unifiedDiff(filename, sources[filename], result[filename])); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		if err := os.WriteFile(filename, result[filename], modes[filename]); err != nil {
+			return nil, 0, err
+		}
+	}
+	return applied, len(filenames), nil
+}
+
+// unifiedDiff renders the line difference between before and after in unified format
+func unifiedDiff(filename string, before, after []byte) string {
+	a, b := splitLines(before), splitLines(after)
+	ops := diffLines(a, b)
+	if len(ops) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	if name, ok := diffPath(filename); ok {
+		sb.WriteString("--- a/" + name + "\n")
+		sb.WriteString("+++ b/" + name + "\n")
+	} else {
+		sb.WriteString("--- " + name + "\n")
+		sb.WriteString("+++ " + name + "\n")
+	}
+
+	for start := 0; start < len(ops); {
+		// Find the next change and the end of its hunk
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		from := max(0, start-diffContextLines)
+		end := start
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContextLines {
+				break
+			}
+			end = next
+		}
+		to := min(len(ops), end+diffContextLines)
+
+		writeHunk(&sb, ops[from:to])
+		start = to
+	}
+	return sb.String()
+}
+
+// diffPath returns filename relative to the working directory in slash form and
+// true, or its cleaned absolute path and false when it lies outside of it
+func diffPath(filename string) (string, bool) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(filename)), !filepath.IsAbs(filename)
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, abs); err == nil && filepath.IsLocal(rel) {
+			return filepath.ToSlash(rel), true
+		}
+	}
+	return filepath.ToSlash(abs), false
+}
+
+type diffOp struct {
+	kind         byte // ' ', '-' or '+'
+	text         string
+	aLine, bLine int // 1-based line numbers before the op
+}
+
+func writeHunk(sb *strings.Builder, ops []diffOp) {
+	aCount, bCount := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			aCount++
+		}
+		if op.kind != '-' {
+			bCount++
+		}
+	}
+	aStart, bStart := ops[0].aLine, ops[0].bLine
+	if aCount == 0 {
+		aStart--
+	}
+	if bCount == 0 {
+		bStart--
+	}
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+	for _, op := range ops {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.text)
+		if !strings.HasSuffix(op.text, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func splitLines(src []byte) []string {
+	if len(src) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(src), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines computes a shortest edit script between a and b with the Myers
+// algorithm and returns it as a sequence of kept, removed and added lines
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	trace := make([][]int, 0, 16)
+
+search:
+	for d := 0; d <= n+m; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace backwards to recover the edit script
+	ops := make([]diffOp, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		prev := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev[offset+k-1] < prev[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := prev[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{kind: ' ', text: a[x], aLine: x + 1, bLine: y + 1})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{kind: '+', text: b[prevY], aLine: prevX + 1, bLine: prevY + 1})
+		} else {
+			ops = append(ops, diffOp{kind: '-', text: a[prevX], aLine: prevX + 1, bLine: prevY + 1})
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	for _, op := range ops {
+		if op.kind != ' ' {
+			return ops
+		}
+	}
+	return nil
+}
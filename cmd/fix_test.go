@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	before := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n")
+	after := []byte("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\n")
+
+	want := "--- a/x.go\n+++ b/x.go\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -8,3 +8,4 @@\n h\n i\n j\n+k\n"
+	if got := unifiedDiff("x.go", before, after); got != want {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+	if got := unifiedDiff("x.go", before, before); got != "" {
+		t.Fatalf("expected empty diff for identical input, got:\n%s", got)
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	src := "package main\n\nvar a = 1\n"
+	filename := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fixable := &models.Issue{
+		Position: token.Position{Filename: filename, Line: 3},
+		Fix:      &models.SuggestedFix{Edits: []models.TextEdit{{Start: 18, End: 19, NewText: "b"}}},
+	}
+	other := &models.Issue{Position: token.Position{Filename: filename, Line: 1}}
+	issues := []*models.Issue{fixable, other}
+
+	var out bytes.Buffer
+	remaining := applyFixes(issues, true, &out)
+	if len(remaining) != 2 {
+		t.Fatalf("dry run should keep all issues, got %d", len(remaining))
+	}
+	if !bytes.Contains(out.Bytes(), []byte("-var a = 1\n+var b = 1\n")) {
+		t.Fatalf("dry run diff missing change:\n%s", out.String())
+	}
+	if data, _ := os.ReadFile(filename); string(data) != src {
+		t.Fatalf("dry run modified the file:\n%s", data)
+	}
+
+	remaining = applyFixes(issues, false, &out)
+	if len(remaining) != 1 || remaining[0] != other {
+		t.Fatalf("expected only the unfixable issue to remain, got %v", remaining)
+	}
+	data, _ := os.ReadFile(filename)
+	if string(data) != "package main\n\nvar b = 1\n" {
+		t.Fatalf("unexpected fixed source:\n%s", data)
+	}
+	if info, _ := os.Stat(filename); info.Mode().Perm() != 0o600 {
+		t.Fatalf("file mode not preserved: %v", info.Mode())
+	}
+}
+
+func TestApplyFixesDryRunCrossFileHeaders(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main.go")
+	other := filepath.Join(dir, "other.go")
+	if err := os.WriteFile(main, []byte("package main\n\nvar a = 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(other, []byte("package main\n\nvar c = 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	issues := []*models.Issue{{
+		Position: token.Position{Filename: main, Line: 3},
+		Fix: &models.SuggestedFix{Edits: []models.TextEdit{
+			{Start: 18, End: 19, NewText: "b"},
+			{Filename: other, Start: 18, End: 19, NewText: "d"},
+		}},
+	}}
+
+	t.Chdir(dir)
+	var out bytes.Buffer
+	applyFixes(issues, true, &out)
+	for _, header := range []string{"--- a/main.go\n+++ b/main.go\n", "--- a/other.go\n+++ b/other.go\n"} {
+		if !bytes.Contains(out.Bytes(), []byte(header)) {
+			t.Fatalf("dry run diff missing %q:\n%s", header, out.String())
+		}
+	}
+
+	// Files outside the working directory keep their absolute path
+	t.Chdir(t.TempDir())
+	out.Reset()
+	applyFixes(issues, true, &out)
+	header := "--- " + filepath.ToSlash(other) + "\n+++ " + filepath.ToSlash(other) + "\n"
+	if !bytes.Contains(out.Bytes(), []byte(header)) || bytes.Contains(out.Bytes(), []byte("a//")) {
+		t.Fatalf("dry run diff should name %s by its absolute path:\n%s", other, out.String())
+	}
+}
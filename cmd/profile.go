@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
+)
+
+// startProfiling starts a CPU profile written to cpuFile, if set, and returns
+// the function that stops it and writes a heap profile to memFile, if set
+func startProfiling(cpuFile, memFile string) (func() error, error) {
+	var cpu *os.File
+	if cpuFile != "" {
+		var err error
+		if cpu, err = os.Create(cpuFile); err != nil {
+			return nil, err
+		}
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			_ = cpu.Close()
+			return nil, err
+		}
+	}
+
+	return func() error {
+		var errs []error
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			errs = append(errs, cpu.Close())
+		}
+		if memFile != "" {
+			errs = append(errs, writeHeapProfile(memFile))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+func writeHeapProfile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	// Collect garbage so the profile shows the memory still in use
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// printStats prints the --stats report: the cost of every analyzer and the
+// slowest files
+func printStats(w io.Writer, stats aibscleaner.Stats) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\nAnalysis took %s\n\n", stats.Duration.Round(time.Millisecond)))
+
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "Analyzer\tTime\tAllocated\tIssues\tFiles\t")
+	for _, a := range stats.Analyzers {
+		_, _ = fmt.Fprintf(
+			tw, "%s\t%s\t%s\t%d\t%d\t\n", a.Name, a.Duration.Round(time.Microsecond), formatBytes(a.Allocs), a.Issues, a.Files,
+		)
+	}
+	_ = tw.Flush()
+
+	if len(stats.SlowestFiles) > 0 {
+		sb.WriteString("\nSlowest files:\n")
+		for _, f := range stats.SlowestFiles {
+			sb.WriteString(fmt.Sprintf("  %10s  %s\n", f.Duration.Round(time.Microsecond), f.Filename))
+		}
+	}
+	_, _ = io.WriteString(w, sb.String())
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
+)
+
+func TestPrintStats(t *testing.T) {
+	var buf bytes.Buffer
+	printStats(&buf, aibscleaner.Stats{
+		Duration:     1500 * time.Millisecond,
+		Analyzers:    []aibscleaner.AnalyzerStats{{Name: "loop", Files: 3, Issues: 2, Duration: time.Millisecond, Allocs: 2048}},
+		SlowestFiles: []aibscleaner.FileTiming{{Filename: "slow.go", Duration: time.Second}},
+	})
+
+	out := buf.String()
+	for _, want := range []string{"Analysis took 1.5s", "loop", "2.0 KiB", "Slowest files:", "slow.go"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[uint64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 3 << 20: "3.0 MiB"}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestStartProfiling(t *testing.T) {
+	dir := t.TempDir()
+	cpu, mem := filepath.Join(dir, "cpu.out"), filepath.Join(dir, "mem.out")
+
+	stop, err := startProfiling(cpu, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range []string{cpu, mem} {
+		if info, err := os.Stat(filename); err != nil || info.Size() == 0 {
+			t.Errorf("expected a profile in %s: %v", filename, err)
+		}
+	}
+}
@@ -0,0 +1,729 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
+	"github.com/SergeiSkv/AiBsCleaner/version"
+)
+
+const terminalReport = "terminal"
+
+// reportRun is what reports are written from: the issues of a run, the files
+// it analyzed and the rules it checked them for
+type reportRun struct {
+	Target string
+	Issues []*models.Issue
+	Files  []string           // analyzed files, which may have no issues
+	Rules  []models.IssueType // checked rules, in PVE order
+}
+
+// reportWriter writes a run in one report format
+type reportWriter func(w io.Writer, run *reportRun) error
+
+var reportWriters = map[string]reportWriter{
+	"json":       writeJSONReport,
+	"checkstyle": writeCheckstyleReport,
+	"junit":      writeJUnitReport,
+	"gitlab":     writeGitLabReport,
+	"jsonl":      writeJSONLReport,
+	"sarif":      writeSARIFReport,
+	"html":       writeHTMLReport,
+}
+
+// deprecatedReports maps the formats --report used to accept, which were
+// ignored in favor of the terminal report, to the format they print
+var deprecatedReports = map[string]string{
+	"markdown": terminalReport,
+	"all":      terminalReport,
+}
+
+// streamingReports are written while files are analyzed when possible
+var streamingReports = map[string]bool{"jsonl": true}
+
+// reportSpec is one entry of --report: a format and the file it is written
+// to, or stdout when Path is empty
+type reportSpec struct {
+	Format string
+	Path   string
+}
+
+// parseReportSpecs parses a comma separated list of format[=path] entries.
+// --json turns the stdout terminal report into a JSON one. When every report
+// goes to a file the terminal report is still printed.
+func parseReportSpecs(value string, jsonOutput bool) ([]reportSpec, error) {
+	var specs []reportSpec
+	toStdout := 0
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		format, path, _ := strings.Cut(entry, "=")
+		spec := reportSpec{Format: strings.ToLower(strings.TrimSpace(format)), Path: strings.TrimSpace(path)}
+		if replacement, ok := deprecatedReports[spec.Format]; ok {
+			if spec.Path != "" {
+				return nil, fmt.Errorf("report format %q was never written to files; use one of: %s", spec.Format, reportFormatNames())
+			}
+			slog.Warn("Deprecated report format, use the replacement", "format", spec.Format, "replacement", replacement)
+			spec.Format = replacement
+		}
+		if spec.Format != terminalReport && reportWriters[spec.Format] == nil {
+			return nil, fmt.Errorf("unknown report format %q (available: %s)", spec.Format, reportFormatNames())
+		}
+		if spec.Format == terminalReport && spec.Path != "" {
+			return nil, fmt.Errorf("the terminal report can only be written to stdout")
+		}
+		if spec.Path == "" {
+			if jsonOutput && spec.Format == terminalReport {
+				spec.Format = "json"
+			}
+			toStdout++
+		}
+		specs = append(specs, spec)
+	}
+
+	if toStdout == 0 {
+		format := terminalReport
+		if jsonOutput {
+			format = "json"
+		}
+		specs = append(specs, reportSpec{Format: format})
+	}
+	if toStdout > 1 {
+		return nil, fmt.Errorf("only one report can be written to stdout; give the others a file with format=path")
+	}
+	return specs, nil
+}
+
+func reportFormatNames() string {
+	names := []string{terminalReport}
+	for name := range reportWriters {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return strings.Join(names, ", ")
+}
+
+// writeReports writes every report, the terminal one with outputHuman
+func writeReports(specs []reportSpec, run *reportRun, config *Config) error {
+	for _, spec := range specs {
+		if spec.Format == terminalReport {
+			outputHuman(run.Target, run.Issues, config)
+			continue
+		}
+		if err := writeReport(spec, run); err != nil {
+			return fmt.Errorf("write %s report: %w", spec.Format, err)
+		}
+	}
+	return nil
+}
+
+func writeReport(spec reportSpec, run *reportRun) error {
+	write := reportWriters[spec.Format]
+	if spec.Path == "" {
+		return write(os.Stdout, run)
+	}
+
+	file, err := os.Create(spec.Path)
+	if err != nil {
+		return err
+	}
+	if err := write(file, run); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func writeJSONReport(w io.Writer, run *reportRun) error {
+	return json.NewEncoder(w).Encode(aibscleaner.NewReport(run.Target, run.Issues))
+}
+
+func writeJSONLReport(w io.Writer, run *reportRun) error {
+	jw := aibscleaner.NewJSONLWriter(w, run.Target)
+	if err := jw.WriteIssues(run.Issues); err != nil {
+		return err
+	}
+	return jw.Close()
+}
+
+// reportStream writes the streaming reports as files finish, so that their
+// issues need not be held until the end of the run
+type reportStream struct {
+	writers []*aibscleaner.JSONLWriter
+	files   []*os.File
+}
+
+// openReportStream opens the streaming reports among specs and returns the
+// specs left to writeReports. The stream is nil when there is nothing to stream.
+func openReportStream(specs []reportSpec, target string) (*reportStream, []reportSpec, error) {
+	var rest []reportSpec
+	stream := &reportStream{}
+	for _, spec := range specs {
+		if !streamingReports[spec.Format] {
+			rest = append(rest, spec)
+			continue
+		}
+		w := io.Writer(os.Stdout)
+		if spec.Path != "" {
+			file, err := os.Create(spec.Path)
+			if err != nil {
+				for _, opened := range stream.files {
+					_ = opened.Close()
+				}
+				return nil, nil, fmt.Errorf("write %s report: %w", spec.Format, err)
+			}
+			stream.files = append(stream.files, file)
+			w = file
+		}
+		stream.writers = append(stream.writers, aibscleaner.NewJSONLWriter(w, target))
+	}
+	if len(stream.writers) == 0 {
+		return nil, specs, nil
+	}
+	return stream, rest, nil
+}
+
+func (s *reportStream) onIssues(_ string, issues []*models.Issue) error {
+	for _, w := range s.writers {
+		if err := w.WriteIssues(issues); err != nil {
+			return fmt.Errorf("write jsonl report: %w", err)
+		}
+	}
+	return nil
+}
+
+// highIssues returns the number of HIGH issues streamed so far
+func (s *reportStream) highIssues() int {
+	return s.writers[0].Summary().High
+}
+
+// close writes the summary records and closes the report files
+func (s *reportStream) close() error {
+	var errs []error
+	for _, w := range s.writers {
+		errs = append(errs, w.Close())
+	}
+	for _, file := range s.files {
+		errs = append(errs, file.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// reportPath returns filename relative to the working directory in slash form,
+// as CI systems expect paths relative to the checkout
+func reportPath(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(filename)
+}
+
+// groupByFile returns the issues per file, files in first-seen order
+func groupByFile(issues []*models.Issue) ([]string, map[string][]*models.Issue) {
+	var files []string
+	byFile := make(map[string][]*models.Issue)
+	for _, issue := range issues {
+		filename := issue.Position.Filename
+		if _, ok := byFile[filename]; !ok {
+			files = append(files, filename)
+		}
+		byFile[filename] = append(byFile[filename], issue)
+	}
+	return files, byFile
+}
+
+// reportFiles returns the analyzed files and the files with issues, sorted
+func reportFiles(run *reportRun) []string {
+	seen := make(map[string]bool, len(run.Files))
+	var files []string
+	for _, filename := range run.Files {
+		if !seen[filename] {
+			seen[filename] = true
+			files = append(files, filename)
+		}
+	}
+	for _, issue := range run.Issues {
+		if filename := issue.Position.Filename; !seen[filename] {
+			seen[filename] = true
+			files = append(files, filename)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// reportRules returns the checked rules and the rules of issues, which may
+// come from outside the analyzers, in PVE order
+func reportRules(checked []models.IssueType, issues []*models.Issue) []models.IssueType {
+	rules := slices.Clone(checked)
+	for _, issue := range issues {
+		if !slices.Contains(rules, issue.Type) {
+			rules = append(rules, issue.Type)
+		}
+	}
+	slices.Sort(rules)
+	return rules
+}
+
+// Checkstyle XML, as consumed by the Jenkins Warnings plugin and most CI tools
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func checkstyleSeverity(severity models.SeverityLevel) string {
+	switch severity {
+	case models.SeverityLevelHigh:
+		return "error"
+	case models.SeverityLevelMedium:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func writeCheckstyleReport(w io.Writer, run *reportRun) error {
+	report := checkstyleReport{Version: "4.3"}
+	files, byFile := groupByFile(run.Issues)
+	for _, filename := range files {
+		file := checkstyleFile{Name: reportPath(filename)}
+		for _, issue := range byFile[filename] {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     issue.Position.Line,
+				Column:   issue.Position.Column,
+				Severity: checkstyleSeverity(issue.Severity),
+				Message:  issueReportMessage(issue),
+				Source:   "aibscleaner." + issue.Type.String(),
+			})
+		}
+		report.Files = append(report.Files, file)
+	}
+	return writeXML(w, report)
+}
+
+// JUnit XML: a test suite per analyzed file with a test case per checked rule,
+// failing when the rule reported issues in the file
+
+type junitReport struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+func writeJUnitReport(w io.Writer, run *reportRun) error {
+	report := junitReport{Name: "aibscleaner"}
+	_, byFile := groupByFile(run.Issues)
+	for _, filename := range reportFiles(run) {
+		path := reportPath(filename)
+		suite := junitSuite{Name: path}
+
+		byRule := make(map[models.IssueType][]*models.Issue)
+		for _, issue := range byFile[filename] {
+			byRule[issue.Type] = append(byRule[issue.Type], issue)
+		}
+
+		for _, rule := range reportRules(run.Rules, byFile[filename]) {
+			testCase := junitCase{Name: rule.String(), Classname: path}
+			if issues := byRule[rule]; len(issues) > 0 {
+				var text strings.Builder
+				for _, issue := range issues {
+					fmt.Fprintf(&text, "%s:%d:%d: %s\n", path, issue.Position.Line, issue.Position.Column, issueReportMessage(issue))
+				}
+				testCase.Failure = &junitFailure{
+					Message: fmt.Sprintf("%d %s issue(s)", len(issues), rule),
+					Type:    rule.GetPVEID(),
+					Text:    text.String(),
+				}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		if len(suite.Cases) == 0 {
+			continue
+		}
+		suite.Tests = len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	// CI systems treat a report without test cases as broken
+	if len(report.Suites) == 0 {
+		report.Tests = 1
+		report.Suites = []junitSuite{{
+			Name:  "aibscleaner",
+			Tests: 1,
+			Cases: []junitCase{{Name: reportPath(run.Target), Classname: "aibscleaner"}},
+		}}
+	}
+	return writeXML(w, report)
+}
+
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// GitLab Code Quality report, see
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+func gitlabSeverity(severity models.SeverityLevel) string {
+	switch severity {
+	case models.SeverityLevelHigh:
+		return "critical"
+	case models.SeverityLevelMedium:
+		return "major"
+	default:
+		return "minor"
+	}
+}
+
+func writeGitLabReport(w io.Writer, run *reportRun) error {
+	report := make([]gitlabIssue, 0, len(run.Issues))
+	seen := make(map[string]int)
+	for _, issue := range run.Issues {
+		path := reportPath(issue.Position.Filename)
+		fingerprint := issueFingerprint(path, issue, seen)
+		report = append(report, gitlabIssue{
+			Description: issueReportMessage(issue),
+			CheckName:   issue.Type.String(),
+			Fingerprint: fingerprint,
+			Severity:    gitlabSeverity(issue.Severity),
+			Location: gitlabLocation{
+				Path:  path,
+				Lines: gitlabLines{Begin: issue.Position.Line, End: issue.EndLine},
+			},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// issueFingerprint identifies an issue across runs. It leaves out the line so
+// that edits elsewhere in the file do not turn the issue into a new one, and
+// numbers identical issues in the same file to keep them apart.
+func issueFingerprint(path string, issue *models.Issue, seen map[string]int) string {
+	key := strings.Join([]string{path, issue.Type.String(), issue.Message, strings.TrimSpace(issue.Code)}, "\x00")
+	n := seen[key]
+	seen[key]++
+
+	sum := sha256.Sum256([]byte(key + "\x00" + strconv.Itoa(n)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// SARIF 2.1.0, as consumed by GitHub code scanning and most security
+// dashboards, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifText          `json:"shortDescription"`
+	FullDescription      *sarifText         `json:"fullDescription,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifText         `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+func sarifLevel(severity models.SeverityLevel) string {
+	switch severity {
+	case models.SeverityLevelHigh:
+		return "error"
+	case models.SeverityLevelMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func writeSARIFReport(w io.Writer, run *reportRun) error {
+	driver := sarifDriver{
+		Name:           "aibscleaner",
+		Version:        version.Version,
+		InformationURI: "https://github.com/SergeiSkv/AiBsCleaner",
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := make(map[models.IssueType]int)
+	for _, t := range reportRules(run.Rules, run.Issues) {
+		rule, _ := t.Rule()
+		description := rule.Description
+		if description == "" {
+			description = t.String()
+		}
+		sr := sarifRule{
+			ID:                   t.GetPVEID(),
+			Name:                 t.String(),
+			ShortDescription:     sarifText{Text: description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		}
+		if rule.Why != "" {
+			sr.FullDescription = &sarifText{Text: rule.Why}
+		}
+		ruleIndex[t] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sr)
+	}
+
+	results := make([]sarifResult, 0, len(run.Issues))
+	seen := make(map[string]int)
+	for _, issue := range run.Issues {
+		path := reportPath(issue.Position.Filename)
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: path}}
+		// SARIF lines start at 1; issues on a whole file, such as go.mod, have none
+		if issue.Position.Line > 0 {
+			region := &sarifRegion{StartLine: issue.Position.Line, StartColumn: issue.Position.Column}
+			if issue.EndLine >= issue.Position.Line {
+				region.EndLine = issue.EndLine
+				region.EndColumn = issue.EndColumn
+			}
+			location.Region = region
+		}
+		results = append(results, sarifResult{
+			RuleID:              issue.Type.GetPVEID(),
+			RuleIndex:           ruleIndex[issue.Type],
+			Level:               sarifLevel(issue.Severity),
+			Message:             sarifText{Text: issueReportMessage(issue)},
+			Locations:           []sarifLocation{{PhysicalLocation: location}},
+			PartialFingerprints: map[string]string{"aibscleaner/v1": issueFingerprint(path, issue, seen)},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// HTML: a single self-contained page with the issues grouped by file
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>aibscleaner report: {{.Target}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ddd; padding: 0.4em; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+pre { margin: 0.3em 0 0; white-space: pre-wrap; }
+.high { color: #b00020; font-weight: bold; }
+.medium { color: #b26a00; }
+.low { color: #555; }
+</style>
+</head>
+<body>
+<h1>aibscleaner report: {{.Target}}</h1>
+<p>{{.Files}} file(s) analyzed, {{.Issues}} issue(s): <span class="high">{{.High}} high</span>, <span class="medium">{{.Medium}} medium</span>, <span class="low">{{.Low}} low</span></p>
+{{- range .Groups}}
+<h2>{{.Path}}</h2>
+<table>
+<tr><th>Line</th><th>Severity</th><th>Rule</th><th>Issue</th></tr>
+{{- range .Issues}}
+<tr>
+<td>{{.Line}}:{{.Column}}</td>
+<td class="{{.Severity}}">{{.Severity}}</td>
+<td>{{.RuleID}} {{.Rule}}</td>
+<td>{{.Message}}{{if .Suggestion}}<br><em>{{.Suggestion}}</em>{{end}}{{if .Code}}<pre>{{.Code}}</pre>{{end}}</td>
+</tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No performance issues found.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type htmlReport struct {
+	Target            string
+	Files, Issues     int
+	High, Medium, Low int
+	Groups            []htmlFile
+}
+
+type htmlFile struct {
+	Path   string
+	Issues []htmlIssue
+}
+
+type htmlIssue struct {
+	Line, Column              int
+	Severity, RuleID, Rule    string
+	Message, Suggestion, Code string
+}
+
+func writeHTMLReport(w io.Writer, run *reportRun) error {
+	report := htmlReport{Target: reportPath(run.Target), Files: len(run.Files), Issues: len(run.Issues)}
+	report.High, report.Medium, report.Low = countBySeverity(run.Issues)
+	files, byFile := groupByFile(run.Issues)
+	for _, filename := range files {
+		group := htmlFile{Path: reportPath(filename)}
+		for _, issue := range byFile[filename] {
+			group.Issues = append(group.Issues, htmlIssue{
+				Line:       issue.Position.Line,
+				Column:     issue.Position.Column,
+				Severity:   strings.ToLower(issue.Severity.String()),
+				RuleID:     issue.Type.GetPVEID(),
+				Rule:       issue.Type.String(),
+				Message:    issue.Message,
+				Suggestion: issue.Suggestion,
+				Code:       issue.Code,
+			})
+		}
+		report.Groups = append(report.Groups, group)
+	}
+	return htmlReportTemplate.Execute(w, report)
+}
+
+// issueReportMessage returns the message with the suggestion, if any
+func issueReportMessage(issue *models.Issue) string {
+	if issue.Suggestion == "" {
+		return issue.Message
+	}
+	return issue.Message + " - " + issue.Suggestion
+}
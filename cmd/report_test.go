@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func reportIssues() []*models.Issue {
+	return []*models.Issue{
+		{
+			Type: models.IssueDeferInLoop, Severity: models.SeverityLevelHigh,
+			Message: "Defer in loop", Suggestion: "Move it out",
+			Position: token.Position{Filename: "a.go", Line: 3, Column: 2},
+		},
+		{
+			Type: models.IssueDeferInLoop, Severity: models.SeverityLevelHigh,
+			Message: "Defer in loop", Suggestion: "Move it out",
+			Position: token.Position{Filename: "a.go", Line: 9, Column: 2},
+		},
+		{
+			Type: models.IssueRegexCompileInLoop, Severity: models.SeverityLevelLow,
+			Message:  "Regex <compiled> in loop",
+			Position: token.Position{Filename: "b.go", Line: 5, Column: 1},
+		},
+	}
+}
+
+func TestParseReportSpecs(t *testing.T) {
+	tests := []struct {
+		value string
+		json  bool
+		want  []reportSpec
+		err   bool
+	}{
+		{value: "terminal", want: []reportSpec{{Format: "terminal"}}},
+		{value: "terminal", json: true, want: []reportSpec{{Format: "json"}}},
+		{value: "checkstyle", want: []reportSpec{{Format: "checkstyle"}}},
+		{
+			value: "junit=out/junit.xml, GitLab=gl.json",
+			want:  []reportSpec{{"junit", "out/junit.xml"}, {"gitlab", "gl.json"}, {Format: "terminal"}},
+		},
+		{value: "gitlab=gl.json", json: true, want: []reportSpec{{"gitlab", "gl.json"}, {Format: "json"}}},
+		{
+			value: "sarif=out.sarif,html=report.html",
+			want:  []reportSpec{{"sarif", "out.sarif"}, {"html", "report.html"}, {Format: "terminal"}},
+		},
+		{value: "html", want: []reportSpec{{Format: "html"}}},
+		// Formats that were accepted but ignored print what they used to
+		{value: "all", json: true, want: []reportSpec{{Format: "json"}}},
+		{value: "markdown,junit=junit.xml", want: []reportSpec{{Format: "terminal"}, {"junit", "junit.xml"}}},
+		{value: "markdown=report.md", err: true},
+		{value: "pdf", err: true},
+		{value: "json,checkstyle", err: true},
+		{value: "terminal=out.txt", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseReportSpecs(tt.value, tt.json)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %v", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %v, got %v", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestWriteCheckstyleReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeCheckstyleReport(&buf, &reportRun{Target: ".", Issues: reportIssues()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var report checkstyleReport
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if len(report.Files) != 2 || report.Files[0].Name != "a.go" || len(report.Files[0].Errors) != 2 {
+		t.Fatalf("unexpected files: %+v", report.Files)
+	}
+	got := report.Files[1].Errors[0]
+	want := checkstyleError{
+		Line: 5, Column: 1, Severity: "info", Message: "Regex <compiled> in loop", Source: "aibscleaner.RegexCompileInLoop",
+	}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	run := &reportRun{
+		Target: ".",
+		Issues: reportIssues(),
+		Files:  []string{"c.go", "b.go", "a.go"},
+		Rules:  []models.IssueType{models.IssueDeferInLoop, models.IssueRegexCompileInLoop, models.IssueNestedLoop},
+	}
+	var buf bytes.Buffer
+	if err := writeJUnitReport(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+
+	var report junitReport
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	// Every checked rule is a test case of every analyzed file, passing or not
+	if report.Tests != 9 || report.Failures != 2 || len(report.Suites) != 3 {
+		t.Fatalf("expected a case per rule per file, got %+v", report)
+	}
+	a, c := report.Suites[0], report.Suites[2]
+	if a.Name != "a.go" || a.Tests != 3 || a.Failures != 1 || c.Name != "c.go" || c.Failures != 0 {
+		t.Fatalf("unexpected suites: %+v", report.Suites)
+	}
+	var failure *junitFailure
+	for _, testCase := range a.Cases {
+		if testCase.Name == models.IssueDeferInLoop.String() {
+			failure = testCase.Failure
+		} else if testCase.Failure != nil {
+			t.Fatalf("expected %s to pass in a.go, got %+v", testCase.Name, testCase.Failure)
+		}
+	}
+	if failure == nil || strings.Count(failure.Text, "\n") != 2 || !strings.Contains(failure.Text, "a.go:9:2:") {
+		t.Fatalf("expected both defer issues in the failure, got %+v", failure)
+	}
+
+	buf.Reset()
+	if err := writeJUnitReport(&buf, &reportRun{Target: "."}); err != nil {
+		t.Fatal(err)
+	}
+	report = junitReport{}
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Tests != 1 || report.Failures != 0 {
+		t.Fatalf("a run without files should report one passing case, got %+v", report)
+	}
+}
+
+func TestWriteSARIFReport(t *testing.T) {
+	issues := reportIssues()
+	issues[0].EndLine, issues[0].EndColumn = 3, 18
+	run := &reportRun{Target: ".", Issues: issues, Rules: []models.IssueType{models.IssueDeferInLoop, models.IssueNestedLoop}}
+	var buf bytes.Buffer
+	if err := writeSARIFReport(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	driver := log.Runs[0].Tool.Driver
+	// The checked rules and the rules of issues from outside them
+	if len(driver.Rules) != 3 || driver.Rules[0].Name != "NestedLoop" || driver.Rules[1].Name != "DeferInLoop" {
+		t.Fatalf("unexpected rules: %+v", driver.Rules)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	first, last := results[0], results[2]
+	if first.Level != "error" || last.Level != "note" || driver.Rules[last.RuleIndex].ID != last.RuleID {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	region := first.Locations[0].PhysicalLocation.Region
+	if first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "a.go" ||
+		region == nil || *region != (sarifRegion{StartLine: 3, StartColumn: 2, EndLine: 3, EndColumn: 18}) {
+		t.Fatalf("unexpected location: %+v", first.Locations)
+	}
+	if first.PartialFingerprints["aibscleaner/v1"] == results[1].PartialFingerprints["aibscleaner/v1"] {
+		t.Fatalf("identical issues need distinct fingerprints")
+	}
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	run := &reportRun{Target: ".", Issues: reportIssues(), Files: []string{"a.go", "b.go"}}
+	var buf bytes.Buffer
+	if err := writeHTMLReport(&buf, run); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, want := range []string{"<h2>a.go</h2>", "<h2>b.go</h2>", "2 file(s) analyzed, 3 issue(s)", "Regex &lt;compiled&gt; in loop"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected %q in the page:\n%s", want, page)
+		}
+	}
+
+	buf.Reset()
+	if err := writeHTMLReport(&buf, &reportRun{Target: "."}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No performance issues found.") {
+		t.Fatalf("expected a clean page, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteGitLabReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeGitLabReport(&buf, &reportRun{Target: ".", Issues: reportIssues()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var report []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(report) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(report))
+	}
+	if report[0].Severity != "critical" || report[2].Severity != "minor" || report[0].CheckName != "DeferInLoop" {
+		t.Fatalf("unexpected severity mapping: %+v", report)
+	}
+	if report[0].Fingerprint == report[1].Fingerprint {
+		t.Fatalf("identical issues need distinct fingerprints")
+	}
+	if report[2].Location.Path != "b.go" || report[2].Location.Lines.Begin != 5 {
+		t.Fatalf("unexpected location: %+v", report[2].Location)
+	}
+
+	// Fingerprints survive the issue moving to another line
+	moved := reportIssues()
+	moved[0].Position.Line = 30
+	buf.Reset()
+	if err := writeGitLabReport(&buf, &reportRun{Target: ".", Issues: moved}); err != nil {
+		t.Fatal(err)
+	}
+	var again []gitlabIssue
+	if err := json.Unmarshal(buf.Bytes(), &again); err != nil {
+		t.Fatal(err)
+	}
+	if again[0].Fingerprint != report[0].Fingerprint {
+		t.Fatalf("fingerprint changed with the line number")
+	}
+}
+
+func TestOpenReportStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.jsonl")
+	specs := []reportSpec{{"jsonl", path}, {"checkstyle", "cs.xml"}, {Format: "terminal"}}
+
+	stream, rest, err := openReportStream(specs, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rest, specs[1:]) {
+		t.Fatalf("expected the other reports to be left, got %v", rest)
+	}
+	issues := reportIssues()
+	if err := stream.onIssues("a.go", issues[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.onIssues("b.go", issues[2:]); err != nil {
+		t.Fatal(err)
+	}
+	if stream.highIssues() != 2 {
+		t.Fatalf("expected 2 high issues, got %d", stream.highIssues())
+	}
+	if err := stream.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[3], `"kind":"summary"`) {
+		t.Fatalf("expected 3 issues and a summary, got:\n%s", data)
+	}
+
+	if stream, rest, _ := openReportStream(specs[1:], "."); stream != nil || len(rest) != 2 {
+		t.Fatalf("nothing to stream, got %v / %v", stream, rest)
+	}
+}
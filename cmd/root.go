@@ -1,16 +1,18 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -18,6 +20,7 @@ import (
 	"github.com/SergeiSkv/AiBsCleaner/analyzer"
 	"github.com/SergeiSkv/AiBsCleaner/cache"
 	"github.com/SergeiSkv/AiBsCleaner/models"
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
 	"github.com/SergeiSkv/AiBsCleaner/version"
 )
 
@@ -33,23 +36,24 @@ var (
 	ignoreFile string
 	logger     *slog.Logger
 	cacheDB    *cache.FileCache
-)
-
-// JSONOutput represents the JSON structure for results
-type JSONOutput struct {
-	Target    string          `json:"target"`
-	Summary   Summary         `json:"summary"`
-	Issues    []*models.Issue `json:"issues"`
-	FileStats []fileStat      `json:"file_stats"`
-}
 
-// Summary contains overall statistics
-type Summary struct {
-	TotalIssues int `json:"total_issues"`
-	High        int `json:"high"`
-	Medium      int `json:"medium"`
-	Low         int `json:"low"`
-}
+	reportUnusedIgnores bool
+	removeUnusedIgnores bool
+	contextLines        int
+	fixIssues           bool
+	dryRun              bool
+	concurrency         int
+	fileTimeout         time.Duration
+	readStdin           bool
+	stdinFilename       string
+	showStats           bool
+	cpuProfile          string
+	memProfile          string
+	escapeAnalysis      bool
+
+	// stdinSources holds the source read with --stdin, keyed by --stdin-filename
+	stdinSources map[string][]byte
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "aibscleaner [path]",
@@ -63,7 +67,9 @@ Perfect for CI/CD pipelines, code reviews, and keeping your codebase clean.`,
   aibscleaner ./src                    # AnalyzeAll specific directory
   aibscleaner main.go                  # AnalyzeAll single file
   aibscleaner --json .                 # JSON output for CI/CD
-  aibscleaner --compact .              # Compact IDE-friendly output`,
+  aibscleaner -r checkstyle=cs.xml,junit=junit.xml .  # CI reports plus terminal output
+  aibscleaner --compact .              # Compact IDE-friendly output
+  aibscleaner --stdin --stdin-filename main.go < main.go  # Unsaved editor buffer`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
@@ -72,20 +78,47 @@ Perfect for CI/CD pipelines, code reviews, and keeping your codebase clean.`,
 
 		target := args[0]
 
-		// Check if target exists
-		if _, err := os.Stat(target); os.IsNotExist(err) {
+		reports, err := parseReportSpecs(reportType, jsonOutput)
+		if err != nil {
+			slog.Error("Invalid --report", "error", err)
+			os.Exit(1)
+		}
+
+		if readStdin {
+			if stdinFilename == "" {
+				slog.Error("--stdin requires --stdin-filename")
+				os.Exit(1)
+			}
+			// Fixes are computed against stdin and would corrupt the file on disk
+			if fixIssues || removeUnusedIgnores {
+				slog.Error("--fix and --remove-unused-ignores cannot be used with --stdin")
+				os.Exit(1)
+			}
+			src, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				slog.Error("Failed to read stdin", "error", err)
+				os.Exit(1)
+			}
+			target = stdinFilename
+			stdinSources = map[string][]byte{target: src}
+		} else if _, err := os.Stat(target); os.IsNotExist(err) {
+			// Check if target exists
 			slog.Error("Path does not exist", "path", target)
 			os.Exit(1)
 		}
 
 		// Initialize file cache unless --no-cache is specified
 		if !noCache {
+			root := getProjectRoot(target)
 			var err error
-			cacheDB, err = cache.New(getProjectRoot(target))
+			cacheDB, err = cache.New(root)
 			if err != nil {
 				slog.Warn("Failed to open cache database", "error", err)
 				// Continue without cache
 			}
+			if err := analyzer.EnablePersistentCache(root); err != nil {
+				slog.Warn("Failed to open result cache", "error", err)
+			}
 			defer func() {
 				if cacheDB != nil {
 					_ = cacheDB.Close()
@@ -115,17 +148,61 @@ Perfect for CI/CD pipelines, code reviews, and keeping your codebase clean.`,
 		if compact {
 			_ = os.Setenv("AIBSCLEANER_COMPACT", "1")
 		}
+		if contextLines > 0 {
+			config.Output.ShowContext = true
+			config.Output.ContextLines = contextLines
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		// Fixes change the issues after the run, so everything is written at the end then
+		var stream *reportStream
+		if !fixIssues && !removeUnusedIgnores {
+			stream, reports, err = openReportStream(reports, target)
+			if err != nil {
+				slog.Error("Failed to write report", "error", err)
+				os.Exit(1)
+			}
+		}
+
+		stopProfiling, err := startProfiling(cpuProfile, memProfile)
+		if err != nil {
+			slog.Error("Failed to start profiling", "error", err)
+			os.Exit(1)
+		}
+		run, err := analyzeTarget(ctx, target, config, stream, len(reports) > 0)
+		if err := stopProfiling(); err != nil {
+			slog.Warn("Failed to write profile", "error", err)
+		}
+		if err != nil {
+			slog.Error("Analysis failed", "error", err)
+			os.Exit(1)
+		}
+		if stream != nil {
+			if err := stream.close(); err != nil {
+				slog.Error("Failed to write report", "error", err)
+				os.Exit(1)
+			}
+		}
 
-		issues := analyzeTarget(target, config)
+		if fixIssues {
+			run.Issues = applyFixes(run.Issues, dryRun, os.Stdout)
+			if dryRun {
+				return
+			}
+		}
 
-		if jsonOutput {
-			outputJSON(target, issues)
-		} else {
-			outputHuman(target, issues)
+		if err := writeReports(reports, run, config); err != nil {
+			slog.Error("Failed to write report", "error", err)
+			os.Exit(1)
 		}
 
 		// Exit with error code if high severity issues found
-		high, _, _ := countBySeverity(issues)
+		high, _, _ := countBySeverity(run.Issues)
+		if stream != nil {
+			high = stream.highIssues()
+		}
 		if high > 0 {
 			os.Exit(1)
 		}
@@ -154,6 +231,19 @@ var versionCmd = &cobra.Command{
 	},
 }
 
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the --json report",
+	Run: func(cmd *cobra.Command, args []string) {
+		schema, err := aibscleaner.JSONSchema()
+		if err != nil {
+			slog.Error("Failed to build schema", "error", err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(schema)
+	},
+}
+
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show cache statistics",
@@ -190,75 +280,121 @@ var statsCmd = &cobra.Command{
 	},
 }
 
-var analyzers = []struct {
-	Name        string
-	Description string
-}{
-	{"LoopAnalyzer", "Detects inefficient loops and allocations"},
-	{"StringConcatAnalyzer", "Finds inefficient string concatenations"},
-	{"DeferAnalyzer", "Identifies defer misuse and overhead"},
-	{"SliceAnalyzer", "Detects slice capacity and append issues"},
-	{"MapAnalyzer", "Finds map initialization problems"},
-	{"ReflectionAnalyzer", "Warns about reflection performance impact"},
-	{"GoroutineAnalyzer", "Detects goroutine leaks and misuse"},
-	{"InterfaceAnalyzer", "Finds unnecessary interface allocations"},
-	{"RegexAnalyzer", "Identifies regex compilation in hot paths"},
-	{"TimeAnalyzer", "Detects time.After leaks and inefficiencies"},
-	{"ComplexityAnalyzer", "Measures cyclomatic complexity"},
-	{"MemoryLeakAnalyzer", "Finds potential memory leaks"},
-	{"DatabaseAnalyzer", "Detects database performance issues"},
-	{"AIBullshitDetector", "Identifies AI-generated anti-patterns"},
-	{"ContextAnalyzer", "Finds context misuse and leaks"},
-	{"ChannelAnalyzer", "Detects channel deadlocks and inefficiencies"},
-	{"RaceConditionAnalyzer", "Identifies potential race conditions"},
-	{"ErrorHandlingAnalyzer", "Finds error handling issues"},
-	{"HTTPClientAnalyzer", "Detects HTTP client problems"},
-	{"GCPressureAnalyzer", "Identifies high GC pressure patterns"},
-	{"ConcurrencyPatternsAnalyzer", "Finds concurrency anti-patterns"},
-	{"CPUOptimizationAnalyzer", "Detects CPU-intensive operations"},
-	{"NetworkPatternsAnalyzer", "Finds network performance issues"},
-	{"SyncPoolAnalyzer", "Suggests sync.Pool optimizations"},
-	{"StructLayoutAnalyzer", "Optimizes struct field alignment and memory layout"},
-	{"PrivacyAnalyzer", "Detects privacy issues and data leaks"},
-	{"DependencyAnalyzer", "Checks dependency health and vulnerabilities"},
-}
-
 var listCmd = &cobra.Command{
 	Use:   "list-analyzers",
 	Short: "List all available analyzers",
 	Long:  `Shows all available analyzers and their detection patterns.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Available Analyzers:")
-		fmt.Println("====================")
-		for _, a := range analyzers {
-			fmt.Printf("• %-30s %s\n", a.Name, a.Description)
-		}
+		fmt.Print(analyzerList())
 	},
 }
 
+// analyzerList lists the registered analyzers and the dependency checks,
+// with the number of rules each reports
+func analyzerList() string {
+	var sb strings.Builder
+	sb.WriteString("Available Analyzers:\n")
+	sb.WriteString("====================\n")
+	write := func(name, description string, rules int, disabled bool) {
+		fmt.Fprintf(&sb, "• %-20s %s", name, description)
+		switch {
+		case rules == 1:
+			sb.WriteString(" (1 rule)")
+		case rules > 1:
+			fmt.Fprintf(&sb, " (%d rules)", rules)
+		}
+		if disabled {
+			sb.WriteString(" [disabled by default]")
+		}
+		sb.WriteString("\n")
+	}
+	for _, reg := range analyzer.Registered() {
+		write(reg.Name, reg.Description, len(reg.Rules), reg.Disabled)
+	}
+	// The dependency checks run once per analyzed path rather than per file
+	write("dependency", "Deprecated, vulnerable and conflicting dependencies",
+		len(models.RulesOf(models.AnalyzerDependency)), false)
+	return sb.String()
+}
+
 func init() {
-	rootCmd.PersistentFlags().BoolVarP(&jsonOutput, "json", "j", false, "Output results in JSON format")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output results in JSON format")
 	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "Path to configuration file")
 	rootCmd.PersistentFlags().BoolVarP(&compact, "compact", "", false, "Compact IDE-friendly output")
+	rootCmd.PersistentFlags().IntVar(
+		&contextLines, "context", 0, "Show N lines of source around each issue (enables output.show_context)",
+	)
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
-	rootCmd.PersistentFlags().StringVarP(&reportType, "report", "r", "terminal", "Report format: terminal, html, markdown, json, all")
+	rootCmd.PersistentFlags().StringVarP(
+		&reportType, "report", "r", terminalReport,
+		"Reports to write as format[=file], comma separated; formats: "+reportFormatNames(),
+	)
 	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "", "info", "Log level: debug, info, warn, error")
 	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", true, "Disable cache and re-analyze all files (default: true)")
 	rootCmd.PersistentFlags().BoolVar(&clearCache, "clear-cache", false, "Clear the cache before analyzing")
+	rootCmd.PersistentFlags().IntVarP(&concurrency, "concurrency", "j", 0, "Number of files analyzed in parallel (default: number of CPUs)")
+	rootCmd.PersistentFlags().DurationVar(
+		&fileTimeout, "file-timeout", time.Minute, "Time budget per file; slower files are reported as timed out (0 disables)",
+	)
 
 	// Add flag to enable cache
 	enableCache := rootCmd.PersistentFlags().Bool("enable-cache", false, "Enable file cache for faster subsequent runs")
+	showDiff := rootCmd.PersistentFlags().Bool("diff", false, "Preview fixes as a unified diff (same as --fix --dry-run)")
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		if *enableCache {
 			noCache = false
 		}
+		if escapeAnalysis {
+			// Cached results were found without the compiler's decisions
+			noCache = true
+		}
+		if removeUnusedIgnores {
+			reportUnusedIgnores = true
+		}
+		if *showDiff {
+			fixIssues, dryRun = true, true
+		}
 	}
 	rootCmd.PersistentFlags().StringVar(&ignoreFile, "ignore-file", ".abcignore", "Path to ignore file")
+	rootCmd.PersistentFlags().BoolVar(
+		&reportUnusedIgnores, "report-unused-ignores", false,
+		"Report ignore directives that suppress nothing, have expired or name unknown rules",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&removeUnusedIgnores, "remove-unused-ignores", false,
+		"Delete stale ignore directives from source files (implies --report-unused-ignores)",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(&readStdin, "stdin", false, "Analyze Go source read from stdin (requires --stdin-filename)")
+	rootCmd.PersistentFlags().StringVar(
+		&stdinFilename, "stdin-filename", "", "Path the stdin source is reported and type-checked as",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(&fixIssues, "fix", false, "Apply suggested fixes to the source files")
+	rootCmd.PersistentFlags().BoolVar(
+		&dryRun, "dry-run", false, "With --fix, print the fixes as a unified diff instead of writing files",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&showStats, "stats", false, "Print time, allocations and issues per analyzer and the slowest files",
+	)
+	rootCmd.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "", "Write a pprof CPU profile of the run to file")
+	rootCmd.PersistentFlags().StringVar(&memProfile, "memprofile", "", "Write a pprof heap profile to file after the run")
+	rootCmd.PersistentFlags().BoolVar(
+		&escapeAnalysis, "escape", false,
+		"Confirm allocations with the compiler's escape analysis (go build -gcflags=-m=2); disables the cache",
+	)
 
 	rootCmd.AddCommand(initConfigCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(listRulesCmd)
+
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Second, "How often to scan for changed files")
+	rootCmd.AddCommand(watchCmd)
 
 	// Setup logger
 	cobra.OnInitialize(initLogger)
@@ -308,160 +444,75 @@ func Execute() error {
 	return rootCmd.Execute()
 }
 
-func analyzeTarget(target string, config *Config) []*models.Issue {
+// analyzeTarget runs the analysis, passing issues to stream as files finish.
+// Issues are only returned when keepIssues is set or there is no stream.
+func analyzeTarget(
+	ctx context.Context, target string, config *Config, stream *reportStream, keepIssues bool,
+) (*reportRun, error) {
 	if config == nil {
-		return nil
+		return &reportRun{Target: target}, nil
 	}
-	var allIssues []*models.Issue
-	var filesAnalyzed int
-	var totalLines int
 
-	// Run dependency analysis ONCE for the entire project
-	depIssues := analyzer.AnalyzeDependencies(target)
-	allIssues = append(allIssues, depIssues...)
-
-	// Collect all Go files first
-	var filesToAnalyze []string
-	err := filepath.Walk(
-		target, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
-
-			// Check if path should be skipped
-			skip, skipDir := shouldSkipPath(path, info, config.Paths.Exclude)
-			if skipDir {
-				return filepath.SkipDir
-			}
-			if skip {
-				return nil
-			}
-
-			// Collect only Go files
-			if isGoFile(path, info) {
-				filesToAnalyze = append(filesToAnalyze, path)
-			}
-
-			return nil
-		},
-	)
-
-	if err != nil {
-		slog.Error("Error scanning target", "error", err)
-		os.Exit(1)
-	}
-
-	// Analyze files in parallel
-	numWorkers := runtime.NumCPU()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	fileChan := make(chan string, len(filesToAnalyze))
-
-	// Start workers
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for path := range fileChan {
-				issues := analyzeFile(path, config)
-				lines := countLines(path)
-
-				mu.Lock()
-				allIssues = append(allIssues, issues...)
-				filesAnalyzed++
-				totalLines += lines
-				mu.Unlock()
-			}
-		}()
+	opts := aibscleaner.Options{
+		Concurrency:         concurrency,
+		FileTimeout:         fileTimeout,
+		ReportUnusedIgnores: reportUnusedIgnores,
+		Profile:             showStats,
+		EscapeAnalysis:      escapeAnalysis,
 	}
-
-	// Send files to workers
-	for _, path := range filesToAnalyze {
-		fileChan <- path
+	if stream != nil {
+		opts.OnIssues = stream.onIssues
+		opts.DiscardIssues = !keepIssues
+	}
+	if cacheDB != nil && !noCache {
+		opts.Cache = fileCache{db: cacheDB}
 	}
-	close(fileChan)
-
-	// Wait for all workers to finish
-	wg.Wait()
 
-	// Print statistics
-	if !jsonOutput {
-		fmt.Fprintf(os.Stderr, "\nAnalyzed %d files (%d lines of code)\n", filesAnalyzed, totalLines)
+	runner := aibscleaner.NewRunner(config, opts)
+	var result *aibscleaner.Result
+	var err error
+	if stdinSources != nil {
+		result, err = runner.AnalyzeSources(ctx, stdinSources)
 	} else {
-		slog.Debug("Analysis complete", "files", filesAnalyzed, "lines", totalLines)
+		result, err = runner.AnalyzePaths(ctx, target)
 	}
-
-	return allIssues
-}
-
-type fileStat struct {
-	Filename string
-	Count    int
-}
-
-func outputJSON(target string, issues []*models.Issue) {
-	// Используем срез для подсчёта по файлам
-	var fileStats []fileStat
-
-	// Функция для поиска индекса файла в срезе
-	findFileIndex := func(filename string) int {
-		for i, f := range fileStats {
-			if f.Filename == filename {
-				return i
-			}
-		}
-		return -1
+	if err != nil {
+		return nil, err
 	}
-
-	for _, issue := range issues {
-		idx := findFileIndex(issue.Position.Filename)
-		if idx == -1 {
-			fileStats = append(
-				fileStats, fileStat{
-					Filename: issue.Position.Filename,
-					Count:    1,
-				},
-			)
-		} else {
-			fileStats[idx].Count++
-		}
+	for _, err := range result.Errors {
+		slog.Warn("Error parsing file", "error", err)
 	}
 
-	// Calculate summary
-	high, medium, low := countBySeverity(issues)
-	summary := Summary{
-		TotalIssues: len(issues),
-		High:        high,
-		Medium:      medium,
-		Low:         low,
+	issues := result.Issues
+	if reportUnusedIgnores && removeUnusedIgnores && !fixIssues {
+		issues = removeStaleDirectives(issues)
 	}
 
-	// Create JSON output
-	output := JSONOutput{
-		Target:    target,
-		Summary:   summary,
-		Issues:    issues,
-		FileStats: fileStats, // тут уже срез вместо map
+	// Print statistics
+	if !jsonOutput {
+		fmt.Fprintf(os.Stderr, "\nAnalyzed %d files (%d lines of code)\n", result.Stats.Files, result.Stats.Lines)
+	} else {
+		slog.Debug("Analysis complete", "files", result.Stats.Files, "lines", result.Stats.Lines)
 	}
-
-	enc := json.NewEncoder(os.Stdout)
-	if err := enc.Encode(output); err != nil {
-		slog.Error("Error encoding JSON", "error", err)
-		os.Exit(1)
+	if showStats {
+		printStats(os.Stderr, result.Stats)
 	}
+
+	return &reportRun{Target: target, Issues: issues, Files: result.Files, Rules: runner.CheckedRules()}, nil
 }
 
-func outputHuman(_ string, issues []*models.Issue) {
+func outputHuman(_ string, issues []*models.Issue, config *Config) {
 	if len(issues) == 0 {
 		fmt.Println("✅ No performance issues found!")
 		return
 	}
 
+	sc := newSourceContext(config)
 	compactMode := os.Getenv("AIBSCLEANER_COMPACT") == "1"
 	if compactMode {
-		printCompactIssues(issues)
+		printCompactIssues(issues, sc)
 	} else {
-		printGroupedIssues(issues)
+		printGroupedIssues(issues, sc)
 	}
 
 	printSummary(issues)
@@ -477,9 +528,9 @@ type groupWithIssues struct {
 	issues []*models.Issue
 }
 
-func printGroupedIssues(issues []*models.Issue) {
+func printGroupedIssues(issues []*models.Issue, sc *sourceContext) {
 	grouped := groupIssuesByAnalyzer(issues)
-	output := buildGroupedOutput(grouped)
+	output := buildGroupedOutput(grouped, sc)
 	fmt.Print(output)
 }
 
@@ -499,15 +550,15 @@ func groupIssuesByAnalyzer(issues []*models.Issue) []groupWithIssues {
 		}
 	}
 
-	// Group issues by analyzer type
+	// Group issues by analyzer type; registered rules may name new groups
 	for _, issue := range issues {
 		groupName := getAnalyzerGroup(issue.Type)
-		for i := range grouped {
-			if grouped[i].group.Name == groupName {
-				grouped[i].issues = append(grouped[i].issues, issue)
-				break
-			}
+		i := slices.IndexFunc(grouped, func(g groupWithIssues) bool { return g.group.Name == groupName })
+		if i < 0 {
+			grouped = append(grouped, groupWithIssues{group: analyzerGroup{Name: groupName, Icon: groupIcon(groupName)}})
+			i = len(grouped) - 1
 		}
+		grouped[i].issues = append(grouped[i].issues, issue)
 	}
 
 	// Sort issues within each group: first by severity (HIGH, MEDIUM, LOW), then by PVE code
@@ -529,7 +580,7 @@ func groupIssuesByAnalyzer(issues []*models.Issue) []groupWithIssues {
 	return grouped
 }
 
-func buildGroupedOutput(grouped []groupWithIssues) string {
+func buildGroupedOutput(grouped []groupWithIssues, sc *sourceContext) string {
 	var sb strings.Builder
 	// Estimate size based on number of issues
 	totalIssues := 0
@@ -543,7 +594,7 @@ func buildGroupedOutput(grouped []groupWithIssues) string {
 			continue
 		}
 		addGroupHeader(&sb, &g)
-		addGroupIssues(&sb, g.issues)
+		addGroupIssues(&sb, g.issues, sc)
 		sb.WriteString("\n")
 	}
 	return sb.String()
@@ -559,7 +610,7 @@ func addGroupHeader(sb *strings.Builder, g *groupWithIssues) {
 	sb.WriteString(strings.Repeat("─", 50) + "\n")
 }
 
-func addGroupIssues(sb *strings.Builder, issues []*models.Issue) {
+func addGroupIssues(sb *strings.Builder, issues []*models.Issue, sc *sourceContext) {
 	for _, issue := range issues {
 		severityIcon := getSeverityIcon(issue.Severity)
 		sb.WriteString("\t")
@@ -581,10 +632,11 @@ func addGroupIssues(sb *strings.Builder, issues []*models.Issue) {
 			sb.WriteString(issue.Suggestion)
 			sb.WriteString("\n")
 		}
+		sc.render(sb, issue, "\t\t")
 	}
 }
 
-func printCompactIssues(issues []*models.Issue) {
+func printCompactIssues(issues []*models.Issue, sc *sourceContext) {
 	// Sort issues: first by severity (HIGH, MEDIUM, LOW), then by PVE code
 	sort.Slice(issues, func(i, j int) bool {
 		// First sort by severity: HIGH first, then MEDIUM, then LOW
@@ -610,41 +662,11 @@ func printCompactIssues(issues []*models.Issue) {
 				severityIcon, issue.Type, issue.Message, issue.Suggestion,
 			),
 		)
+		sc.render(&sb, issue, "    ")
 	}
 	fmt.Print(sb.String())
 }
 
-func analyzeFile(filename string, config *Config) []*models.Issue {
-	if config == nil {
-		return nil
-	}
-
-	// Try to load from cache first
-	if cachedIssues, found := loadCachedIssues(filename, cacheDB); found {
-		return cachedIssues
-	}
-
-	// Parse the Go file
-	fset, node, err := parseGoFile(filename)
-	if err != nil {
-		return nil
-	}
-
-	// Build enabled analyzers map from config
-	enabledAnalyzers := buildEnabledAnalyzers(config)
-
-	// Use centralized AnalyzeAll function with config
-	issues := analyzer.Analyze(filename, node, fset, enabledAnalyzers)
-
-	// Filter out issues that have ignore comments
-	allIssues := analyzer.FilterIssuesByComments(issues, fset, node)
-
-	// Save to cache
-	saveToCacheDB(filename, allIssues, cacheDB)
-
-	return allIssues
-}
-
 func getSeverityIcon(severity models.SeverityLevel) string {
 	switch severity {
 	case models.SeverityLevelHigh:
@@ -659,254 +681,57 @@ func getSeverityIcon(severity models.SeverityLevel) string {
 }
 
 type analyzerGroup struct {
-	Name  string
-	Icon  string
-	Types []models.IssueType
-}
-
+	Name string
+	Icon string
+}
+
+// groupIcons holds the terminal icons of the rule groups
+var groupIcons = map[string]string{
+	models.GroupAI:            "🤖",
+	models.GroupMemory:        "💾",
+	models.GroupConcurrency:   "🔄",
+	models.GroupPerformance:   "🔥",
+	models.GroupDefer:         "⏰",
+	models.GroupString:        "📝",
+	models.GroupReflection:    "🔍",
+	models.GroupTimeRegex:     "⏱️",
+	models.GroupNetwork:       "🌐",
+	models.GroupDatabase:      "🗄️",
+	models.GroupErrorHandling: "⚠️",
+	models.GroupCodeQuality:   "🎯",
+	models.GroupContextAPI:    "⚡",
+	models.GroupOptimization:  "💡",
+	models.GroupTestCoverage:  "🧪",
+	models.GroupSecurity:      "🔒",
+	models.GroupDependencies:  "📦",
+	models.GroupOther:         "📌",
+}
+
+// pluginGroupIcon marks output groups named by registered rules
+const pluginGroupIcon = "🧩"
+
+// getAnalyzerGroups returns the groups of the built-in rules in output order
 func getAnalyzerGroups() []analyzerGroup {
-	groups := []analyzerGroup{
-		getAIGroup(),
-		getMemoryGroup(),
-		getConcurrencyGroup(),
-		getPerformanceGroup(),
-		getDeferGroup(),
-		getStringGroup(),
-		getReflectionGroup(),
-		getTimeRegexGroup(),
-		getNetworkGroup(),
-		getDatabaseGroup(),
-		getErrorGroup(),
-		getQualityGroup(),
-		getContextGroup(),
-		getOptimizationGroup(),
-		getTestGroup(),
-		getPrivacyGroup(),
-		getDependenciesGroup(),
-		getOtherGroup(),
+	names := models.RuleGroups()
+	groups := make([]analyzerGroup, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, analyzerGroup{Name: name, Icon: groupIcon(name)})
 	}
 	return groups
 }
 
-func getMemoryGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Memory & GC",
-		Icon: "💾",
-		Types: []models.IssueType{
-			models.IssueMemoryLeak, models.IssueGlobalVar, models.IssueLargeAllocation, models.IssueHighGCPressure,
-			models.IssueFrequentAllocation, models.IssueLargeHeapAlloc, models.IssuePointerHeavyStruct,
-			models.IssueSliceCapacity, models.IssueSliceCopy, models.IssueSliceAppend, models.IssueSliceRangeCopy,
-			models.IssueMapCapacity, models.IssueMapClear, models.IssueInterfaceAllocation, models.IssueEmptyInterface,
-			models.IssueStructLayoutUnoptimized, models.IssueStructLargePadding, models.IssueStructFieldAlignment,
-			models.IssueCacheFalseSharing, models.IssueCacheLineWaste, models.IssueCacheLineAlignment,
-			models.IssueOversizedType, models.IssueUnspecificIntType, models.IssueSoAPattern,
-			models.IssueNestedRangeCache, models.IssueMapRangeCache,
-		},
-	}
-}
-
-func getConcurrencyGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Concurrency & Race Conditions",
-		Icon: "🔄",
-		Types: []models.IssueType{
-			models.IssueRaceCondition, models.IssueRaceConditionGlobal, models.IssueUnsyncMapAccess, models.IssueRaceClosure,
-			models.IssueGoroutineLeak, models.IssueUnbufferedChannel, models.IssueGoroutineOverhead, models.IssueSyncMutexValue,
-			models.IssueWaitgroupMisuse, models.IssueRaceInDefer, models.IssueAtomicMisuse, models.IssueGoroutinePerRequest,
-			models.IssueNoWorkerPool, models.IssueUnbufferedSignalChan, models.IssueSelectDefault, models.IssueChannelSize,
-			models.IssueRangeOverChannel,
-		},
-	}
-}
-
-func getPerformanceGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Performance Hotspots",
-		Icon: "🔥",
-		Types: []models.IssueType{
-			models.IssueAllocInLoop, models.IssueNestedLoop, models.IssueAppendInLoop,
-			models.IssueDeferInLoop, models.IssueRegexInLoop, models.IssueTimeInLoop, models.IssueSQLInLoop,
-			models.IssueDNSInLoop,
-			models.IssueReflectionInLoop, models.IssueCPUIntensiveLoop, models.IssueUnnecessaryCopy,
-			models.IssueBoundsCheckElimination,
-			models.IssueInefficientAlgorithm, models.IssueCacheUnfriendly,
-		},
-	}
-}
-
-func getDeferGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Defer Optimization",
-		Icon: "⏰",
-		Types: []models.IssueType{
-			models.IssueDeferInShortFunc, models.IssueDeferOverhead, models.IssueUnnecessaryDefer, models.IssueDeferAtEnd,
-			models.IssueMultipleDefers, models.IssueDeferInHotPath, models.IssueDeferLargeCapture,
-			models.IssueUnnecessaryMutexDefer,
-			models.IssueMissingDeferUnlock, models.IssueMissingDeferClose,
-		},
-	}
-}
-
-func getStringGroup() analyzerGroup {
-	return analyzerGroup{
-		Name:  "String Operations",
-		Icon:  "📝",
-		Types: []models.IssueType{models.IssueStringConcat, models.IssueStringBuilder},
-	}
-}
-
-func getReflectionGroup() analyzerGroup {
-	return analyzerGroup{
-		Name:  "Reflection & Interfaces",
-		Icon:  "🔍",
-		Types: []models.IssueType{models.IssueReflection, models.IssueInterfacePollution},
-	}
-}
-
-func getTimeRegexGroup() analyzerGroup {
-	return analyzerGroup{
-		Name:  "Time & Regex",
-		Icon:  "⏱️",
-		Types: []models.IssueType{models.IssueTimeAfterLeak, models.IssueTimeFormat, models.IssueRegexCompile},
-	}
-}
-
-func getNetworkGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Network & HTTP",
-		Icon: "🌐",
-		Types: []models.IssueType{
-			models.IssueHTTPNoTimeout, models.IssueHTTPNoClose, models.IssueHTTPDefaultClient, models.IssueHTTPNoContext,
-			models.IssueKeepaliveMissing, models.IssueConnectionPool, models.IssueNoReuseConnection,
-		},
-	}
-}
-
-func getDatabaseGroup() analyzerGroup {
-	return analyzerGroup{
-		Name:  "Database",
-		Icon:  "🗄️",
-		Types: []models.IssueType{models.IssueNoPreparedStmt, models.IssueMissingDBClose},
-	}
-}
-
-func getErrorGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Error Handling",
-		Icon: "⚠️",
-		Types: []models.IssueType{
-			models.IssueErrorIgnored, models.IssueErrorCheckMissing, models.IssuePanicRecover, models.IssueErrorStringFormat,
-			models.IssuePanicRisk, models.IssuePanicInLibrary,
-		},
-	}
-}
-
-func getQualityGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Code Quality",
-		Icon: "🎯",
-		Types: []models.IssueType{
-			models.IssueHighComplexityO3, models.IssueHighComplexityO2,
-			models.IssuePointerToSlice, models.IssueUselessCondition, models.IssueEmptyElse,
-			models.IssueSleepInsteadOfSync, models.IssueConsoleLogDebugging,
-			models.IssueHardcodedConfig, models.IssuePanicInLibrary, models.IssueGlobalVariable,
-		},
-	}
-}
-
-func getContextGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Context & API",
-		Icon: "⚡",
-		Types: []models.IssueType{
-			models.IssueContextBackground, models.IssueContextValue, models.IssueMissingContextCancel, models.IssueContextLeak,
-			models.IssueContextInStruct, models.IssueContextNotFirst, models.IssueSyncPoolMisuse, models.IssueContextMisuse,
-			models.IssueWGMisuse,
-		},
-	}
-}
-
-func getOptimizationGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Optimization Opportunities",
-		Icon: "💡",
-		Types: []models.IssueType{
-			models.IssueSyncPoolOpportunity, models.IssueSyncPoolPutMissing, models.IssueSyncPoolTypeAssert,
-		},
-	}
-}
-
-func getTestGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Test Coverage",
-		Icon: "🧪",
-		Types: []models.IssueType{
-			models.IssueMissingTest, models.IssueMissingExample, models.IssueMissingBenchmark, models.IssueUntestedExport,
-			models.IssueUntestedType, models.IssueUntestedError, models.IssueUntestedConcurrency,
-			models.IssueUntestedIOFunction,
-		},
-	}
-}
-
-func getPrivacyGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Privacy & Security",
-		Icon: "🔒",
-		Types: []models.IssueType{
-			models.IssuePrivacyHardcodedSecret, models.IssuePrivacyAWSKey, models.IssuePrivacyJWTToken,
-			models.IssuePrivacyEmailPII, models.IssuePrivacySSNPII, models.IssuePrivacyCreditCardPII,
-			models.IssuePrivacyLoggingSensitive, models.IssuePrivacyPrintingSensitive, models.IssuePrivacyExposedField,
-			models.IssuePrivacyUnencryptedDBWrite, models.IssuePrivacyDirectInputToDB,
-		},
-	}
-}
-
-func getDependenciesGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "Dependencies",
-		Icon: "📦",
-		Types: []models.IssueType{
-			models.IssueDependencyDeprecated, models.IssueDependencyVulnerable, models.IssueDependencyOutdated,
-			models.IssueDependencyCGO, models.IssueDependencyUnsafe, models.IssueDependencyInternal,
-			models.IssueDependencyIndirect,
-			models.IssueDependencyLocalReplace, models.IssueDependencyNoChecksum, models.IssueDependencyEmptyChecksum,
-			models.IssueDependencyVersionConflict,
-		},
-	}
-}
-
-func getOtherGroup() analyzerGroup {
-	return analyzerGroup{
-		Name:  "Other",
-		Icon:  "📌",
-		Types: []models.IssueType{}, // Catches any uncategorized issues
-	}
-}
-
-func getAIGroup() analyzerGroup {
-	return analyzerGroup{
-		Name: "AI Bullshit Detection",
-		Icon: "🤖",
-		Types: []models.IssueType{
-			models.IssueAIBullshitConcurrency, models.IssueAIReflectionOverkill, models.IssueAIPatternAbuse,
-			models.IssueAIEnterpriseHelloWorld, models.IssueAICaptainObvious, models.IssueAIOverengineeredSimple,
-			models.IssueAIGeneratedComment, models.IssueAIUnnecessaryComplexity, models.IssueAIVariable,
-			models.IssueAIErrorHandling,
-			models.IssueAIStructure, models.IssueAIRepetition, models.IssueAIFactorySimple, models.IssueAIRedundantElse,
-		},
+func groupIcon(name string) string {
+	if icon, ok := groupIcons[name]; ok {
+		return icon
 	}
+	return pluginGroupIcon
 }
 
 func getAnalyzerGroup(issueType models.IssueType) string {
-	groups := getAnalyzerGroups()
-	for _, group := range groups {
-		for _, t := range group.Types {
-			if t == issueType {
-				return group.Name
-			}
-		}
+	if rule, ok := issueType.Rule(); ok && rule.Group != "" {
+		return rule.Group
 	}
-	return "Other" // Default group for uncategorized issues
+	return models.GroupOther
 }
 
 func countBySeverity(issues []*models.Issue) (high, medium, low int) {
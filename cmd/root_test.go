@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func TestAnalyzeTargetReadsStdinSources(t *testing.T) {
+	// The file does not exist on disk; only the stdin source is analyzed
+	filename := filepath.Join(t.TempDir(), "buffer.go")
+	stdinSources = map[string][]byte{
+		filename: []byte("package main\n\nfunc main() {\n\tfor i := 0; i < 3; i++ {\n\t\tdefer println(i)\n\t}\n}\n"),
+	}
+	t.Cleanup(func() { stdinSources = nil })
+
+	run, err := analyzeTarget(context.Background(), filename, DefaultConfig(), nil, true)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if len(run.Files) != 1 || run.Files[0] != filename {
+		t.Fatalf("expected the stdin source to be the analyzed file, got %v", run.Files)
+	}
+
+	found := false
+	for _, issue := range run.Issues {
+		if issue.Type == models.IssueDeferInLoop && issue.Position.Filename == filename {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected DeferInLoop in the stdin source, got %+v", run.Issues)
+	}
+}
+
+func TestAnalyzerListShowsRegisteredAnalyzers(t *testing.T) {
+	list := analyzerList()
+	for _, reg := range analyzer.Registered() {
+		if !strings.Contains(list, "• "+reg.Name+" ") {
+			t.Errorf("list-analyzers misses %s:\n%s", reg.Name, list)
+		}
+	}
+	if got, want := strings.Count(list, "• "), len(analyzer.Registered())+1; got != want {
+		t.Errorf("list-analyzers shows %d analyzers, want %d", got, want)
+	}
+}
+
+func TestRuleGroupsHaveIcons(t *testing.T) {
+	groups := getAnalyzerGroups()
+	if len(groups) != len(groupIcons) {
+		t.Errorf("%d groups but %d icons", len(groups), len(groupIcons))
+	}
+	for _, group := range groups {
+		if group.Icon == pluginGroupIcon {
+			t.Errorf("group %q has no icon", group.Name)
+		}
+	}
+	for _, rule := range models.Rules() {
+		if name := getAnalyzerGroup(rule.Type); groupIcon(name) == pluginGroupIcon && rule.Analyzer != models.AnalyzerPlugin {
+			t.Errorf("%s is in unknown group %q", rule.ID, name)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
+)
+
+// ANSI escape sequences used for terminal output
+const (
+	ansiReset  = "\x1b[0m"
+	ansiDim    = "\x1b[2m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// sourceContext renders the source lines around an issue and underlines the reported range
+type sourceContext struct {
+	lines int
+	color bool
+	files map[string][]string
+}
+
+// newSourceContext returns nil when the config does not ask for code context
+func newSourceContext(config *Config) *sourceContext {
+	if config == nil || !config.Output.ShowContext {
+		return nil
+	}
+
+	lines := config.Output.ContextLines
+	if lines <= 0 {
+		lines = aibscleaner.DefaultContextLines
+	}
+
+	return &sourceContext{
+		lines: lines,
+		color: colorEnabled(),
+		files: make(map[string][]string),
+	}
+}
+
+// colorEnabled reports whether stdout is a terminal and NO_COLOR is not set
+func colorEnabled() bool {
+	return os.Getenv("NO_COLOR") == "" && stdoutIsTerminal()
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func (sc *sourceContext) paint(code, s string) string {
+	if !sc.color {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+func severityColor(severity models.SeverityLevel) string {
+	switch severity {
+	case models.SeverityLevelHigh:
+		return ansiRed
+	case models.SeverityLevelMedium:
+		return ansiYellow
+	default:
+		return ansiCyan
+	}
+}
+
+func (sc *sourceContext) fileLines(filename string) []string {
+	if lines, ok := sc.files[filename]; ok {
+		return lines
+	}
+
+	var lines []string
+	data, ok := stdinSources[filename]
+	if !ok {
+		data, _ = os.ReadFile(filename)
+	}
+	if data != nil {
+		lines = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	}
+	sc.files[filename] = lines
+	return lines
+}
+
+// render writes the context block for issue to sb, each line prefixed with indent
+func (sc *sourceContext) render(sb *strings.Builder, issue *models.Issue, indent string) {
+	if sc == nil {
+		return
+	}
+
+	span := spanOf(issue)
+	line := span.line
+	if line <= 0 {
+		return
+	}
+
+	first, last := line, line
+	lines := sc.fileLines(issue.Position.Filename)
+	if line <= len(lines) {
+		first = max(1, line-sc.lines)
+		last = min(len(lines), line+sc.lines)
+	} else if issue.Code != "" {
+		// The file is gone or changed; fall back to the span captured during
+		// analysis, placed at the columns it was taken from
+		code := strings.Split(issue.Code, "\n")
+		lines = make([]string, line-1, line-1+len(code))
+		lines = append(lines, strings.Repeat(" ", max(0, span.column-1))+code[0])
+		lines = append(lines, code[1:]...)
+		last = len(lines)
+	} else {
+		return
+	}
+
+	width := len(strconv.Itoa(last))
+	color := ansiBold + severityColor(issue.Severity)
+	for n := first; n <= last; n++ {
+		text := strings.TrimRight(lines[n-1], " \t\r")
+		gutter := padLeft(strconv.Itoa(n), width) + " │ "
+		sb.WriteString(indent)
+		if n == line {
+			sb.WriteString(sc.paint(ansiBold, gutter))
+		} else {
+			sb.WriteString(sc.paint(ansiDim, gutter))
+		}
+		if from, to, ok := span.columns(n, text); ok && sc.color {
+			text = text[:from-1] + sc.paint(color, text[from-1:to-1]) + text[to-1:]
+		}
+		sb.WriteString(text)
+		sb.WriteString("\n")
+
+		if n == line {
+			text = strings.TrimRight(lines[n-1], " \t\r")
+			sb.WriteString(indent)
+			sb.WriteString(strings.Repeat(" ", width))
+			sb.WriteString(sc.paint(ansiDim, " │ "))
+			sb.WriteString(caretPadding(text, span.column))
+			sb.WriteString(sc.paint(color, underline(text, issue)))
+			sb.WriteString("\n")
+		}
+	}
+}
+
+// issueSpan is the source range an issue reports, with 1-based lines and
+// columns and an exclusive end column
+type issueSpan struct {
+	line, column       int
+	endLine, endColumn int
+}
+
+// spanOf returns the range of issue, preferring Position over the flat fields
+func spanOf(issue *models.Issue) issueSpan {
+	span := issueSpan{
+		line:      issue.Position.Line,
+		column:    issue.Position.Column,
+		endLine:   issue.EndLine,
+		endColumn: issue.EndColumn,
+	}
+	if span.line == 0 {
+		span.line = issue.Line
+	}
+	if span.column == 0 {
+		span.column = issue.Column
+	}
+	return span
+}
+
+// columns returns the part of line n, whose text is text, covered by the span.
+// Lines after the first start at their indentation. It reports false when the
+// span has no end or does not cover line n.
+func (s issueSpan) columns(n int, text string) (from, to int, ok bool) {
+	if s.endLine < s.line || n < s.line || n > s.endLine {
+		return 0, 0, false
+	}
+	from = len(text) - len(strings.TrimLeft(text, " \t")) + 1
+	to = len(text) + 1
+	if n == s.line {
+		from = max(1, s.column)
+	}
+	if n == s.endLine {
+		to = min(to, s.endColumn)
+	}
+	return from, to, from < to
+}
+
+// underline returns a caret followed by tildes covering the issue range on its first line
+func underline(text string, issue *models.Issue) string {
+	span := spanOf(issue)
+	from, to, ok := span.columns(span.line, text)
+	if !ok || to <= from+1 {
+		return "^"
+	}
+	return "^" + strings.Repeat("~", to-from-1)
+}
+
+// caretPadding reproduces the whitespace before column so the caret lines up under tabs
+func caretPadding(text string, column int) string {
+	if column <= 1 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for i := 0; i < column-1 && i < len(text); i++ {
+		if text[i] == '\t' {
+			sb.WriteByte('\t')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	return sb.String()
+}
+
+func padLeft(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-len(s)) + s
+}
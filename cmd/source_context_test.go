@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func TestSourceContextRender(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	src := "package main\n\nfunc main() {\n\tfor i := 0; i < 3; i++ {\n\t\tdefer println(i)\n\t}\n}\n"
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	config := DefaultConfig()
+	config.Output.ShowContext = true
+	config.Output.ContextLines = 1
+	sc := newSourceContext(config)
+	sc.color = false
+
+	issue := &models.Issue{
+		Type:      models.IssueDeferInLoop,
+		Severity:  models.SeverityLevelHigh,
+		Position:  token.Position{Filename: path, Line: 5, Column: 3},
+		EndLine:   5,
+		EndColumn: 19,
+	}
+
+	var sb strings.Builder
+	sc.render(&sb, issue, "")
+	want := "4 │ \tfor i := 0; i < 3; i++ {\n" +
+		"5 │ \t\tdefer println(i)\n" +
+		"  │ \t\t^~~~~~~~~~~~~~~~\n" +
+		"6 │ \t}\n"
+	if sb.String() != want {
+		t.Fatalf("unexpected context:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestSourceContextFallsBackToIssueCode(t *testing.T) {
+	sc := &sourceContext{lines: 2, files: make(map[string][]string)}
+	issue := &models.Issue{
+		Code:      "fmt.Sprintf(\"%d\",\n\tn)",
+		Position:  token.Position{Filename: filepath.Join(t.TempDir(), "missing.go"), Line: 3, Column: 6},
+		EndLine:   4,
+		EndColumn: 4,
+	}
+
+	var sb strings.Builder
+	sc.render(&sb, issue, "")
+	want := "3 │      fmt.Sprintf(\"%d\",\n" +
+		"  │      ^~~~~~~~~~~~~~~~~\n" +
+		"4 │ \tn)\n"
+	if sb.String() != want {
+		t.Fatalf("unexpected context:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestSourceContextHighlightsSpan(t *testing.T) {
+	sc := &sourceContext{lines: 0, color: true, files: map[string][]string{
+		"main.go": {"package main", "func main() {", "\tx := f(a,", "\t\tb)", "}"},
+	}}
+	issue := &models.Issue{
+		Severity:  models.SeverityLevelHigh,
+		Position:  token.Position{Filename: "main.go", Line: 3, Column: 7},
+		EndLine:   4,
+		EndColumn: 5,
+	}
+
+	var sb strings.Builder
+	sc.render(&sb, issue, "")
+	hl := ansiBold + ansiRed
+	want := ansiBold + "3 │ " + ansiReset + "\tx := " + hl + "f(a," + ansiReset + "\n" +
+		" " + ansiDim + " │ " + ansiReset + "\t     " + hl + "^~~~" + ansiReset + "\n"
+	if sb.String() != want {
+		t.Fatalf("unexpected context:\n%q\nwant:\n%q", sb.String(), want)
+	}
+
+	sc.lines = 1
+	sb.Reset()
+	sc.render(&sb, issue, "")
+	if !strings.Contains(sb.String(), "\t\t"+hl+"b)"+ansiReset+"\n") {
+		t.Fatalf("second line of the span is not highlighted:\n%q", sb.String())
+	}
+}
+
+func TestUnderline(t *testing.T) {
+	text := "\tfoo(bar)"
+	tests := []struct {
+		name  string
+		issue *models.Issue
+		want  string
+	}{
+		{"no range", &models.Issue{Position: token.Position{Line: 1, Column: 2}}, "^"},
+		{"single line", &models.Issue{Position: token.Position{Line: 1, Column: 2}, EndLine: 1, EndColumn: 10}, "^~~~~~~~"},
+		{"multi line", &models.Issue{Position: token.Position{Line: 1, Column: 2}, EndLine: 3, EndColumn: 2}, "^~~~~~~~"},
+	}
+	for _, tt := range tests {
+		if got := underline(text, tt.issue); got != tt.want {
+			t.Fatalf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewSourceContextDisabled(t *testing.T) {
+	if sc := newSourceContext(DefaultConfig()); sc != nil {
+		t.Fatalf("expected no source context by default")
+	}
+
+	// A nil context renders nothing
+	var sc *sourceContext
+	var sb strings.Builder
+	sc.render(&sb, &models.Issue{Line: 1}, "")
+	if sb.Len() != 0 {
+		t.Fatalf("expected empty output, got %q", sb.String())
+	}
+}
+
+func TestColorEnabledRespectsNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled() {
+		t.Fatalf("expected color to be disabled when NO_COLOR is set")
+	}
+}
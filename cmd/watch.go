@@ -0,0 +1,429 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
+)
+
+var watchInterval time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [path]",
+	Short: "Re-analyze files as they change",
+	Long: `Watches the tree and re-analyzes changed files together with the other files
+of their package and the packages importing it, directly or through other
+packages; the results of unaffected files come from the cache. Only issues that appeared or
+were resolved since the previous run are printed, followed by a summary line.
+
+Changes are found by periodically comparing file sizes and modification times,
+so no file system notification support is needed.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		target := "."
+		if len(args) > 0 {
+			target = args[0]
+		}
+		if _, err := os.Stat(target); err != nil {
+			slog.Error("Path does not exist", "path", target)
+			os.Exit(1)
+		}
+
+		config, err := LoadConfig(configPath)
+		if err != nil {
+			slog.Error("Failed to load config", "error", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		err = newWatcher(target, config).run(ctx, watchInterval, os.Stdout)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			slog.Error("Watch failed", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// fileStamp identifies a version of a file on disk
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s fileStamp) equal(other fileStamp) bool {
+	return s.size == other.size && s.modTime.Equal(other.modTime)
+}
+
+// watcher keeps the results of the last run per file and re-analyzes only the
+// files affected by a change
+type watcher struct {
+	runner     *aibscleaner.Runner
+	cache      *watchCache
+	target     string
+	root       string // module root; import paths are resolved against it
+	modulePath string
+	live       bool // the summary line is rewritten in place
+
+	stamps  map[string]fileStamp
+	imports map[string][]string
+	issues  map[string][]*models.Issue
+}
+
+func newWatcher(target string, config *Config) *watcher {
+	// Dependency checks look at go.mod rather than the edited files
+	cfg := *config
+	cfg.Analyzers.Dependency.Enabled = false
+
+	root := getProjectRoot(target)
+	fileCache := &watchCache{entries: make(map[string][]*models.Issue)}
+	opts := aibscleaner.Options{Concurrency: concurrency, FileTimeout: fileTimeout, Cache: fileCache}
+	return &watcher{
+		runner:     aibscleaner.NewRunner(&cfg, opts),
+		cache:      fileCache,
+		target:     target,
+		root:       root,
+		modulePath: readModulePath(root),
+		live:       stdoutIsTerminal(),
+		stamps:     make(map[string]fileStamp),
+		imports:    make(map[string][]string),
+		issues:     make(map[string][]*models.Issue),
+	}
+}
+
+func readModulePath(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	return modfile.ModulePath(data)
+}
+
+// run analyzes the tree, then re-analyzes it on every change until ctx is done
+func (w *watcher) run(ctx context.Context, interval time.Duration, out io.Writer) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for first := true; ; first = false {
+		changed, removed, err := w.scan(ctx)
+		if err != nil {
+			return err
+		}
+		if first || len(changed) > 0 || len(removed) > 0 {
+			added, resolved, errs, err := w.update(ctx, w.affected(changed, removed), removed)
+			if err != nil {
+				return err
+			}
+			// The first run sets the baseline; only later changes are listed
+			if first {
+				added, resolved = nil, nil
+			}
+			w.report(out, added, resolved, errs)
+		}
+
+		select {
+		case <-ctx.Done():
+			if w.live {
+				_, _ = fmt.Fprintln(out)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan returns the files added or changed since the previous scan and the
+// files that are gone
+func (w *watcher) scan(ctx context.Context) (changed, removed []string, err error) {
+	files, err := w.runner.CollectFiles(ctx, w.target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]bool, len(files))
+	for _, filename := range files {
+		seen[filename] = true
+		info, err := os.Stat(filename)
+		if err != nil {
+			continue // Removed since the walk; the next scan notices
+		}
+		stamp := fileStamp{modTime: info.ModTime(), size: info.Size()}
+		if old, ok := w.stamps[filename]; !ok || !old.equal(stamp) {
+			w.stamps[filename] = stamp
+			changed = append(changed, filename)
+		}
+	}
+	for filename := range w.stamps {
+		if !seen[filename] {
+			delete(w.stamps, filename)
+			removed = append(removed, filename)
+		}
+	}
+	sort.Strings(removed)
+	return changed, removed, nil
+}
+
+// affected returns the files to re-analyze after a change: the changed files,
+// the rest of their packages, whose type information depends on them, and the
+// files of every package importing those packages directly or transitively.
+// The other files keep the results of their last analysis.
+func (w *watcher) affected(changed, removed []string) []string {
+	for _, filename := range changed {
+		w.imports[filename] = readImports(filename)
+	}
+	for _, filename := range removed {
+		delete(w.imports, filename)
+	}
+
+	// importers maps an import path to the directories of the files importing it
+	importers := make(map[string][]string)
+	for filename, imports := range w.imports {
+		for _, path := range imports {
+			importers[path] = append(importers[path], filepath.Dir(filename))
+		}
+	}
+
+	dirs := make(map[string]bool)
+	var queue []string
+	visit := func(dir string) {
+		if !dirs[dir] {
+			dirs[dir] = true
+			queue = append(queue, dir)
+		}
+	}
+	for _, filename := range slices.Concat(changed, removed) {
+		visit(filepath.Dir(filename))
+	}
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+		if path := w.importPath(dir); path != "" {
+			for _, importer := range importers[path] {
+				visit(importer)
+			}
+		}
+	}
+
+	var files []string
+	for filename := range w.imports {
+		if dirs[filepath.Dir(filename)] {
+			files = append(files, filename)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// importPath returns the import path of the package in dir, or "" when dir is
+// outside the module
+func (w *watcher) importPath(dir string) string {
+	if w.modulePath == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(w.root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	if rel == "." {
+		return w.modulePath
+	}
+	return w.modulePath + "/" + filepath.ToSlash(rel)
+}
+
+func readImports(filename string) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+	imports := make([]string, 0, len(file.Imports))
+	for _, spec := range file.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+			imports = append(imports, path)
+		}
+	}
+	return imports
+}
+
+// update drops the cached results of stale, the files affected by a change,
+// forgets the removed files and analyzes the watched files again, so that only
+// the stale ones are analyzed and the others come from the cache. It returns
+// the issues that appeared and disappeared. A file that does not parse keeps
+// its previous issues until it does again.
+func (w *watcher) update(
+	ctx context.Context, stale, removed []string,
+) (added, resolved []*models.Issue, errs []error, err error) {
+	for _, filename := range removed {
+		resolved = append(resolved, w.issues[filename]...)
+		delete(w.issues, filename)
+	}
+
+	files := make([]string, 0, len(w.stamps))
+	for filename := range w.stamps {
+		files = append(files, filename)
+	}
+	sort.Strings(files)
+	if len(files) == 0 {
+		return added, resolved, nil, nil
+	}
+
+	// Unchanged files may depend on changed ones, so their results are stale
+	// in both the watch cache and the analyzer's own
+	w.cache.invalidate(slices.Concat(stale, removed)...)
+	analyzer.InvalidateCache(slices.Concat(stale, removed)...)
+	result, err := w.runner.AnalyzePaths(ctx, files...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	failed := make(map[string]bool, len(result.Errors))
+	for _, err := range result.Errors {
+		var fileErr *aibscleaner.FileError
+		if errors.As(err, &fileErr) {
+			failed[fileErr.Filename] = true
+		}
+	}
+	byFile := make(map[string][]*models.Issue, len(files))
+	for _, issue := range result.Issues {
+		byFile[issue.Position.Filename] = append(byFile[issue.Position.Filename], issue)
+	}
+
+	for _, filename := range files {
+		if failed[filename] {
+			continue
+		}
+		appeared, disappeared := diffIssues(w.issues[filename], byFile[filename])
+		added = append(added, appeared...)
+		resolved = append(resolved, disappeared...)
+		w.issues[filename] = byFile[filename]
+	}
+	return added, resolved, result.Errors, nil
+}
+
+// watchCache is the Runner cache of a watcher. It holds the issues of the last
+// analysis of each file until the watcher invalidates them.
+type watchCache struct {
+	mu      sync.Mutex
+	entries map[string][]*models.Issue
+	hits    int // loads served from the cache
+}
+
+func (c *watchCache) Load(filename string) ([]*models.Issue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	issues, ok := c.entries[filename]
+	if ok {
+		c.hits++
+	}
+	// The Runner filters the returned slice in place
+	return slices.Clone(issues), ok
+}
+
+func (c *watchCache) Store(filename string, issues []*models.Issue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[filename] = slices.Clone(issues)
+}
+
+func (c *watchCache) IgnoresRule(string, string) bool {
+	return false
+}
+
+// invalidate drops the entries of filenames
+func (c *watchCache) invalidate(filenames ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, filename := range filenames {
+		delete(c.entries, filename)
+	}
+}
+
+// diffIssues matches issues by rule and message, since edits move them around,
+// and returns the unmatched ones of after and of before
+func diffIssues(before, after []*models.Issue) (added, resolved []*models.Issue) {
+	key := func(issue *models.Issue) string {
+		return issue.Type.String() + "\x00" + issue.Message
+	}
+
+	counts := make(map[string]int, len(before))
+	for _, issue := range before {
+		counts[key(issue)]++
+	}
+	for _, issue := range after {
+		if k := key(issue); counts[k] > 0 {
+			counts[k]--
+		} else {
+			added = append(added, issue)
+		}
+	}
+	for _, issue := range before {
+		if k := key(issue); counts[k] > 0 {
+			counts[k]--
+			resolved = append(resolved, issue)
+		}
+	}
+	return added, resolved
+}
+
+// report prints the issue changes of a run and the summary line
+func (w *watcher) report(out io.Writer, added, resolved []*models.Issue, errs []error) {
+	var sb strings.Builder
+	if w.live {
+		sb.WriteString("\r\x1b[K")
+	}
+	for _, err := range errs {
+		sb.WriteString(fmt.Sprintf("! %v\n", err))
+	}
+	for _, issue := range resolved {
+		sb.WriteString("- " + formatWatchIssue(issue) + "\n")
+	}
+	for _, issue := range added {
+		sb.WriteString("+ " + formatWatchIssue(issue) + "\n")
+	}
+
+	var all []*models.Issue
+	for _, issues := range w.issues {
+		all = append(all, issues...)
+	}
+	high, medium, low := countBySeverity(all)
+	sb.WriteString(fmt.Sprintf(
+		"[%s] %d issues (%d high, %d medium, %d low) in %d files, watching for changes",
+		time.Now().Format("15:04:05"), len(all), high, medium, low, len(w.stamps),
+	))
+	if !w.live {
+		sb.WriteString("\n")
+	}
+	_, _ = io.WriteString(out, sb.String())
+}
+
+func formatWatchIssue(issue *models.Issue) string {
+	return fmt.Sprintf(
+		"%s:%d:%d: %s [%s] %s",
+		issue.Position.Filename, issue.Position.Line, issue.Position.Column,
+		getSeverityIcon(issue.Severity), issue.Type, issue.Message,
+	)
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const watchLoopSource = "package main\n\nfunc main() {\n\tfor i := 0; i < 3; i++ {\n\t\tdefer println(i)\n\t}\n}\n"
+
+func writeWatchFile(t *testing.T, path, src string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// step runs one watch iteration and returns the issues that changed
+func step(t *testing.T, w *watcher) (added, resolved []*models.Issue) {
+	t.Helper()
+	changed, removed, err := w.scan(context.Background())
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	added, resolved, _, err = w.update(context.Background(), w.affected(changed, removed), removed)
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	return added, resolved
+}
+
+func countType(issues []*models.Issue, issueType models.IssueType) int {
+	n := 0
+	for _, issue := range issues {
+		if issue.Type == issueType {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWatcherReportsOnlyChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeWatchFile(t, filepath.Join(dir, "go.mod"), "module example.com/demo\n\ngo 1.21\n")
+	mainFile := filepath.Join(dir, "main.go")
+	writeWatchFile(t, mainFile, watchLoopSource)
+
+	w := newWatcher(dir, DefaultConfig())
+	added, _ := step(t, w)
+	loopIssues := countType(added, models.IssueDeferInLoop)
+	if loopIssues == 0 {
+		t.Fatalf("expected the baseline to contain DeferInLoop, got %+v", added)
+	}
+
+	if added, resolved := step(t, w); len(added) != 0 || len(resolved) != 0 {
+		t.Fatalf("unchanged tree should report nothing, got %+v / %+v", added, resolved)
+	}
+
+	// Moving the issue down a line is not a change
+	writeWatchFile(t, mainFile, "package main\n\n"+watchLoopSource[len("package main\n"):])
+	added, resolved := step(t, w)
+	if countType(added, models.IssueDeferInLoop) != 0 || countType(resolved, models.IssueDeferInLoop) != 0 {
+		t.Fatalf("moved issue should not be reported, got %+v / %+v", added, resolved)
+	}
+
+	// A file that does not parse keeps its issues
+	writeWatchFile(t, mainFile, "package main\n\nfunc main() {\n")
+	if _, resolved := step(t, w); len(resolved) != 0 {
+		t.Fatalf("syntax errors should not resolve issues, got %+v", resolved)
+	}
+
+	writeWatchFile(t, mainFile, "package main\n\nfunc main() {}\n")
+	if _, resolved := step(t, w); countType(resolved, models.IssueDeferInLoop) != loopIssues {
+		t.Fatalf("expected DeferInLoop to be resolved, got %+v", resolved)
+	}
+
+	otherFile := filepath.Join(dir, "other.go")
+	writeWatchFile(t, otherFile, "package main\n\nfunc other() {\n\tfor {\n\t\tdefer println()\n\t}\n}\n")
+	added, _ = step(t, w)
+	if countType(added, models.IssueDeferInLoop) != loopIssues {
+		t.Fatalf("expected a new DeferInLoop in other.go, got %+v", added)
+	}
+	for _, issue := range added {
+		if issue.Position.Filename != otherFile {
+			t.Fatalf("unchanged main.go should not report new issues, got %+v", issue)
+		}
+	}
+
+	if err := os.Remove(otherFile); err != nil {
+		t.Fatal(err)
+	}
+	if _, resolved := step(t, w); countType(resolved, models.IssueDeferInLoop) != loopIssues {
+		t.Fatalf("removing a file should resolve its issues, got %+v", resolved)
+	}
+}
+
+func TestWatcherAffectedIncludesTransitiveImporters(t *testing.T) {
+	dir := t.TempDir()
+	writeWatchFile(t, filepath.Join(dir, "go.mod"), "module example.com/demo\n\ngo 1.21\n")
+	lib := filepath.Join(dir, "lib", "lib.go")
+	libOther := filepath.Join(dir, "lib", "util.go")
+	app := filepath.Join(dir, "app", "app.go")
+	cli := filepath.Join(dir, "cli", "main.go")
+	unrelated := filepath.Join(dir, "tools", "tools.go")
+	writeWatchFile(t, lib, "package lib\n")
+	writeWatchFile(t, libOther, "package lib\n")
+	writeWatchFile(t, app, "package app\n\nimport _ \"example.com/demo/lib\"\n")
+	writeWatchFile(t, cli, "package main\n\nimport _ \"example.com/demo/app\"\n")
+	writeWatchFile(t, unrelated, "package tools\n\nimport _ \"strings\"\n")
+
+	w := newWatcher(dir, DefaultConfig())
+	w.affected([]string{lib, libOther, app, cli, unrelated}, nil)
+
+	// cli imports lib only through app
+	got := w.affected([]string{lib}, nil)
+	want := []string{app, cli, lib, libOther}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got = w.affected([]string{app}, nil)
+	want = []string{app, cli}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	got = w.affected(nil, []string{unrelated})
+	if len(got) != 0 {
+		t.Fatalf("removing the only file of a package affects nothing else, got %v", got)
+	}
+}
+
+func TestWatcherServesUnaffectedFilesFromCache(t *testing.T) {
+	dir := t.TempDir()
+	writeWatchFile(t, filepath.Join(dir, "go.mod"), "module example.com/demo\n\ngo 1.21\n")
+	lib := filepath.Join(dir, "lib", "lib.go")
+	libOther := filepath.Join(dir, "lib", "util.go")
+	tools := filepath.Join(dir, "tools", "tools.go")
+	writeWatchFile(t, lib, "package lib\n")
+	writeWatchFile(t, libOther, "package lib\n\nfunc G() {}\n")
+	writeWatchFile(t, tools, "package tools\n\n"+watchLoopSource[len("package main\n\n"):])
+
+	w := newWatcher(dir, DefaultConfig())
+	added, _ := step(t, w)
+	if w.cache.hits != 0 {
+		t.Fatalf("the first run has nothing cached, got %d hits", w.cache.hits)
+	}
+	loopIssues := countType(added, models.IssueDeferInLoop)
+	if loopIssues == 0 {
+		t.Fatalf("expected the baseline to contain DeferInLoop, got %+v", added)
+	}
+
+	step(t, w)
+	if w.cache.hits != 3 {
+		t.Fatalf("an unchanged tree should come from the cache, got %d of 3 hits", w.cache.hits)
+	}
+
+	// util.go shares the package of lib.go and is analyzed again with it;
+	// only tools.go is unaffected
+	writeWatchFile(t, lib, "package lib\n\nfunc F() {}\n")
+	hits := w.cache.hits
+	added, resolved := step(t, w)
+	if got := w.cache.hits - hits; got != 1 {
+		t.Fatalf("expected only tools.go from the cache, got %d hits", got)
+	}
+	if len(added) != 0 || len(resolved) != 0 {
+		t.Fatalf("cached results should not be reported as changes, got %+v / %+v", added, resolved)
+	}
+	if n := countType(w.issues[tools], models.IssueDeferInLoop); n != loopIssues {
+		t.Fatalf("tools.go should keep its %d DeferInLoop issues from the cache, got %d", loopIssues, n)
+	}
+}
+
+func TestDiffIssues(t *testing.T) {
+	a := &models.Issue{Type: models.IssueDeferInLoop, Message: "m", Line: 3}
+	b := &models.Issue{Type: models.IssueDeferInLoop, Message: "m", Line: 9}
+	c := &models.Issue{Type: models.IssueRegexCompileInLoop, Message: "r"}
+
+	added, resolved := diffIssues([]*models.Issue{a, c}, []*models.Issue{b, b})
+	if len(added) != 1 || added[0] != b {
+		t.Fatalf("expected the second duplicate to be new, got %+v", added)
+	}
+	if len(resolved) != 1 || resolved[0] != c {
+		t.Fatalf("expected the regex issue to be resolved, got %+v", resolved)
+	}
+}
@@ -0,0 +1,136 @@
+// Package debugf is an example analyzer plugin. It reports ourlog.Debugf
+// calls in HTTP handlers that are not guarded by ourlog.DebugEnabled(): the
+// arguments are formatted on every request even when debug logging is off.
+package debugf
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const (
+	loggerPackage = "ourlog"
+	debugf        = "Debugf"
+	levelCheck    = "DebugEnabled"
+)
+
+// IssueDebugfInHandler is the issue type the analyzer reports
+var IssueDebugfInHandler = models.MustRegisterRule(models.Rule{
+	ID:          "ACME-001",
+	Name:        "DebugfInHandler",
+	Severity:    models.SeverityLevelMedium,
+	Group:       "Logging",
+	Description: "ourlog.Debugf in a request handler formats its arguments even when debug logging is off",
+})
+
+func init() {
+	analyzer.Register(analyzer.Registration{
+		Name:        "debugf",
+		Description: "Finds ourlog.Debugf in request handlers without a level check",
+		New:         New,
+		Rules:       []models.IssueType{IssueDebugfInHandler},
+	})
+}
+
+// Analyzer reports unguarded debug logging in HTTP handlers
+type Analyzer struct{}
+
+// New returns the analyzer for one file
+func New() analyzer.Analyzer {
+	return &Analyzer{}
+}
+
+func (a *Analyzer) Name() string {
+	return "Debugf In Handler"
+}
+
+func (a *Analyzer) Analyze(node interface{}, fset *token.FileSet) []*models.Issue {
+	file, ok := node.(*ast.File)
+	if !ok {
+		return nil
+	}
+	w := analyzer.NewWalker(fset, file)
+	issues := a.Subscribe(w)
+	w.Walk()
+	return issues()
+}
+
+// Subscribe makes the analyzer part of the traversal shared by the built-in
+// analyzers
+func (a *Analyzer) Subscribe(w *analyzer.Walker) func() []*models.Issue {
+	var issues []*models.Issue
+	w.On(func(n ast.Node, wc *analyzer.WalkContext) {
+		call, _ := n.(*ast.CallExpr)
+		if !isCall(call, debugf) || wc.FuncDecl == nil || !isHandler(wc.FuncDecl) || guarded(wc) {
+			return
+		}
+		pos := wc.Fset.Position(call.Pos())
+		end := wc.Fset.Position(call.End())
+		issues = append(issues, &models.Issue{
+			File:       pos.Filename,
+			Line:       pos.Line,
+			Column:     pos.Column,
+			EndLine:    end.Line,
+			EndColumn:  end.Column,
+			Position:   pos,
+			Type:       IssueDebugfInHandler,
+			Severity:   IssueDebugfInHandler.Severity(),
+			Message:    loggerPackage + "." + debugf + " in request handler " + wc.FuncDecl.Name.Name + " without a level check",
+			Suggestion: "Wrap the call in if " + loggerPackage + "." + levelCheck + "() { ... }",
+		})
+	}, (*ast.CallExpr)(nil))
+	return func() []*models.Issue { return issues }
+}
+
+// isCall reports whether call is ourlog.<name>(...)
+func isCall(call *ast.CallExpr, name string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == loggerPackage
+}
+
+// isHandler reports whether fn has the http.HandlerFunc signature
+func isHandler(fn *ast.FuncDecl) bool {
+	params := fn.Type.Params.List
+	if len(params) != 2 {
+		return false
+	}
+	writer, ok := params[0].Type.(*ast.SelectorExpr)
+	if !ok || writer.Sel.Name != "ResponseWriter" {
+		return false
+	}
+	star, ok := params[1].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	request, ok := star.X.(*ast.SelectorExpr)
+	return ok && request.Sel.Name == "Request"
+}
+
+// guarded reports whether the node is in the body of an if statement whose
+// condition checks the debug level
+func guarded(wc *analyzer.WalkContext) bool {
+	for i, node := range wc.Stack {
+		ifStmt, ok := node.(*ast.IfStmt)
+		if !ok || i+1 >= len(wc.Stack) || wc.Stack[i+1] != ifStmt.Body {
+			continue
+		}
+		checked := false
+		ast.Inspect(ifStmt.Cond, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok && isCall(call, levelCheck) {
+				checked = true
+			}
+			return !checked
+		})
+		if checked {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package debugf
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+	"github.com/SergeiSkv/AiBsCleaner/pkg/aibscleaner"
+)
+
+const handlerSource = `package api
+
+import (
+	"net/http"
+
+	"example.com/ourlog"
+)
+
+func handle(w http.ResponseWriter, r *http.Request) {
+	ourlog.Debugf("request %v", r)
+	if ourlog.DebugEnabled() {
+		ourlog.Debugf("headers %v", r.Header)
+	} else {
+		ourlog.Debugf("unreachable %v", r)
+	}
+}
+
+func helper(r *http.Request) {
+	ourlog.Debugf("not a handler %v", r)
+}
+`
+
+func TestRegisteredAnalyzerRunsWithBuiltins(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "handler.go", handlerSource, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	enabled := aibscleaner.DefaultConfig().EnabledAnalyzers()
+	if !enabled["debugf"] {
+		t.Fatalf("registered analyzer is not enabled by default: %v", enabled)
+	}
+
+	var lines []int
+	for _, issue := range analyzer.Analyze("handler.go", file, fset, enabled) {
+		if issue.Type == IssueDebugfInHandler {
+			lines = append(lines, issue.Line)
+		}
+	}
+	if len(lines) != 2 || lines[0] != 10 || lines[1] != 14 {
+		t.Errorf("DebugfInHandler issues on lines %v, want [10 14]", lines)
+	}
+
+	if got := IssueDebugfInHandler.String(); got != "DebugfInHandler" {
+		t.Errorf("String() = %q", got)
+	}
+	if parsed, err := models.ParseIssueType("ACME-001"); err != nil || parsed != IssueDebugfInHandler {
+		t.Errorf("ParseIssueType(ACME-001) = %v, %v", parsed, err)
+	}
+}
+
+func TestRegisteredAnalyzerCanBeDisabled(t *testing.T) {
+	cfg := aibscleaner.DefaultConfig()
+	cfg.Analyzers.Plugins = map[string]aibscleaner.AnalyzerConfig{"debugf": {Enabled: false}}
+	if cfg.EnabledAnalyzers()["debugf"] {
+		t.Error("analyzers.plugins.debugf.enabled: false did not disable the analyzer")
+	}
+}
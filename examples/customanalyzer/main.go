@@ -0,0 +1,17 @@
+// Command customanalyzer is aibscleaner built with a team-specific analyzer.
+// Importing a plugin package registers its analyzers and rules; everything
+// else, including flags, configuration and reports, is the stock command.
+package main
+
+import (
+	"log"
+
+	"github.com/SergeiSkv/AiBsCleaner/cmd"
+	_ "github.com/SergeiSkv/AiBsCleaner/examples/customanalyzer/debugf"
+)
+
+func main() {
+	if err := cmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
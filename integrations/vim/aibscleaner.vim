@@ -16,10 +16,12 @@ let g:aibscleaner_format = get(g:, 'aibscleaner_format', 'json')
 command! AiBsClean call aibscleaner#Run()
 command! AiBsCleanFix call aibscleaner#Fix()
 command! AiBsCleanToggle call aibscleaner#Toggle()
+command! -nargs=? AiBsCleanExplain call aibscleaner#Explain(<q-args>)
 
 " Keybindings
 nnoremap <silent> <leader>ab :AiBsClean<CR>
 nnoremap <silent> <leader>af :AiBsCleanFix<CR>
+nnoremap <silent> <leader>ae :AiBsCleanExplain<CR>
 
 " Auto-run on save for Go files
 augroup AiBsCleaner
@@ -38,10 +40,12 @@ function! aibscleaner#Run() abort
     endif
 
     echo "Running AiBsCleaner..."
-    let l:cmd = 'aibscleaner -path ' . shellescape(l:filename) . ' -format json'
-    let l:output = system(l:cmd)
+    " Analyze the buffer as it is, including unsaved changes
+    let l:cmd = 'aibscleaner --json --stdin --stdin-filename ' . shellescape(l:filename)
+    let l:output = system(l:cmd, getline(1, '$'))
     
-    if v:shell_error
+    " A non-zero exit status also signals high severity issues
+    if v:shell_error && empty(l:output)
         echo "AiBsCleaner: Error running analysis"
         return
     endif
@@ -70,6 +74,8 @@ function! aibscleaner#ProcessResults(output) abort
             \ 'filename': issue.file,
             \ 'lnum': issue.line,
             \ 'col': issue.column,
+            \ 'end_lnum': get(issue, 'end_line', 0),
+            \ 'end_col': get(issue, 'end_column', 0),
             \ 'text': issue.type . ': ' . issue.message,
             \ 'type': issue.severity == 'HIGH' ? 'E' : issue.severity == 'MEDIUM' ? 'W' : 'I'
         \ })
@@ -98,15 +104,62 @@ endfunction
 function! aibscleaner#Fix() abort
     let l:filename = expand('%:p')
     echo "Running AiBsCleaner auto-fix..."
-    let l:cmd = 'aibscleaner -path ' . shellescape(l:filename) . ' --fix'
+    if &modified
+        echo "AiBsCleaner: Save the buffer before fixing"
+        return
+    endif
+    let l:cmd = 'aibscleaner --fix --compact ' . shellescape(l:filename)
     let l:output = system(l:cmd)
     
-    if v:shell_error
+    if v:shell_error && empty(l:output)
         echo "AiBsCleaner: Error fixing issues"
     else
         edit!
-        echo "AiBsCleaner: Fixed issues"
+        echo "AiBsCleaner: Applied available fixes"
+    endif
+endfunction
+
+" Show the documentation of a rule, by default of the issue on the cursor line
+function! aibscleaner#Explain(rule) abort
+    let l:rule = a:rule
+    if empty(l:rule)
+        let l:word = expand('<cWORD>')
+        if l:word =~# 'PVE-\d\+'
+            let l:rule = matchstr(l:word, 'PVE-\d\+')
+        endif
+    endif
+    if empty(l:rule)
+        for l:item in getqflist()
+            if bufnr('%') == l:item.bufnr && line('.') == l:item.lnum
+                let l:rule = matchstr(l:item.text, '^\w\+')
+                break
+            endif
+        endfor
+    endif
+    if empty(l:rule)
+        echo "AiBsCleaner: No issue on this line, use :AiBsCleanExplain PVE-XXX"
+        return
+    endif
+
+    let l:output = system('aibscleaner explain ' . shellescape(l:rule))
+    if v:shell_error
+        echo "AiBsCleaner: Unknown rule " . l:rule
+        return
+    endif
+
+    " Reuse the explain window if it is open
+    let l:winnr = bufwinnr('__AiBsCleanerExplain__')
+    if l:winnr > 0
+        execute l:winnr . 'wincmd w'
+    else
+        botright new __AiBsCleanerExplain__
+        setlocal buftype=nofile bufhidden=wipe noswapfile nobuflisted
     endif
+    setlocal modifiable
+    silent %delete _
+    call setline(1, split(l:output, "\n"))
+    setlocal nomodifiable
+    normal! gg
 endfunction
 
 " Background run for auto-save
@@ -116,7 +169,7 @@ function! aibscleaner#RunBackground() abort
     endif
     
     let l:filename = expand('%:p')
-    let l:cmd = ['aibscleaner', '-path', l:filename, '-format', 'json']
+    let l:cmd = ['aibscleaner', '--json', l:filename]
     
     if has('nvim')
         call jobstart(l:cmd, {
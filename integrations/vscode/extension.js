@@ -1,6 +1,6 @@
 // VS Code Extension for AiBsCleaner
 const vscode = require('vscode');
-const { exec } = require('child_process');
+const { exec, execFile } = require('child_process');
 const path = require('path');
 
 function activate(context) {
@@ -21,11 +21,12 @@ function activate(context) {
         const filePath = document.fileName;
         const workspaceFolder = vscode.workspace.getWorkspaceFolder(document.uri);
         
-        // Run AiBsCleaner
-        exec(`aibscleaner -path "${filePath}" -format json`, 
-            { cwd: workspaceFolder.uri.fsPath },
+        // Run AiBsCleaner on the editor contents, including unsaved changes
+        const child = exec(`aibscleaner --json --stdin --stdin-filename "${filePath}"`,
+            { cwd: workspaceFolder ? workspaceFolder.uri.fsPath : path.dirname(filePath) },
             (error, stdout, stderr) => {
-                if (error) {
+                // A non-zero exit status also signals high severity issues
+                if (error && !stdout) {
                     vscode.window.showErrorMessage(`AiBsCleaner error: ${error.message}`);
                     return;
                 }
@@ -38,10 +39,15 @@ function activate(context) {
                 }
             }
         );
+        child.stdin.end(document.getText());
     });
 
     context.subscriptions.push(disposable);
 
+    context.subscriptions.push(
+        vscode.commands.registerCommand('aibscleaner.explain', explainRule)
+    );
+
     // Register CodeLens provider
     const codeLensProvider = new AiBsCleanerCodeLensProvider();
     context.subscriptions.push(
@@ -64,10 +70,13 @@ function displayResults(results, editor) {
     const diagnostics = [];
     
     results.issues.forEach(issue => {
-        const range = new vscode.Range(
-            issue.line - 1, issue.column - 1,
-            issue.line - 1, issue.column + 10
-        );
+        // end_column points just past the offending expression (1-based)
+        const range = issue.end_line
+            ? new vscode.Range(
+                issue.line - 1, issue.column - 1,
+                issue.end_line - 1, issue.end_column - 1
+            )
+            : editor.document.lineAt(issue.line - 1).range;
 
         const severity = issue.severity === 'HIGH' 
             ? vscode.DiagnosticSeverity.Error
@@ -91,6 +100,58 @@ function displayResults(results, editor) {
     diagnosticCollection.set(editor.document.uri, diagnostics);
 }
 
+// Show the documentation of the rule under the cursor, or of a rule picked
+// from `aibscleaner list-rules --json`
+async function explainRule() {
+    const editor = vscode.window.activeTextEditor;
+    let rule;
+    if (editor) {
+        const position = editor.selection.active;
+        const diagnostic = vscode.languages.getDiagnostics(editor.document.uri).find(d =>
+            d.source === 'AiBsCleaner' && d.range.contains(position)
+        );
+        rule = diagnostic && diagnostic.code;
+    }
+
+    if (!rule) {
+        let rules;
+        try {
+            rules = JSON.parse(await runAiBsCleaner(['list-rules', '--json']));
+        } catch (e) {
+            vscode.window.showErrorMessage(`AiBsCleaner error: ${e.message}`);
+            return;
+        }
+        const picked = await vscode.window.showQuickPick(
+            rules.map(r => ({ label: r.id, description: r.name, detail: r.description })),
+            { placeHolder: 'Rule to explain', matchOnDescription: true, matchOnDetail: true }
+        );
+        if (!picked) {
+            return;
+        }
+        rule = picked.label;
+    }
+
+    try {
+        const content = await runAiBsCleaner(['explain', String(rule)]);
+        const document = await vscode.workspace.openTextDocument({ content, language: 'plaintext' });
+        await vscode.window.showTextDocument(document, { viewColumn: vscode.ViewColumn.Beside, preview: true });
+    } catch (e) {
+        vscode.window.showErrorMessage(`AiBsCleaner error: ${e.message}`);
+    }
+}
+
+function runAiBsCleaner(args) {
+    return new Promise((resolve, reject) => {
+        execFile('aibscleaner', args, (error, stdout, stderr) => {
+            if (error) {
+                reject(new Error(stderr.trim() || error.message));
+                return;
+            }
+            resolve(stdout);
+        });
+    });
+}
+
 class AiBsCleanerCodeLensProvider {
     provideCodeLenses(document, token) {
         const codeLenses = [];
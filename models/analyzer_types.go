@@ -38,5 +38,12 @@ const (
 	AnalyzerTestCoverage
 	AnalyzerDependency
 	AnalyzerCPUOptimization
+	AnalyzerSuppression
+	AnalyzerPipeline
+	AnalyzerPlugin
+	AnalyzerStructLayout
+	AnalyzerCPUCache
+	AnalyzerHotPath
+	AnalyzerBoundsCheck
 	AnalyzerTypeMax
 )
@@ -7,11 +7,11 @@ import (
 	"strings"
 )
 
-const _AnalyzerTypeName = "LoopDeferOptimizationSliceMapStringReflectionInterfaceRegexTimeMemoryLeakGCPressureSyncPoolGoroutineChannelRaceConditionConcurrencyPatternsHTTPClientHTTPReuseIOBufferNetworkPatternsDatabaseSerializationCryptoPrivacyContextErrorHandlingAPIMisuseAIBullshitCGOTestCoverageDependencyCPUOptimizationTypeMax"
+const _AnalyzerTypeName = "LoopDeferOptimizationSliceMapStringReflectionInterfaceRegexTimeMemoryLeakGCPressureSyncPoolGoroutineChannelRaceConditionConcurrencyPatternsHTTPClientHTTPReuseIOBufferNetworkPatternsDatabaseSerializationCryptoPrivacyContextErrorHandlingAPIMisuseAIBullshitCGOTestCoverageDependencyCPUOptimizationSuppressionPipelinePluginStructLayoutCPUCacheHotPathBoundsCheckTypeMax"
 
-var _AnalyzerTypeIndex = [...]uint16{0, 4, 21, 26, 29, 35, 45, 54, 59, 63, 73, 83, 91, 100, 107, 120, 139, 149, 158, 166, 181, 189, 202, 208, 215, 222, 235, 244, 254, 257, 269, 279, 294, 301}
+var _AnalyzerTypeIndex = [...]uint16{0, 4, 21, 26, 29, 35, 45, 54, 59, 63, 73, 83, 91, 100, 107, 120, 139, 149, 158, 166, 181, 189, 202, 208, 215, 222, 235, 244, 254, 257, 269, 279, 294, 305, 313, 319, 331, 339, 346, 357, 364}
 
-const _AnalyzerTypeLowerName = "loopdeferoptimizationslicemapstringreflectioninterfaceregextimememoryleakgcpressuresyncpoolgoroutinechannelraceconditionconcurrencypatternshttpclienthttpreuseiobuffernetworkpatternsdatabaseserializationcryptoprivacycontexterrorhandlingapimisuseaibullshitcgotestcoveragedependencycpuoptimizationtypemax"
+const _AnalyzerTypeLowerName = "loopdeferoptimizationslicemapstringreflectioninterfaceregextimememoryleakgcpressuresyncpoolgoroutinechannelraceconditionconcurrencypatternshttpclienthttpreuseiobuffernetworkpatternsdatabaseserializationcryptoprivacycontexterrorhandlingapimisuseaibullshitcgotestcoveragedependencycpuoptimizationsuppressionpipelinepluginstructlayoutcpucachehotpathboundschecktypemax"
 
 func (i AnalyzerType) String() string {
 	if i >= AnalyzerType(len(_AnalyzerTypeIndex)-1) {
@@ -56,10 +56,17 @@ func _AnalyzerTypeNoOp() {
 	_ = x[AnalyzerTestCoverage-(29)]
 	_ = x[AnalyzerDependency-(30)]
 	_ = x[AnalyzerCPUOptimization-(31)]
-	_ = x[AnalyzerTypeMax-(32)]
+	_ = x[AnalyzerSuppression-(32)]
+	_ = x[AnalyzerPipeline-(33)]
+	_ = x[AnalyzerPlugin-(34)]
+	_ = x[AnalyzerStructLayout-(35)]
+	_ = x[AnalyzerCPUCache-(36)]
+	_ = x[AnalyzerHotPath-(37)]
+	_ = x[AnalyzerBoundsCheck-(38)]
+	_ = x[AnalyzerTypeMax-(39)]
 }
 
-var _AnalyzerTypeValues = []AnalyzerType{AnalyzerLoop, AnalyzerDeferOptimization, AnalyzerSlice, AnalyzerMap, AnalyzerString, AnalyzerReflection, AnalyzerInterface, AnalyzerRegex, AnalyzerTime, AnalyzerMemoryLeak, AnalyzerGCPressure, AnalyzerSyncPool, AnalyzerGoroutine, AnalyzerChannel, AnalyzerRaceCondition, AnalyzerConcurrencyPatterns, AnalyzerHTTPClient, AnalyzerHTTPReuse, AnalyzerIOBuffer, AnalyzerNetworkPatterns, AnalyzerDatabase, AnalyzerSerialization, AnalyzerCrypto, AnalyzerPrivacy, AnalyzerContext, AnalyzerErrorHandling, AnalyzerAPIMisuse, AnalyzerAIBullshit, AnalyzerCGO, AnalyzerTestCoverage, AnalyzerDependency, AnalyzerCPUOptimization, AnalyzerTypeMax}
+var _AnalyzerTypeValues = []AnalyzerType{AnalyzerLoop, AnalyzerDeferOptimization, AnalyzerSlice, AnalyzerMap, AnalyzerString, AnalyzerReflection, AnalyzerInterface, AnalyzerRegex, AnalyzerTime, AnalyzerMemoryLeak, AnalyzerGCPressure, AnalyzerSyncPool, AnalyzerGoroutine, AnalyzerChannel, AnalyzerRaceCondition, AnalyzerConcurrencyPatterns, AnalyzerHTTPClient, AnalyzerHTTPReuse, AnalyzerIOBuffer, AnalyzerNetworkPatterns, AnalyzerDatabase, AnalyzerSerialization, AnalyzerCrypto, AnalyzerPrivacy, AnalyzerContext, AnalyzerErrorHandling, AnalyzerAPIMisuse, AnalyzerAIBullshit, AnalyzerCGO, AnalyzerTestCoverage, AnalyzerDependency, AnalyzerCPUOptimization, AnalyzerSuppression, AnalyzerPipeline, AnalyzerPlugin, AnalyzerStructLayout, AnalyzerCPUCache, AnalyzerHotPath, AnalyzerBoundsCheck, AnalyzerTypeMax}
 
 var _AnalyzerTypeNameToValueMap = map[string]AnalyzerType{
 	_AnalyzerTypeName[0:4]:          AnalyzerLoop,
@@ -126,8 +133,22 @@ var _AnalyzerTypeNameToValueMap = map[string]AnalyzerType{
 	_AnalyzerTypeLowerName[269:279]: AnalyzerDependency,
 	_AnalyzerTypeName[279:294]:      AnalyzerCPUOptimization,
 	_AnalyzerTypeLowerName[279:294]: AnalyzerCPUOptimization,
-	_AnalyzerTypeName[294:301]:      AnalyzerTypeMax,
-	_AnalyzerTypeLowerName[294:301]: AnalyzerTypeMax,
+	_AnalyzerTypeName[294:305]:      AnalyzerSuppression,
+	_AnalyzerTypeLowerName[294:305]: AnalyzerSuppression,
+	_AnalyzerTypeName[305:313]:      AnalyzerPipeline,
+	_AnalyzerTypeLowerName[305:313]: AnalyzerPipeline,
+	_AnalyzerTypeName[313:319]:      AnalyzerPlugin,
+	_AnalyzerTypeLowerName[313:319]: AnalyzerPlugin,
+	_AnalyzerTypeName[319:331]:      AnalyzerStructLayout,
+	_AnalyzerTypeLowerName[319:331]: AnalyzerStructLayout,
+	_AnalyzerTypeName[331:339]:      AnalyzerCPUCache,
+	_AnalyzerTypeLowerName[331:339]: AnalyzerCPUCache,
+	_AnalyzerTypeName[339:346]:      AnalyzerHotPath,
+	_AnalyzerTypeLowerName[339:346]: AnalyzerHotPath,
+	_AnalyzerTypeName[346:357]:      AnalyzerBoundsCheck,
+	_AnalyzerTypeLowerName[346:357]: AnalyzerBoundsCheck,
+	_AnalyzerTypeName[357:364]:      AnalyzerTypeMax,
+	_AnalyzerTypeLowerName[357:364]: AnalyzerTypeMax,
 }
 
 var _AnalyzerTypeNames = []string{
@@ -163,7 +184,14 @@ var _AnalyzerTypeNames = []string{
 	_AnalyzerTypeName[257:269],
 	_AnalyzerTypeName[269:279],
 	_AnalyzerTypeName[279:294],
-	_AnalyzerTypeName[294:301],
+	_AnalyzerTypeName[294:305],
+	_AnalyzerTypeName[305:313],
+	_AnalyzerTypeName[313:319],
+	_AnalyzerTypeName[319:331],
+	_AnalyzerTypeName[331:339],
+	_AnalyzerTypeName[339:346],
+	_AnalyzerTypeName[346:357],
+	_AnalyzerTypeName[357:364],
 }
 
 // AnalyzerTypeString retrieves an enum value from the enum constants string name.
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateDocs = flag.Bool("update", false, "rewrite PVE_CODES.md from the rule registry")
+
+// TestBuiltinRulesAreConsistent fails when an issue type has no rule, or a
+// rule disagrees with its issue type or has incomplete documentation
+func TestBuiltinRulesAreConsistent(t *testing.T) {
+	var types []IssueType
+	for _, v := range IssueTypeValues() {
+		if v < IssueTypeMax {
+			types = append(types, v)
+		}
+	}
+	require.Len(t, builtinRules, len(types), "every issue type needs exactly one rule in builtinRules")
+
+	for i, rule := range builtinRules {
+		require.Equal(t, types[i], rule.Type, "builtinRules must list issue types in PVE order")
+		t.Run(rule.Name, func(t *testing.T) {
+			require.Equal(t, fmt.Sprintf("PVE-%03d", int(rule.Type)), rule.ID)
+			require.Equal(t, rule.Type.String(), rule.Name)
+
+			described, ok := rule.Type.Rule()
+			require.True(t, ok)
+			require.Equal(t, rule, described)
+			require.Equal(t, rule.Severity, rule.Type.Severity())
+			require.Equal(t, rule.Analyzer, rule.Type.GetAnalyzer())
+			require.Equal(t, rule.ID, rule.Type.GetPVEID())
+
+			require.True(t, rule.Analyzer.IsAAnalyzerType())
+			require.NotEqual(t, AnalyzerPlugin, rule.Analyzer)
+			require.Contains(t, ruleGroups, rule.Group)
+			require.True(t, rule.Severity.IsASeverityLevel())
+
+			require.NotEmpty(t, rule.Description)
+			require.True(t, strings.HasSuffix(rule.Description, "."), "description should be a sentence")
+			require.NotEmpty(t, rule.Tags)
+			for _, tag := range rule.Tags {
+				require.Equal(t, strings.ToLower(tag), tag)
+				require.NotContains(t, tag, " ")
+			}
+			require.Len(t, slices.Compact(slices.Sorted(slices.Values(rule.Tags))), len(rule.Tags), "duplicate tag")
+
+			require.Equal(t, rule.Bad == "", rule.Good == "", "bad and good examples come in pairs")
+			if rule.Bad != "" {
+				require.NotEqual(t, rule.Bad, rule.Good)
+				requireGoSnippet(t, rule.Bad)
+				requireGoSnippet(t, rule.Good)
+			}
+		})
+	}
+}
+
+// requireGoSnippet fails unless code parses as declarations or statements
+func requireGoSnippet(t *testing.T, code string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, "", "package p\n"+code, 0); err == nil {
+		return
+	}
+	_, err := parser.ParseFile(fset, "", "package p\nfunc _() {\n"+code+"\n}", 0)
+	require.NoError(t, err, "example does not parse:\n%s", code)
+}
+
+// rulesWithoutExamples are the rules whose findings are not in Go source, so
+// they have no bad and good code examples
+var rulesWithoutExamples = []IssueType{
+	IssueDependencyVulnerable, IssueDependencyOutdated, IssueDependencyIndirect, // go.mod
+	IssueDependencyLocalReplace, IssueDependencyNoChecksum, IssueDependencyEmptyChecksum, // go.mod and go.sum
+	IssueHighGCPressureDetected, IssueFrequentAllocationDetected, // profiles
+	IssueLargeHeapAllocDetected, IssuePointerHeavyStructDetected, // profiles
+	IssueMissingTest,     // a package without test files
+	IssueAnalysisTimeout, // the analysis itself
+}
+
+// TestRuleDocsDescribeBuiltinRules fails when a built-in rule has no file in
+// ruledocs or no code examples, a file names no built-in rule or it lists a
+// related rule that does not help the reader
+func TestRuleDocsDescribeBuiltinRules(t *testing.T) {
+	for _, rule := range builtinRules {
+		require.NotEmpty(t, rule.Why, "%s has no ruledocs/%s.md", rule.Name, rule.Name)
+		require.Equal(t, !slices.Contains(rulesWithoutExamples, rule.Type), rule.Bad != "", "%s: bad and good examples", rule.Name)
+	}
+
+	files, err := fs.Glob(ruleDocs, "ruledocs/*")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		name, ok := strings.CutSuffix(path.Base(file), ".md")
+		require.True(t, ok, "%s is not a markdown file", file)
+		issueType, err := IssueTypeString(name)
+		require.NoError(t, err, "%s names no rule", file)
+		rule, ok := issueType.Rule()
+		require.True(t, ok)
+		require.Equal(t, name, rule.Name, "%s must be named exactly like its rule", file)
+
+		require.NotEmpty(t, rule.Why)
+		require.NotEmpty(t, rule.Cost)
+		require.NotEmpty(t, rule.Related, "%s lists no related rules", file)
+		require.NotContains(t, rule.Related, rule.Type, "%s relates to itself", file)
+		require.Len(t, slices.Compact(slices.Sorted(slices.Values(rule.Related))), len(rule.Related), "%s: duplicate related rule", file)
+	}
+}
+
+func TestLoadRuleDocRejectsMalformedDocs(t *testing.T) {
+	rule := Rule{Type: IssueDeferInLoop, Name: "DeferInLoop"}
+	require.NoError(t, loadRuleDoc(&rule))
+	require.Contains(t, rule.Why, "surrounding function returns")
+	require.NotContains(t, rule.Why, "\n", "paragraph lines are joined")
+	require.Contains(t, rule.Related, IssueMemoryLeak)
+
+	rule = Rule{Type: IssueNestedLoop, Name: "NotDocumented"}
+	require.NoError(t, loadRuleDoc(&rule))
+	require.Empty(t, rule.Why)
+
+	require.Equal(t, "one two\n\nthree", unwrapMarkdown("\none\ntwo\n\n\nthree\n"))
+}
+
+func TestRuleGroupsAreUsed(t *testing.T) {
+	for _, group := range RuleGroups() {
+		require.True(t, slices.ContainsFunc(builtinRules, func(r Rule) bool { return r.Group == group }), group)
+	}
+}
+
+func TestRulesOf(t *testing.T) {
+	require.Equal(t, []IssueType{IssueStaleIgnoreDirective}, RulesOf(AnalyzerSuppression))
+	require.Empty(t, RulesOf(AnalyzerPlugin))
+
+	covered := 0
+	for _, a := range AnalyzerTypeValues() {
+		for _, issueType := range RulesOf(a) {
+			require.Equal(t, a, issueType.GetAnalyzer())
+			covered++
+		}
+	}
+	require.Equal(t, len(builtinRules), covered)
+}
+
+// TestPVECodesAreCurrent fails when PVE_CODES.md was not regenerated with
+// `go test ./models -update` after changing the rules
+func TestPVECodesAreCurrent(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteRulesMarkdown(&buf))
+
+	const path = "../PVE_CODES.md"
+	if *updateDocs {
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	}
+	published, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(published), buf.String(), "PVE_CODES.md drifted, run go test ./models -update")
+}
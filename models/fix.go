@@ -0,0 +1,30 @@
+package models
+
+// TextEdit replaces the bytes in [Start, End) of a file with NewText.
+// Offsets are byte offsets into the analyzed source; Start == End is an insertion.
+// An empty Filename refers to the file of the issue the fix belongs to.
+type TextEdit struct {
+	Filename string `json:"filename,omitempty"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+	NewText  string `json:"new_text"`
+}
+
+// SuggestedFix is a set of edits that resolves an issue when applied together
+type SuggestedFix struct {
+	Message string     `json:"message"`
+	Edits   []TextEdit `json:"edits"`
+	Imports []string   `json:"imports,omitempty"` // import paths the edited code needs
+}
+
+// Overlaps reports whether two edits touch the same bytes.
+// Insertions at the boundary of another edit do not overlap it.
+func (e TextEdit) Overlaps(other TextEdit) bool {
+	if e == other {
+		return false
+	}
+	if e.Start == e.End && other.Start == other.End {
+		return false
+	}
+	return e.Start < other.End && other.Start < e.End
+}
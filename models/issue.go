@@ -11,6 +11,8 @@ type Issue struct {
 	File       string         `json:"file,omitempty"`
 	Line       int            `json:"line,omitempty"`
 	Column     int            `json:"column,omitempty"`
+	EndLine    int            `json:"end_line,omitempty"`
+	EndColumn  int            `json:"end_column,omitempty"`
 	Position   token.Position `json:"position"`
 	Type       IssueType      `json:"type,omitempty"`
 	Severity   SeverityLevel  `json:"severity,omitempty"`
@@ -18,6 +20,7 @@ type Issue struct {
 	Suggestion string         `json:"suggestion,omitempty"`
 	Code       string         `json:"code,omitempty"`
 	CanBeFixed bool           `json:"can_be_fixed,omitempty"`
+	Fix        *SuggestedFix  `json:"fix,omitempty"`
 	FixedAt    time.Time      `json:"fixed_at,omitempty"`
 	CreatedAt  time.Time      `json:"created_at,omitempty"`
 	UpdatedAt  time.Time      `json:"updated_at,omitempty"`
@@ -1,6 +1,10 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // IssueType represents specific issue types as an enum
 //
@@ -282,333 +286,60 @@ const (
 	IssueNestedRangeCache
 	IssueMapRangeCache
 
+	// Suppression issues
+	IssueStaleIgnoreDirective
+
+	// Analysis issues
+	IssueAnalysisTimeout
+
 	// Sentinel
 	IssueTypeMax
 )
 
-// Severity returns the default severity for this issue type
-var issueSeverityMap = map[IssueType]SeverityLevel{
-	// HIGH severity issues
-	IssueMemoryLeak:             SeverityLevelHigh,
-	IssueGoroutineLeak:          SeverityLevelHigh,
-	IssueRaceCondition:          SeverityLevelHigh,
-	IssueSQLNPlusOne:            SeverityLevelHigh,
-	IssueHTTPNoTimeout:          SeverityLevelHigh,
-	IssuePrivacyHardcodedSecret: SeverityLevelHigh,
-	IssueDependencyVulnerable:   SeverityLevelHigh,
-	IssueWeakCrypto:             SeverityLevelHigh,
-	IssuePanicInLibrary:         SeverityLevelHigh,
-	IssueHighComplexityO3:       SeverityLevelHigh,
-	IssueCGOMemoryLeak:          SeverityLevelHigh,
-	IssueTypeMax:                SeverityLevelHigh,
-
-	// MEDIUM severity issues
-	IssueNestedLoop:              SeverityLevelMedium,
-	IssueAllocInLoop:             SeverityLevelMedium,
-	IssueHighGCPressure:          SeverityLevelMedium,
-	IssueSliceAppendInLoop:       SeverityLevelMedium,
-	IssueDeferInLoop:             SeverityLevelMedium,
-	IssueAIUnnecessaryComplexity: SeverityLevelMedium,
-	IssueStructLayoutUnoptimized: SeverityLevelMedium,
-	IssueCacheFalseSharing:       SeverityLevelHigh,
-	IssueSoAPattern:              SeverityLevelMedium,
-	IssueNestedRangeCache:        SeverityLevelMedium,
-}
-
+// Severity returns the default severity of issues of this type
 func (i IssueType) Severity() SeverityLevel {
-	if severity, ok := issueSeverityMap[i]; ok {
-		return severity
+	if rule, ok := i.Rule(); ok {
+		return rule.Severity
 	}
-	// LOW severity - all other issues
 	return SeverityLevelLow
 }
 
-// issueAnalyzerMap maps each issue type to its corresponding analyzer
-var issueAnalyzerMap = map[IssueType]AnalyzerType{
-	// Loop issues
-	IssueNestedLoop:       AnalyzerLoop,
-	IssueAllocInLoop:      AnalyzerLoop,
-	IssueAppendInLoop:     AnalyzerLoop,
-	IssueDeferInLoop:      AnalyzerLoop,
-	IssueRegexInLoop:      AnalyzerLoop,
-	IssueTimeInLoop:       AnalyzerLoop,
-	IssueSQLInLoop:        AnalyzerLoop,
-	IssueDNSInLoop:        AnalyzerLoop,
-	IssueReflectionInLoop: AnalyzerLoop,
-	IssueCPUIntensiveLoop: AnalyzerLoop,
-
-	// Memory issues
-	IssueMemoryLeak:         AnalyzerMemoryLeak,
-	IssueGlobalVar:          AnalyzerMemoryLeak,
-	IssueLargeAllocation:    AnalyzerMemoryLeak,
-	IssueHighGCPressure:     AnalyzerGCPressure,
-	IssueFrequentAllocation: AnalyzerGCPressure,
-	IssueLargeHeapAlloc:     AnalyzerGCPressure,
-	IssuePointerHeavyStruct: AnalyzerGCPressure,
-	IssueMissingDefer:       AnalyzerDeferOptimization,
-	IssueMissingClose:       AnalyzerDeferOptimization,
-
-	// Slice issues
-	IssueSliceCapacity:     AnalyzerSlice,
-	IssueSliceCopy:         AnalyzerSlice,
-	IssueSliceAppend:       AnalyzerSlice,
-	IssueSliceRangeCopy:    AnalyzerSlice,
-	IssueSliceAppendInLoop: AnalyzerSlice,
-	IssueSlicePrealloc:     AnalyzerSlice,
-
-	// Map issues
-	IssueMapCapacity: AnalyzerMap,
-	IssueMapClear:    AnalyzerMap,
-	IssueMapPrealloc: AnalyzerMap,
-
-	// String issues
-	IssueStringConcat:      AnalyzerString,
-	IssueStringBuilder:     AnalyzerString,
-	IssueStringInefficient: AnalyzerString,
-
-	// Defer issues
-	IssueDeferInShortFunc:      AnalyzerDeferOptimization,
-	IssueDeferOverhead:         AnalyzerDeferOptimization,
-	IssueUnnecessaryDefer:      AnalyzerDeferOptimization,
-	IssueDeferAtEnd:            AnalyzerDeferOptimization,
-	IssueMultipleDefers:        AnalyzerDeferOptimization,
-	IssueDeferInHotPath:        AnalyzerDeferOptimization,
-	IssueDeferLargeCapture:     AnalyzerDeferOptimization,
-	IssueUnnecessaryMutexDefer: AnalyzerDeferOptimization,
-	IssueMissingDeferUnlock:    AnalyzerDeferOptimization,
-	IssueMissingDeferClose:     AnalyzerDeferOptimization,
-
-	// Concurrency issues
-	IssueRaceCondition:                AnalyzerRaceCondition,
-	IssueRaceConditionGlobal:          AnalyzerRaceCondition,
-	IssueUnsyncMapAccess:              AnalyzerRaceCondition,
-	IssueRaceClosure:                  AnalyzerRaceCondition,
-	IssueGoroutineLeak:                AnalyzerGoroutine,
-	IssueUnbufferedChannel:            AnalyzerChannel,
-	IssueGoroutineOverhead:            AnalyzerGoroutine,
-	IssueSyncMutexValue:               AnalyzerConcurrencyPatterns,
-	IssueWaitgroupMisuse:              AnalyzerConcurrencyPatterns,
-	IssueRaceInDefer:                  AnalyzerRaceCondition,
-	IssueAtomicMisuse:                 AnalyzerConcurrencyPatterns,
-	IssueGoroutineNoRecover:           AnalyzerGoroutine,
-	IssueGoroutineCapturesLoop:        AnalyzerGoroutine,
-	IssueWaitGroupAddInLoop:           AnalyzerConcurrencyPatterns,
-	IssueWaitGroupWaitBeforeStart:     AnalyzerConcurrencyPatterns,
-	IssueMutexForReadOnly:             AnalyzerConcurrencyPatterns,
-	IssueSelectWithSingleCase:         AnalyzerChannel,
-	IssueBusyWait:                     AnalyzerConcurrencyPatterns,
-	IssueContextBackgroundInGoroutine: AnalyzerContext,
-	IssueGoroutinePerRequest:          AnalyzerGoroutine,
-	IssueNoWorkerPool:                 AnalyzerGoroutine,
-	IssueUnbufferedSignalChan:         AnalyzerChannel,
-	IssueSelectDefault:                AnalyzerChannel,
-	IssueChannelSize:                  AnalyzerChannel,
-	IssueRangeOverChannel:             AnalyzerChannel,
-	IssueChannelDeadlock:              AnalyzerChannel,
-	IssueChannelMultipleClose:         AnalyzerChannel,
-	IssueChannelSendOnClosed:          AnalyzerChannel,
-
-	// Network & HTTP issues
-	IssueHTTPNoTimeout:         AnalyzerHTTPClient,
-	IssueHTTPNoClose:           AnalyzerHTTPClient,
-	IssueHTTPDefaultClient:     AnalyzerHTTPClient,
-	IssueHTTPNoContext:         AnalyzerHTTPClient,
-	IssueKeepaliveMissing:      AnalyzerHTTPReuse,
-	IssueConnectionPool:        AnalyzerHTTPReuse,
-	IssueNoReuseConnection:     AnalyzerHTTPReuse,
-	IssueHTTPNoConnectionReuse: AnalyzerHTTPReuse,
-
-	// Database issues
-	IssueNoPreparedStmt: AnalyzerDatabase,
-	IssueMissingDBClose: AnalyzerDatabase,
-	IssueSQLNPlusOne:    AnalyzerDatabase,
-
-	// Interface & Reflection issues
-	IssueReflection:          AnalyzerReflection,
-	IssueInterfaceAllocation: AnalyzerInterface,
-	IssueEmptyInterface:      AnalyzerInterface,
-	IssueInterfacePollution:  AnalyzerInterface,
-
-	// Time & Regex issues
-	IssueTimeAfterLeak:      AnalyzerTime,
-	IssueTimeFormat:         AnalyzerTime,
-	IssueTimeNowInLoop:      AnalyzerTime,
-	IssueRegexCompile:       AnalyzerRegex,
-	IssueRegexCompileInLoop: AnalyzerRegex,
-
-	// Context issues
-	IssueContextBackground:    AnalyzerContext,
-	IssueContextValue:         AnalyzerContext,
-	IssueMissingContextCancel: AnalyzerContext,
-	IssueContextLeak:          AnalyzerContext,
-	IssueContextInStruct:      AnalyzerContext,
-	IssueContextNotFirst:      AnalyzerContext,
-	IssueContextMisuse:        AnalyzerContext,
-
-	// Error handling issues
-	IssueErrorIgnored:      AnalyzerErrorHandling,
-	IssueErrorCheckMissing: AnalyzerErrorHandling,
-	IssuePanicRecover:      AnalyzerErrorHandling,
-	IssueErrorStringFormat: AnalyzerErrorHandling,
-	IssuePanicRisk:         AnalyzerErrorHandling,
-	IssuePanicInLibrary:    AnalyzerErrorHandling,
-
-	// AI Bullshit issues
-	IssueAIBullshitConcurrency:   AnalyzerAIBullshit,
-	IssueAIReflectionOverkill:    AnalyzerAIBullshit,
-	IssueAIPatternAbuse:          AnalyzerAIBullshit,
-	IssueAIEnterpriseHelloWorld:  AnalyzerAIBullshit,
-	IssueAICaptainObvious:        AnalyzerAIBullshit,
-	IssueAIOverengineeredSimple:  AnalyzerAIBullshit,
-	IssueAIGeneratedComment:      AnalyzerAIBullshit,
-	IssueAIUnnecessaryComplexity: AnalyzerAIBullshit,
-	IssueAIOverAbstraction:       AnalyzerAIBullshit,
-	IssueAIVariable:              AnalyzerAIBullshit,
-	IssueAIErrorHandling:         AnalyzerAIBullshit,
-	IssueAIStructure:             AnalyzerAIBullshit,
-	IssueAIRepetition:            AnalyzerAIBullshit,
-	IssueAIFactorySimple:         AnalyzerAIBullshit,
-	IssueAIRedundantElse:         AnalyzerAIBullshit,
-	IssueAIGoroutineOverkill:     AnalyzerAIBullshit,
-	IssueAIUnnecessaryReflection: AnalyzerAIBullshit,
-	IssueAIUnnecessaryInterface:  AnalyzerAIBullshit,
-
-	// GC Pressure issues
-	IssueHighGCPressureDetected:     AnalyzerGCPressure,
-	IssueFrequentAllocationDetected: AnalyzerGCPressure,
-	IssueLargeHeapAllocDetected:     AnalyzerGCPressure,
-	IssuePointerHeavyStructDetected: AnalyzerGCPressure,
-
-	// Sync Pool issues
-	IssueSyncPoolOpportunity: AnalyzerSyncPool,
-	IssueSyncPoolPutMissing:  AnalyzerSyncPool,
-	IssueSyncPoolTypeAssert:  AnalyzerSyncPool,
-	IssueSyncPoolMisuse:      AnalyzerSyncPool,
-
-	// API Misuse issues
-	IssueAPIMisuse:               AnalyzerAPIMisuse,
-	IssueWGMisuse:                AnalyzerAPIMisuse,
-	IssuePprofInProd:             AnalyzerAPIMisuse,
-	IssuePprofNilWriter:          AnalyzerAPIMisuse,
-	IssueDebugInProd:             AnalyzerAPIMisuse,
-	IssueWaitgroupAddInGoroutine: AnalyzerAPIMisuse,
-	IssueContextBackgroundMisuse: AnalyzerAPIMisuse,
-	IssueSleepInLoop:             AnalyzerAPIMisuse,
-	IssueSprintfConcatenation:    AnalyzerAPIMisuse,
-	IssueLogInHotPath:            AnalyzerAPIMisuse,
-	IssueRecoverWithoutDefer:     AnalyzerAPIMisuse,
-	IssueJSONMarshalInLoop:       AnalyzerAPIMisuse,
-	IssueRegexCompileInFunc:      AnalyzerAPIMisuse,
-	IssueMutexByValue:            AnalyzerAPIMisuse,
-
-	// Privacy issues
-	IssuePrivacyHardcodedSecret:    AnalyzerPrivacy,
-	IssuePrivacyAWSKey:             AnalyzerPrivacy,
-	IssuePrivacyJWTToken:           AnalyzerPrivacy,
-	IssuePrivacyEmailPII:           AnalyzerPrivacy,
-	IssuePrivacySSNPII:             AnalyzerPrivacy,
-	IssuePrivacyCreditCardPII:      AnalyzerPrivacy,
-	IssuePrivacyLoggingSensitive:   AnalyzerPrivacy,
-	IssuePrivacyPrintingSensitive:  AnalyzerPrivacy,
-	IssuePrivacyExposedField:       AnalyzerPrivacy,
-	IssuePrivacyUnencryptedDBWrite: AnalyzerPrivacy,
-	IssuePrivacyDirectInputToDB:    AnalyzerPrivacy,
-
-	// Dependency issues
-	IssueDependencyDeprecated:      AnalyzerDependency,
-	IssueDependencyVulnerable:      AnalyzerDependency,
-	IssueDependencyOutdated:        AnalyzerDependency,
-	IssueDependencyCGO:             AnalyzerDependency,
-	IssueDependencyUnsafe:          AnalyzerDependency,
-	IssueDependencyInternal:        AnalyzerDependency,
-	IssueDependencyIndirect:        AnalyzerDependency,
-	IssueDependencyLocalReplace:    AnalyzerDependency,
-	IssueDependencyNoChecksum:      AnalyzerDependency,
-	IssueDependencyEmptyChecksum:   AnalyzerDependency,
-	IssueDependencyVersionConflict: AnalyzerDependency,
-
-	// Test Coverage issues
-	IssueMissingTest:         AnalyzerTestCoverage,
-	IssueMissingExample:      AnalyzerTestCoverage,
-	IssueMissingBenchmark:    AnalyzerTestCoverage,
-	IssueUntestedExport:      AnalyzerTestCoverage,
-	IssueUntestedType:        AnalyzerTestCoverage,
-	IssueUntestedError:       AnalyzerTestCoverage,
-	IssueUntestedConcurrency: AnalyzerTestCoverage,
-	IssueUntestedIOFunction:  AnalyzerTestCoverage,
-
-	// Crypto issues
-	IssueWeakCrypto:     AnalyzerCrypto,
-	IssueInsecureRandom: AnalyzerCrypto,
-	IssueWeakHash:       AnalyzerCrypto,
-
-	// Serialization issues
-	IssueJSONInLoop:          AnalyzerSerialization,
-	IssueXMLInLoop:           AnalyzerSerialization,
-	IssueSerializationInLoop: AnalyzerSerialization,
-
-	// IO Buffer issues
-	IssueUnbufferedIO:     AnalyzerIOBuffer,
-	IssueSmallBuffer:      AnalyzerIOBuffer,
-	IssueMissingBuffering: AnalyzerIOBuffer,
-
-	// Network Pattern issues
-	IssueNetworkInLoop:    AnalyzerNetworkPatterns,
-	IssueDNSLookupInLoop:  AnalyzerNetworkPatterns,
-	IssueNoConnectionPool: AnalyzerNetworkPatterns,
-
-	// CGO issues
-	IssueCGOCall:       AnalyzerCGO,
-	IssueCGOInLoop:     AnalyzerCGO,
-	IssueCGOMemoryLeak: AnalyzerCGO,
-
-	// CPU Optimization issues
-	IssueCPUIntensive:           AnalyzerCPUOptimization,
-	IssueUnnecessaryCopy:        AnalyzerCPUOptimization,
-	IssueBoundsCheckElimination: AnalyzerCPUOptimization,
-	IssueInefficientAlgorithm:   AnalyzerCPUOptimization,
-	IssueCacheUnfriendly:        AnalyzerCPUOptimization,
-	IssueHighComplexityO2:       AnalyzerCPUOptimization,
-	IssueHighComplexityO3:       AnalyzerCPUOptimization,
-	IssuePreventsInlining:       AnalyzerCPUOptimization,
-	IssueExpensiveOpInHotPath:   AnalyzerCPUOptimization,
-	IssueModuloPowerOfTwo:       AnalyzerCPUOptimization,
-
-	// Misc issues
-	IssueMagicNumber:         AnalyzerAPIMisuse,
-	IssueUselessCondition:    AnalyzerAPIMisuse,
-	IssueEmptyElse:           AnalyzerAPIMisuse,
-	IssueSleepInsteadOfSync:  AnalyzerAPIMisuse,
-	IssueConsoleLogDebugging: AnalyzerAPIMisuse,
-	IssueHardcodedConfig:     AnalyzerAPIMisuse,
-	IssueGlobalVariable:      AnalyzerAPIMisuse,
-	IssuePointerToSlice:      AnalyzerAPIMisuse,
-
-	// Struct Layout issues
-	IssueStructLayoutUnoptimized: AnalyzerCPUOptimization,
-	IssueStructLargePadding:      AnalyzerCPUOptimization,
-	IssueStructFieldAlignment:    AnalyzerCPUOptimization,
-
-	// CPU Cache optimization issues
-	IssueCacheFalseSharing:  AnalyzerCPUOptimization,
-	IssueCacheLineWaste:     AnalyzerCPUOptimization,
-	IssueCacheLineAlignment: AnalyzerCPUOptimization,
-	IssueOversizedType:      AnalyzerCPUOptimization,
-	IssueUnspecificIntType:  AnalyzerCPUOptimization,
-	IssueSoAPattern:         AnalyzerCPUOptimization,
-	IssueNestedRangeCache:   AnalyzerCPUOptimization,
-	IssueMapRangeCache:      AnalyzerCPUOptimization,
-}
-
 // GetAnalyzer returns the analyzer type that detects this issue
 func (i IssueType) GetAnalyzer() AnalyzerType {
-	if analyzer, ok := issueAnalyzerMap[i]; ok {
-		return analyzer
+	if rule, ok := i.Rule(); ok {
+		return rule.Analyzer
 	}
 	return AnalyzerLoop // Default fallback
 }
 
-// GetPVEID returns the PVE-ID for this issue type (e.g., PVE-001)
+// GetPVEID returns the PVE-ID for this issue type (e.g., PVE-001), or the ID
+// of a registered rule
 func (i IssueType) GetPVEID() string {
+	if rule, ok := i.Rule(); ok {
+		return rule.ID
+	}
 	return fmt.Sprintf("PVE-%03d", int(i))
 }
+
+// ParseIssueType resolves a user-supplied rule reference to an IssueType.
+// It accepts enum names (NestedLoop), their SCREAMING_SNAKE form (NESTED_LOOP),
+// PVE IDs (PVE-000) and the IDs of registered rules, all case-insensitively.
+func ParseIssueType(s string) (IssueType, error) {
+	s = strings.TrimSpace(s)
+	if t, ok := ruleByID(s); ok {
+		return t, nil
+	}
+	if id, ok := strings.CutPrefix(strings.ToUpper(s), "PVE-"); ok {
+		n, err := strconv.Atoi(id)
+		if err == nil && n >= 0 && IssueType(n) < IssueTypeMax && IssueType(n).IsAIssueType() {
+			return IssueType(n), nil
+		}
+		return 0, fmt.Errorf("unknown PVE ID %q", s)
+	}
+
+	t, err := IssueTypeString(strings.ReplaceAll(s, "_", ""))
+	if err != nil || t == IssueTypeMax {
+		return 0, fmt.Errorf("unknown issue type %q", s)
+	}
+	return t, nil
+}
@@ -82,6 +82,33 @@ func TestIssueTypeValues(t *testing.T) {
 	require.Contains(t, values, IssueRaceCondition)
 }
 
+func TestParseIssueType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected IssueType
+	}{
+		{"NestedLoop", IssueNestedLoop},
+		{"nestedloop", IssueNestedLoop},
+		{"DEFER_IN_LOOP", IssueDeferInLoop},
+		{IssueRaceCondition.GetPVEID(), IssueRaceCondition},
+		{"pve-000", IssueNestedLoop},
+		{" HTTPNoTimeout ", IssueHTTPNoTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseIssueType(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+
+	for _, bad := range []string{"", "NoSuchRule", "PVE-999", "PVE-abc", "TypeMax"} {
+		_, err := ParseIssueType(bad)
+		require.Error(t, err, bad)
+	}
+}
+
 func TestIssueTypeIsValid(t *testing.T) {
 	// Test IsAIssueType() validation
 	require.True(t, IssueNestedLoop.IsAIssueType())
@@ -7,8 +7,8 @@ import (
 	"strings"
 )
 
-const _IssueTypeName = "NestedLoopAllocInLoopAppendInLoopDeferInLoopRegexInLoopTimeInLoopSQLInLoopDNSInLoopReflectionInLoopCPUIntensiveLoopMemoryLeakGlobalVarLargeAllocationHighGCPressureFrequentAllocationLargeHeapAllocPointerHeavyStructMissingDeferMissingCloseSliceCapacitySliceCopySliceAppendSliceRangeCopySliceAppendInLoopSlicePreallocMapCapacityMapClearMapPreallocStringConcatStringBuilderStringInefficientDeferInShortFuncDeferOverheadUnnecessaryDeferDeferAtEndMultipleDefersDeferInHotPathDeferLargeCaptureUnnecessaryMutexDeferMissingDeferUnlockMissingDeferCloseRaceConditionRaceConditionGlobalUnsyncMapAccessRaceClosureGoroutineLeakUnbufferedChannelGoroutineOverheadSyncMutexValueWaitgroupMisuseRaceInDeferAtomicMisuseGoroutineNoRecoverGoroutineCapturesLoopWaitGroupAddInLoopWaitGroupWaitBeforeStartMutexForReadOnlySelectWithSingleCaseBusyWaitContextBackgroundInGoroutineGoroutinePerRequestNoWorkerPoolUnbufferedSignalChanSelectDefaultChannelSizeRangeOverChannelChannelDeadlockChannelMultipleCloseChannelSendOnClosedHTTPNoTimeoutHTTPNoCloseHTTPDefaultClientHTTPNoContextKeepaliveMissingConnectionPoolNoReuseConnectionHTTPNoConnectionReuseNoPreparedStmtMissingDBCloseSQLNPlusOneReflectionInterfaceAllocationEmptyInterfaceInterfacePollutionTimeAfterLeakTimeFormatTimeNowInLoopRegexCompileRegexCompileInLoopContextBackgroundContextValueMissingContextCancelContextLeakContextInStructContextNotFirstContextMisuseErrorIgnoredErrorCheckMissingPanicRecoverErrorStringFormatPanicRiskPanicInLibraryAIBullshitConcurrencyAIReflectionOverkillAIPatternAbuseAIEnterpriseHelloWorldAICaptainObviousAIOverengineeredSimpleAIGeneratedCommentAIUnnecessaryComplexityAIOverAbstractionAIVariableAIErrorHandlingAIStructureAIRepetitionAIFactorySimpleAIRedundantElseAIGoroutineOverkillAIUnnecessaryReflectionAIUnnecessaryInterfaceHighGCPressureDetectedFrequentAllocationDetectedLargeHeapAllocDetectedPointerHeavyStructDetectedSyncPoolOpportunitySyncPoolPutMissingSyncPoolTypeAssertSyncPoolMisuseAPIMisuseWGMisusePprofInProdPprofNilWriterDebugInProdWaitgroupAddInGoroutineContextBackgroundMisuseSleepInLoopSprintfConcatenationLogInHotPathRecoverWithoutDeferJSONMarshalInLoopRegexCompileInFuncMutexByValuePrivacyHardcodedSecretPrivacyAWSKeyPrivacyJWTTokenPrivacyEmailPIIPrivacySSNPIIPrivacyCreditCardPIIPrivacyLoggingSensitivePrivacyPrintingSensitivePrivacyExposedFieldPrivacyUnencryptedDBWritePrivacyDirectInputToDBDependencyDeprecatedDependencyVulnerableDependencyOutdatedDependencyCGODependencyUnsafeDependencyInternalDependencyIndirectDependencyLocalReplaceDependencyNoChecksumDependencyEmptyChecksumDependencyVersionConflictMissingTestMissingExampleMissingBenchmarkUntestedExportUntestedTypeUntestedErrorUntestedConcurrencyUntestedIOFunctionWeakCryptoInsecureRandomWeakHashJSONInLoopXMLInLoopSerializationInLoopUnbufferedIOSmallBufferMissingBufferingNetworkInLoopDNSLookupInLoopNoConnectionPoolCGOCallCGOInLoopCGOMemoryLeakCPUIntensiveUnnecessaryCopyBoundsCheckEliminationInefficientAlgorithmCacheUnfriendlyHighComplexityO2HighComplexityO3PreventsInliningExpensiveOpInHotPathModuloPowerOfTwoMagicNumberUselessConditionEmptyElseSleepInsteadOfSyncConsoleLogDebuggingHardcodedConfigGlobalVariablePointerToSliceStructLayoutUnoptimizedStructLargePaddingStructFieldAlignmentCacheFalseSharingCacheLineWasteCacheLineAlignmentOversizedTypeUnspecificIntTypeSoAPatternNestedRangeCacheMapRangeCacheTypeMax"
-const _IssueTypeLowerName = "nestedloopallocinloopappendinloopdeferinloopregexinlooptimeinloopsqlinloopdnsinloopreflectioninloopcpuintensiveloopmemoryleakglobalvarlargeallocationhighgcpressurefrequentallocationlargeheapallocpointerheavystructmissingdefermissingcloseslicecapacityslicecopysliceappendslicerangecopysliceappendinloopslicepreallocmapcapacitymapclearmappreallocstringconcatstringbuilderstringinefficientdeferinshortfuncdeferoverheadunnecessarydeferdeferatendmultipledefersdeferinhotpathdeferlargecaptureunnecessarymutexdefermissingdeferunlockmissingdefercloseraceconditionraceconditionglobalunsyncmapaccessraceclosuregoroutineleakunbufferedchannelgoroutineoverheadsyncmutexvaluewaitgroupmisuseraceindeferatomicmisusegoroutinenorecovergoroutinecapturesloopwaitgroupaddinloopwaitgroupwaitbeforestartmutexforreadonlyselectwithsinglecasebusywaitcontextbackgroundingoroutinegoroutineperrequestnoworkerpoolunbufferedsignalchanselectdefaultchannelsizerangeoverchannelchanneldeadlockchannelmultipleclosechannelsendonclosedhttpnotimeouthttpnoclosehttpdefaultclienthttpnocontextkeepalivemissingconnectionpoolnoreuseconnectionhttpnoconnectionreusenopreparedstmtmissingdbclosesqlnplusonereflectioninterfaceallocationemptyinterfaceinterfacepollutiontimeafterleaktimeformattimenowinloopregexcompileregexcompileinloopcontextbackgroundcontextvaluemissingcontextcancelcontextleakcontextinstructcontextnotfirstcontextmisuseerrorignorederrorcheckmissingpanicrecovererrorstringformatpanicriskpanicinlibraryaibullshitconcurrencyaireflectionoverkillaipatternabuseaienterprisehelloworldaicaptainobviousaioverengineeredsimpleaigeneratedcommentaiunnecessarycomplexityaioverabstractionaivariableaierrorhandlingaistructureairepetitionaifactorysimpleairedundantelseaigoroutineoverkillaiunnecessaryreflectionaiunnecessaryinterfacehighgcpressuredetectedfrequentallocationdetectedlargeheapallocdetectedpointerheavystructdetectedsyncpoolopportunitysyncpoolputmissingsyncpooltypeassertsyncpoolmisuseapimisusewgmisusepprofinprodpprofnilwriterdebuginprodwaitgroupaddingoroutinecontextbackgroundmisusesleepinloopsprintfconcatenationloginhotpathrecoverwithoutdeferjsonmarshalinloopregexcompileinfuncmutexbyvalueprivacyhardcodedsecretprivacyawskeyprivacyjwttokenprivacyemailpiiprivacyssnpiiprivacycreditcardpiiprivacyloggingsensitiveprivacyprintingsensitiveprivacyexposedfieldprivacyunencrypteddbwriteprivacydirectinputtodbdependencydeprecateddependencyvulnerabledependencyoutdateddependencycgodependencyunsafedependencyinternaldependencyindirectdependencylocalreplacedependencynochecksumdependencyemptychecksumdependencyversionconflictmissingtestmissingexamplemissingbenchmarkuntestedexportuntestedtypeuntestederroruntestedconcurrencyuntestediofunctionweakcryptoinsecurerandomweakhashjsoninloopxmlinloopserializationinloopunbufferediosmallbuffermissingbufferingnetworkinloopdnslookupinloopnoconnectionpoolcgocallcgoinloopcgomemoryleakcpuintensiveunnecessarycopyboundscheckeliminationinefficientalgorithmcacheunfriendlyhighcomplexityo2highcomplexityo3preventsinliningexpensiveopinhotpathmodulopoweroftwomagicnumberuselessconditionemptyelsesleepinsteadofsyncconsolelogdebugginghardcodedconfigglobalvariablepointertoslicestructlayoutunoptimizedstructlargepaddingstructfieldalignmentcachefalsesharingcachelinewastecachelinealignmentoversizedtypeunspecificinttypesoapatternnestedrangecachemaprangecachetypemax"
+const _IssueTypeName = "NestedLoopAllocInLoopAppendInLoopDeferInLoopRegexInLoopTimeInLoopSQLInLoopDNSInLoopReflectionInLoopCPUIntensiveLoopMemoryLeakGlobalVarLargeAllocationHighGCPressureFrequentAllocationLargeHeapAllocPointerHeavyStructMissingDeferMissingCloseSliceCapacitySliceCopySliceAppendSliceRangeCopySliceAppendInLoopSlicePreallocMapCapacityMapClearMapPreallocStringConcatStringBuilderStringInefficientDeferInShortFuncDeferOverheadUnnecessaryDeferDeferAtEndMultipleDefersDeferInHotPathDeferLargeCaptureUnnecessaryMutexDeferMissingDeferUnlockMissingDeferCloseRaceConditionRaceConditionGlobalUnsyncMapAccessRaceClosureGoroutineLeakUnbufferedChannelGoroutineOverheadSyncMutexValueWaitgroupMisuseRaceInDeferAtomicMisuseGoroutineNoRecoverGoroutineCapturesLoopWaitGroupAddInLoopWaitGroupWaitBeforeStartMutexForReadOnlySelectWithSingleCaseBusyWaitContextBackgroundInGoroutineGoroutinePerRequestNoWorkerPoolUnbufferedSignalChanSelectDefaultChannelSizeRangeOverChannelChannelDeadlockChannelMultipleCloseChannelSendOnClosedHTTPNoTimeoutHTTPNoCloseHTTPDefaultClientHTTPNoContextKeepaliveMissingConnectionPoolNoReuseConnectionHTTPNoConnectionReuseNoPreparedStmtMissingDBCloseSQLNPlusOneReflectionInterfaceAllocationEmptyInterfaceInterfacePollutionTimeAfterLeakTimeFormatTimeNowInLoopRegexCompileRegexCompileInLoopContextBackgroundContextValueMissingContextCancelContextLeakContextInStructContextNotFirstContextMisuseErrorIgnoredErrorCheckMissingPanicRecoverErrorStringFormatPanicRiskPanicInLibraryAIBullshitConcurrencyAIReflectionOverkillAIPatternAbuseAIEnterpriseHelloWorldAICaptainObviousAIOverengineeredSimpleAIGeneratedCommentAIUnnecessaryComplexityAIOverAbstractionAIVariableAIErrorHandlingAIStructureAIRepetitionAIFactorySimpleAIRedundantElseAIGoroutineOverkillAIUnnecessaryReflectionAIUnnecessaryInterfaceHighGCPressureDetectedFrequentAllocationDetectedLargeHeapAllocDetectedPointerHeavyStructDetectedSyncPoolOpportunitySyncPoolPutMissingSyncPoolTypeAssertSyncPoolMisuseAPIMisuseWGMisusePprofInProdPprofNilWriterDebugInProdWaitgroupAddInGoroutineContextBackgroundMisuseSleepInLoopSprintfConcatenationLogInHotPathRecoverWithoutDeferJSONMarshalInLoopRegexCompileInFuncMutexByValuePrivacyHardcodedSecretPrivacyAWSKeyPrivacyJWTTokenPrivacyEmailPIIPrivacySSNPIIPrivacyCreditCardPIIPrivacyLoggingSensitivePrivacyPrintingSensitivePrivacyExposedFieldPrivacyUnencryptedDBWritePrivacyDirectInputToDBDependencyDeprecatedDependencyVulnerableDependencyOutdatedDependencyCGODependencyUnsafeDependencyInternalDependencyIndirectDependencyLocalReplaceDependencyNoChecksumDependencyEmptyChecksumDependencyVersionConflictMissingTestMissingExampleMissingBenchmarkUntestedExportUntestedTypeUntestedErrorUntestedConcurrencyUntestedIOFunctionWeakCryptoInsecureRandomWeakHashJSONInLoopXMLInLoopSerializationInLoopUnbufferedIOSmallBufferMissingBufferingNetworkInLoopDNSLookupInLoopNoConnectionPoolCGOCallCGOInLoopCGOMemoryLeakCPUIntensiveUnnecessaryCopyBoundsCheckEliminationInefficientAlgorithmCacheUnfriendlyHighComplexityO2HighComplexityO3PreventsInliningExpensiveOpInHotPathModuloPowerOfTwoMagicNumberUselessConditionEmptyElseSleepInsteadOfSyncConsoleLogDebuggingHardcodedConfigGlobalVariablePointerToSliceStructLayoutUnoptimizedStructLargePaddingStructFieldAlignmentCacheFalseSharingCacheLineWasteCacheLineAlignmentOversizedTypeUnspecificIntTypeSoAPatternNestedRangeCacheMapRangeCacheStaleIgnoreDirectiveAnalysisTimeoutTypeMax"
+const _IssueTypeLowerName = "nestedloopallocinloopappendinloopdeferinloopregexinlooptimeinloopsqlinloopdnsinloopreflectioninloopcpuintensiveloopmemoryleakglobalvarlargeallocationhighgcpressurefrequentallocationlargeheapallocpointerheavystructmissingdefermissingcloseslicecapacityslicecopysliceappendslicerangecopysliceappendinloopslicepreallocmapcapacitymapclearmappreallocstringconcatstringbuilderstringinefficientdeferinshortfuncdeferoverheadunnecessarydeferdeferatendmultipledefersdeferinhotpathdeferlargecaptureunnecessarymutexdefermissingdeferunlockmissingdefercloseraceconditionraceconditionglobalunsyncmapaccessraceclosuregoroutineleakunbufferedchannelgoroutineoverheadsyncmutexvaluewaitgroupmisuseraceindeferatomicmisusegoroutinenorecovergoroutinecapturesloopwaitgroupaddinloopwaitgroupwaitbeforestartmutexforreadonlyselectwithsinglecasebusywaitcontextbackgroundingoroutinegoroutineperrequestnoworkerpoolunbufferedsignalchanselectdefaultchannelsizerangeoverchannelchanneldeadlockchannelmultipleclosechannelsendonclosedhttpnotimeouthttpnoclosehttpdefaultclienthttpnocontextkeepalivemissingconnectionpoolnoreuseconnectionhttpnoconnectionreusenopreparedstmtmissingdbclosesqlnplusonereflectioninterfaceallocationemptyinterfaceinterfacepollutiontimeafterleaktimeformattimenowinloopregexcompileregexcompileinloopcontextbackgroundcontextvaluemissingcontextcancelcontextleakcontextinstructcontextnotfirstcontextmisuseerrorignorederrorcheckmissingpanicrecovererrorstringformatpanicriskpanicinlibraryaibullshitconcurrencyaireflectionoverkillaipatternabuseaienterprisehelloworldaicaptainobviousaioverengineeredsimpleaigeneratedcommentaiunnecessarycomplexityaioverabstractionaivariableaierrorhandlingaistructureairepetitionaifactorysimpleairedundantelseaigoroutineoverkillaiunnecessaryreflectionaiunnecessaryinterfacehighgcpressuredetectedfrequentallocationdetectedlargeheapallocdetectedpointerheavystructdetectedsyncpoolopportunitysyncpoolputmissingsyncpooltypeassertsyncpoolmisuseapimisusewgmisusepprofinprodpprofnilwriterdebuginprodwaitgroupaddingoroutinecontextbackgroundmisusesleepinloopsprintfconcatenationloginhotpathrecoverwithoutdeferjsonmarshalinloopregexcompileinfuncmutexbyvalueprivacyhardcodedsecretprivacyawskeyprivacyjwttokenprivacyemailpiiprivacyssnpiiprivacycreditcardpiiprivacyloggingsensitiveprivacyprintingsensitiveprivacyexposedfieldprivacyunencrypteddbwriteprivacydirectinputtodbdependencydeprecateddependencyvulnerabledependencyoutdateddependencycgodependencyunsafedependencyinternaldependencyindirectdependencylocalreplacedependencynochecksumdependencyemptychecksumdependencyversionconflictmissingtestmissingexamplemissingbenchmarkuntestedexportuntestedtypeuntestederroruntestedconcurrencyuntestediofunctionweakcryptoinsecurerandomweakhashjsoninloopxmlinloopserializationinloopunbufferediosmallbuffermissingbufferingnetworkinloopdnslookupinloopnoconnectionpoolcgocallcgoinloopcgomemoryleakcpuintensiveunnecessarycopyboundscheckeliminationinefficientalgorithmcacheunfriendlyhighcomplexityo2highcomplexityo3preventsinliningexpensiveopinhotpathmodulopoweroftwomagicnumberuselessconditionemptyelsesleepinsteadofsyncconsolelogdebugginghardcodedconfigglobalvariablepointertoslicestructlayoutunoptimizedstructlargepaddingstructfieldalignmentcachefalsesharingcachelinewastecachelinealignmentoversizedtypeunspecificinttypesoapatternnestedrangecachemaprangecachestaleignoredirectiveanalysistimeouttypemax"
 
 var _IssueTypeMap = map[IssueType]string{
 	0:   _IssueTypeName[0:10],
@@ -227,7 +227,9 @@ var _IssueTypeMap = map[IssueType]string{
 	314: _IssueTypeName[3304:3314],
 	315: _IssueTypeName[3314:3330],
 	316: _IssueTypeName[3330:3343],
-	317: _IssueTypeName[3343:3350],
+	317: _IssueTypeName[3343:3363],
+	318: _IssueTypeName[3363:3378],
+	319: _IssueTypeName[3378:3385],
 }
 
 func (i IssueType) String() string {
@@ -457,10 +459,12 @@ func _IssueTypeNoOp() {
 	_ = x[IssueSoAPattern-(314)]
 	_ = x[IssueNestedRangeCache-(315)]
 	_ = x[IssueMapRangeCache-(316)]
-	_ = x[IssueTypeMax-(317)]
+	_ = x[IssueStaleIgnoreDirective-(317)]
+	_ = x[IssueAnalysisTimeout-(318)]
+	_ = x[IssueTypeMax-(319)]
 }
 
-var _IssueTypeValues = []IssueType{IssueNestedLoop, IssueAllocInLoop, IssueAppendInLoop, IssueDeferInLoop, IssueRegexInLoop, IssueTimeInLoop, IssueSQLInLoop, IssueDNSInLoop, IssueReflectionInLoop, IssueCPUIntensiveLoop, IssueMemoryLeak, IssueGlobalVar, IssueLargeAllocation, IssueHighGCPressure, IssueFrequentAllocation, IssueLargeHeapAlloc, IssuePointerHeavyStruct, IssueMissingDefer, IssueMissingClose, IssueSliceCapacity, IssueSliceCopy, IssueSliceAppend, IssueSliceRangeCopy, IssueSliceAppendInLoop, IssueSlicePrealloc, IssueMapCapacity, IssueMapClear, IssueMapPrealloc, IssueStringConcat, IssueStringBuilder, IssueStringInefficient, IssueDeferInShortFunc, IssueDeferOverhead, IssueUnnecessaryDefer, IssueDeferAtEnd, IssueMultipleDefers, IssueDeferInHotPath, IssueDeferLargeCapture, IssueUnnecessaryMutexDefer, IssueMissingDeferUnlock, IssueMissingDeferClose, IssueRaceCondition, IssueRaceConditionGlobal, IssueUnsyncMapAccess, IssueRaceClosure, IssueGoroutineLeak, IssueUnbufferedChannel, IssueGoroutineOverhead, IssueSyncMutexValue, IssueWaitgroupMisuse, IssueRaceInDefer, IssueAtomicMisuse, IssueGoroutineNoRecover, IssueGoroutineCapturesLoop, IssueWaitGroupAddInLoop, IssueWaitGroupWaitBeforeStart, IssueMutexForReadOnly, IssueSelectWithSingleCase, IssueBusyWait, IssueContextBackgroundInGoroutine, IssueGoroutinePerRequest, IssueNoWorkerPool, IssueUnbufferedSignalChan, IssueSelectDefault, IssueChannelSize, IssueRangeOverChannel, IssueChannelDeadlock, IssueChannelMultipleClose, IssueChannelSendOnClosed, IssueHTTPNoTimeout, IssueHTTPNoClose, IssueHTTPDefaultClient, IssueHTTPNoContext, IssueKeepaliveMissing, IssueConnectionPool, IssueNoReuseConnection, IssueHTTPNoConnectionReuse, IssueNoPreparedStmt, IssueMissingDBClose, IssueSQLNPlusOne, IssueReflection, IssueInterfaceAllocation, IssueEmptyInterface, IssueInterfacePollution, IssueTimeAfterLeak, IssueTimeFormat, IssueTimeNowInLoop, IssueRegexCompile, IssueRegexCompileInLoop, IssueContextBackground, IssueContextValue, IssueMissingContextCancel, IssueContextLeak, IssueContextInStruct, IssueContextNotFirst, IssueContextMisuse, IssueErrorIgnored, IssueErrorCheckMissing, IssuePanicRecover, IssueErrorStringFormat, IssuePanicRisk, IssuePanicInLibrary, IssueAIBullshitConcurrency, IssueAIReflectionOverkill, IssueAIPatternAbuse, IssueAIEnterpriseHelloWorld, IssueAICaptainObvious, IssueAIOverengineeredSimple, IssueAIGeneratedComment, IssueAIUnnecessaryComplexity, IssueAIOverAbstraction, IssueAIVariable, IssueAIErrorHandling, IssueAIStructure, IssueAIRepetition, IssueAIFactorySimple, IssueAIRedundantElse, IssueAIGoroutineOverkill, IssueAIUnnecessaryReflection, IssueAIUnnecessaryInterface, IssueHighGCPressureDetected, IssueFrequentAllocationDetected, IssueLargeHeapAllocDetected, IssuePointerHeavyStructDetected, IssueSyncPoolOpportunity, IssueSyncPoolPutMissing, IssueSyncPoolTypeAssert, IssueSyncPoolMisuse, IssueAPIMisuse, IssueWGMisuse, IssuePprofInProd, IssuePprofNilWriter, IssueDebugInProd, IssueWaitgroupAddInGoroutine, IssueContextBackgroundMisuse, IssueSleepInLoop, IssueSprintfConcatenation, IssueLogInHotPath, IssueRecoverWithoutDefer, IssueJSONMarshalInLoop, IssueRegexCompileInFunc, IssueMutexByValue, IssuePrivacyHardcodedSecret, IssuePrivacyAWSKey, IssuePrivacyJWTToken, IssuePrivacyEmailPII, IssuePrivacySSNPII, IssuePrivacyCreditCardPII, IssuePrivacyLoggingSensitive, IssuePrivacyPrintingSensitive, IssuePrivacyExposedField, IssuePrivacyUnencryptedDBWrite, IssuePrivacyDirectInputToDB, IssueDependencyDeprecated, IssueDependencyVulnerable, IssueDependencyOutdated, IssueDependencyCGO, IssueDependencyUnsafe, IssueDependencyInternal, IssueDependencyIndirect, IssueDependencyLocalReplace, IssueDependencyNoChecksum, IssueDependencyEmptyChecksum, IssueDependencyVersionConflict, IssueMissingTest, IssueMissingExample, IssueMissingBenchmark, IssueUntestedExport, IssueUntestedType, IssueUntestedError, IssueUntestedConcurrency, IssueUntestedIOFunction, IssueWeakCrypto, IssueInsecureRandom, IssueWeakHash, IssueJSONInLoop, IssueXMLInLoop, IssueSerializationInLoop, IssueUnbufferedIO, IssueSmallBuffer, IssueMissingBuffering, IssueNetworkInLoop, IssueDNSLookupInLoop, IssueNoConnectionPool, IssueCGOCall, IssueCGOInLoop, IssueCGOMemoryLeak, IssueCPUIntensive, IssueUnnecessaryCopy, IssueBoundsCheckElimination, IssueInefficientAlgorithm, IssueCacheUnfriendly, IssueHighComplexityO2, IssueHighComplexityO3, IssuePreventsInlining, IssueExpensiveOpInHotPath, IssueModuloPowerOfTwo, IssueMagicNumber, IssueUselessCondition, IssueEmptyElse, IssueSleepInsteadOfSync, IssueConsoleLogDebugging, IssueHardcodedConfig, IssueGlobalVariable, IssuePointerToSlice, IssueStructLayoutUnoptimized, IssueStructLargePadding, IssueStructFieldAlignment, IssueCacheFalseSharing, IssueCacheLineWaste, IssueCacheLineAlignment, IssueOversizedType, IssueUnspecificIntType, IssueSoAPattern, IssueNestedRangeCache, IssueMapRangeCache, IssueTypeMax}
+var _IssueTypeValues = []IssueType{IssueNestedLoop, IssueAllocInLoop, IssueAppendInLoop, IssueDeferInLoop, IssueRegexInLoop, IssueTimeInLoop, IssueSQLInLoop, IssueDNSInLoop, IssueReflectionInLoop, IssueCPUIntensiveLoop, IssueMemoryLeak, IssueGlobalVar, IssueLargeAllocation, IssueHighGCPressure, IssueFrequentAllocation, IssueLargeHeapAlloc, IssuePointerHeavyStruct, IssueMissingDefer, IssueMissingClose, IssueSliceCapacity, IssueSliceCopy, IssueSliceAppend, IssueSliceRangeCopy, IssueSliceAppendInLoop, IssueSlicePrealloc, IssueMapCapacity, IssueMapClear, IssueMapPrealloc, IssueStringConcat, IssueStringBuilder, IssueStringInefficient, IssueDeferInShortFunc, IssueDeferOverhead, IssueUnnecessaryDefer, IssueDeferAtEnd, IssueMultipleDefers, IssueDeferInHotPath, IssueDeferLargeCapture, IssueUnnecessaryMutexDefer, IssueMissingDeferUnlock, IssueMissingDeferClose, IssueRaceCondition, IssueRaceConditionGlobal, IssueUnsyncMapAccess, IssueRaceClosure, IssueGoroutineLeak, IssueUnbufferedChannel, IssueGoroutineOverhead, IssueSyncMutexValue, IssueWaitgroupMisuse, IssueRaceInDefer, IssueAtomicMisuse, IssueGoroutineNoRecover, IssueGoroutineCapturesLoop, IssueWaitGroupAddInLoop, IssueWaitGroupWaitBeforeStart, IssueMutexForReadOnly, IssueSelectWithSingleCase, IssueBusyWait, IssueContextBackgroundInGoroutine, IssueGoroutinePerRequest, IssueNoWorkerPool, IssueUnbufferedSignalChan, IssueSelectDefault, IssueChannelSize, IssueRangeOverChannel, IssueChannelDeadlock, IssueChannelMultipleClose, IssueChannelSendOnClosed, IssueHTTPNoTimeout, IssueHTTPNoClose, IssueHTTPDefaultClient, IssueHTTPNoContext, IssueKeepaliveMissing, IssueConnectionPool, IssueNoReuseConnection, IssueHTTPNoConnectionReuse, IssueNoPreparedStmt, IssueMissingDBClose, IssueSQLNPlusOne, IssueReflection, IssueInterfaceAllocation, IssueEmptyInterface, IssueInterfacePollution, IssueTimeAfterLeak, IssueTimeFormat, IssueTimeNowInLoop, IssueRegexCompile, IssueRegexCompileInLoop, IssueContextBackground, IssueContextValue, IssueMissingContextCancel, IssueContextLeak, IssueContextInStruct, IssueContextNotFirst, IssueContextMisuse, IssueErrorIgnored, IssueErrorCheckMissing, IssuePanicRecover, IssueErrorStringFormat, IssuePanicRisk, IssuePanicInLibrary, IssueAIBullshitConcurrency, IssueAIReflectionOverkill, IssueAIPatternAbuse, IssueAIEnterpriseHelloWorld, IssueAICaptainObvious, IssueAIOverengineeredSimple, IssueAIGeneratedComment, IssueAIUnnecessaryComplexity, IssueAIOverAbstraction, IssueAIVariable, IssueAIErrorHandling, IssueAIStructure, IssueAIRepetition, IssueAIFactorySimple, IssueAIRedundantElse, IssueAIGoroutineOverkill, IssueAIUnnecessaryReflection, IssueAIUnnecessaryInterface, IssueHighGCPressureDetected, IssueFrequentAllocationDetected, IssueLargeHeapAllocDetected, IssuePointerHeavyStructDetected, IssueSyncPoolOpportunity, IssueSyncPoolPutMissing, IssueSyncPoolTypeAssert, IssueSyncPoolMisuse, IssueAPIMisuse, IssueWGMisuse, IssuePprofInProd, IssuePprofNilWriter, IssueDebugInProd, IssueWaitgroupAddInGoroutine, IssueContextBackgroundMisuse, IssueSleepInLoop, IssueSprintfConcatenation, IssueLogInHotPath, IssueRecoverWithoutDefer, IssueJSONMarshalInLoop, IssueRegexCompileInFunc, IssueMutexByValue, IssuePrivacyHardcodedSecret, IssuePrivacyAWSKey, IssuePrivacyJWTToken, IssuePrivacyEmailPII, IssuePrivacySSNPII, IssuePrivacyCreditCardPII, IssuePrivacyLoggingSensitive, IssuePrivacyPrintingSensitive, IssuePrivacyExposedField, IssuePrivacyUnencryptedDBWrite, IssuePrivacyDirectInputToDB, IssueDependencyDeprecated, IssueDependencyVulnerable, IssueDependencyOutdated, IssueDependencyCGO, IssueDependencyUnsafe, IssueDependencyInternal, IssueDependencyIndirect, IssueDependencyLocalReplace, IssueDependencyNoChecksum, IssueDependencyEmptyChecksum, IssueDependencyVersionConflict, IssueMissingTest, IssueMissingExample, IssueMissingBenchmark, IssueUntestedExport, IssueUntestedType, IssueUntestedError, IssueUntestedConcurrency, IssueUntestedIOFunction, IssueWeakCrypto, IssueInsecureRandom, IssueWeakHash, IssueJSONInLoop, IssueXMLInLoop, IssueSerializationInLoop, IssueUnbufferedIO, IssueSmallBuffer, IssueMissingBuffering, IssueNetworkInLoop, IssueDNSLookupInLoop, IssueNoConnectionPool, IssueCGOCall, IssueCGOInLoop, IssueCGOMemoryLeak, IssueCPUIntensive, IssueUnnecessaryCopy, IssueBoundsCheckElimination, IssueInefficientAlgorithm, IssueCacheUnfriendly, IssueHighComplexityO2, IssueHighComplexityO3, IssuePreventsInlining, IssueExpensiveOpInHotPath, IssueModuloPowerOfTwo, IssueMagicNumber, IssueUselessCondition, IssueEmptyElse, IssueSleepInsteadOfSync, IssueConsoleLogDebugging, IssueHardcodedConfig, IssueGlobalVariable, IssuePointerToSlice, IssueStructLayoutUnoptimized, IssueStructLargePadding, IssueStructFieldAlignment, IssueCacheFalseSharing, IssueCacheLineWaste, IssueCacheLineAlignment, IssueOversizedType, IssueUnspecificIntType, IssueSoAPattern, IssueNestedRangeCache, IssueMapRangeCache, IssueStaleIgnoreDirective, IssueAnalysisTimeout, IssueTypeMax}
 
 var _IssueTypeNameToValueMap = map[string]IssueType{
 	_IssueTypeName[0:10]:           IssueNestedLoop,
@@ -895,8 +899,12 @@ var _IssueTypeNameToValueMap = map[string]IssueType{
 	_IssueTypeLowerName[3314:3330]: IssueNestedRangeCache,
 	_IssueTypeName[3330:3343]:      IssueMapRangeCache,
 	_IssueTypeLowerName[3330:3343]: IssueMapRangeCache,
-	_IssueTypeName[3343:3350]:      IssueTypeMax,
-	_IssueTypeLowerName[3343:3350]: IssueTypeMax,
+	_IssueTypeName[3343:3363]:      IssueStaleIgnoreDirective,
+	_IssueTypeLowerName[3343:3363]: IssueStaleIgnoreDirective,
+	_IssueTypeName[3363:3378]:      IssueAnalysisTimeout,
+	_IssueTypeLowerName[3363:3378]: IssueAnalysisTimeout,
+	_IssueTypeName[3378:3385]:      IssueTypeMax,
+	_IssueTypeLowerName[3378:3385]: IssueTypeMax,
 }
 
 var _IssueTypeNames = []string{
@@ -1116,7 +1124,9 @@ var _IssueTypeNames = []string{
 	_IssueTypeName[3304:3314],
 	_IssueTypeName[3314:3330],
 	_IssueTypeName[3330:3343],
-	_IssueTypeName[3343:3350],
+	_IssueTypeName[3343:3363],
+	_IssueTypeName[3363:3378],
+	_IssueTypeName[3378:3385],
 }
 
 // IssueTypeString retrieves an enum value from the enum constants string name.
@@ -0,0 +1,186 @@
+package models
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Output groups of the built-in rules, in the order output lists them
+const (
+	GroupAI            = "AI Bullshit Detection"
+	GroupMemory        = "Memory & GC"
+	GroupConcurrency   = "Concurrency & Race Conditions"
+	GroupPerformance   = "Performance Hotspots"
+	GroupDefer         = "Defer Optimization"
+	GroupString        = "String Operations"
+	GroupReflection    = "Reflection & Interfaces"
+	GroupTimeRegex     = "Time & Regex"
+	GroupNetwork       = "Network & HTTP"
+	GroupDatabase      = "Database"
+	GroupErrorHandling = "Error Handling"
+	GroupCodeQuality   = "Code Quality"
+	GroupContextAPI    = "Context & API"
+	GroupOptimization  = "Optimization Opportunities"
+	GroupTestCoverage  = "Test Coverage"
+	GroupSecurity      = "Privacy & Security"
+	GroupDependencies  = "Dependencies"
+	GroupOther         = "Other"
+)
+
+var ruleGroups = []string{
+	GroupAI, GroupMemory, GroupConcurrency, GroupPerformance, GroupDefer, GroupString, GroupReflection,
+	GroupTimeRegex, GroupNetwork, GroupDatabase, GroupErrorHandling, GroupCodeQuality, GroupContextAPI,
+	GroupOptimization, GroupTestCoverage, GroupSecurity, GroupDependencies, GroupOther,
+}
+
+// RuleGroups returns the output groups of the built-in rules in the order
+// output lists them. Registered rules may name further groups.
+func RuleGroups() []string {
+	return slices.Clone(ruleGroups)
+}
+
+// Rule describes an issue type: how it is referenced, who reports it, how
+// output groups it and how it is documented. Built-in issue types are
+// described by builtinRules and the long-form docs in ruledocs; analyzer
+// plugins add theirs with RegisterRule.
+type Rule struct {
+	Type        IssueType
+	ID          string        // reference shown in output and accepted by ignore directives, e.g. "ACME-001"
+	Name        string        // issue type name, e.g. "DebugfInHandler"
+	Analyzer    AnalyzerType  // analyzer reporting it; AnalyzerPlugin for registered rules
+	Group       string        // output group; "Other" when empty
+	Severity    SeverityLevel // default severity of its issues
+	Tags        []string      // lowercase keywords for filtering, e.g. "loop", "allocation"
+	Description string        // what the rule finds and why it matters
+	Bad         string        // Go code the rule reports, if documented
+	Good        string        // the same code written the way the rule suggests
+	Fixable     bool          // issues may carry a SuggestedFix
+	Why         string        // long-form explanation shown by explain, if documented
+	Cost        string        // typical cost of an issue, if documented
+	Related     []IssueType   // rules worth reading together with this one
+}
+
+// builtinRuleIndex holds the built-in rules by issue type
+var builtinRuleIndex = make(map[IssueType]*Rule, len(builtinRules))
+
+func init() {
+	for i := range builtinRules {
+		rule := &builtinRules[i]
+		rule.ID = fmt.Sprintf("PVE-%03d", int(rule.Type))
+		rule.Name = rule.Type.String()
+		builtinRuleIndex[rule.Type] = rule
+		if err := loadRuleDoc(rule); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// firstRuleType is the IssueType of the first registered rule, well above
+// the built-in issue types
+const firstRuleType IssueType = 1000
+
+// rules holds the registered rules by issue type
+var rules = make(map[IssueType]Rule)
+
+// RegisterRule adds an issue type for rule and returns it. The type works
+// like a built-in one: it prints as rule.Name, its severity and ID come from
+// rule, and ignore directives and configuration accept its name and ID.
+// Type and Analyzer are set by RegisterRule.
+// RegisterRule is meant for package initialization and must not run
+// concurrently with analyses.
+func RegisterRule(rule Rule) (IssueType, error) {
+	if !token.IsIdentifier(rule.Name) {
+		return 0, fmt.Errorf("rule name %q is not an identifier", rule.Name)
+	}
+	if rule.ID == "" || strings.HasPrefix(strings.ToUpper(rule.ID), "PVE-") {
+		return 0, fmt.Errorf("rule %s: ID %q is empty or reserved", rule.Name, rule.ID)
+	}
+	if _, err := ParseIssueType(rule.Name); err == nil {
+		return 0, fmt.Errorf("rule %s is already registered", rule.Name)
+	}
+	if _, err := ParseIssueType(rule.ID); err == nil {
+		return 0, fmt.Errorf("rule ID %s is already registered", rule.ID)
+	}
+
+	t := firstRuleType + IssueType(len(rules))
+	rule.Type = t
+	rule.Analyzer = AnalyzerPlugin
+	if rule.Group == "" {
+		rule.Group = GroupOther
+	}
+	rules[t] = rule
+	// Extend the generated tables so String, IssueTypeString and the value
+	// lists know the new type
+	_IssueTypeMap[t] = rule.Name
+	_IssueTypeNameToValueMap[rule.Name] = t
+	_IssueTypeNameToValueMap[strings.ToLower(rule.Name)] = t
+	_IssueTypeValues = append(_IssueTypeValues, t)
+	_IssueTypeNames = append(_IssueTypeNames, rule.Name)
+	return t, nil
+}
+
+// MustRegisterRule is like RegisterRule but panics if the rule is invalid,
+// for use in package-level variable declarations
+func MustRegisterRule(rule Rule) IssueType {
+	t, err := RegisterRule(rule)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// RegisteredRule returns the rule registered for t, if t was added with
+// RegisterRule
+func RegisteredRule(t IssueType) (Rule, bool) {
+	rule, ok := rules[t]
+	return rule, ok
+}
+
+// Rule returns the description of the built-in or registered issue type i
+func (i IssueType) Rule() (Rule, bool) {
+	if rule, ok := builtinRuleIndex[i]; ok {
+		return *rule, true
+	}
+	return RegisteredRule(i)
+}
+
+// Fixable reports whether issues of type i may carry a SuggestedFix
+func (i IssueType) Fixable() bool {
+	rule, _ := i.Rule()
+	return rule.Fixable
+}
+
+// Rules returns the built-in rules in PVE order followed by the registered
+// rules in registration order. The Tags and Related slices are shared and
+// must not be modified.
+func Rules() []Rule {
+	all := slices.Clone(builtinRules)
+	for t := firstRuleType; t < firstRuleType+IssueType(len(rules)); t++ {
+		all = append(all, rules[t])
+	}
+	return all
+}
+
+// RulesOf returns the built-in issue types reported by analyzer a, in PVE
+// order
+func RulesOf(a AnalyzerType) []IssueType {
+	var types []IssueType
+	for _, rule := range builtinRules {
+		if rule.Analyzer == a {
+			types = append(types, rule.Type)
+		}
+	}
+	return types
+}
+
+// ruleByID returns the issue type of the rule registered with id
+func ruleByID(id string) (IssueType, bool) {
+	for t, rule := range rules {
+		if strings.EqualFold(rule.ID, id) {
+			return t, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// ruleDocs holds the long-form documentation of the built-in rules, one
+// ruledocs/<Name>.md per rule: the explanation, then "## Cost" and
+// "## Related" sections, the latter listing rule names as "- Name" items
+//
+//go:embed ruledocs/*.md
+var ruleDocs embed.FS
+
+// loadRuleDoc fills Why, Cost and Related of rule from its file in ruleDocs,
+// if there is one
+func loadRuleDoc(rule *Rule) error {
+	data, err := ruleDocs.ReadFile("ruledocs/" + rule.Name + ".md")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	why, rest, _ := strings.Cut(string(data), "\n## Cost\n")
+	cost, related, _ := strings.Cut(rest, "\n## Related\n")
+	rule.Why, rule.Cost = unwrapMarkdown(why), unwrapMarkdown(cost)
+	if rule.Why == "" || rule.Cost == "" {
+		return fmt.Errorf("ruledocs/%s.md: explanation or cost section is empty", rule.Name)
+	}
+	for line := range strings.Lines(related) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, ok := strings.CutPrefix(line, "- ")
+		if !ok {
+			return fmt.Errorf("ruledocs/%s.md: related rule %q is not a list item", rule.Name, line)
+		}
+		t, err := ParseIssueType(name)
+		if err != nil {
+			return fmt.Errorf("ruledocs/%s.md: %w", rule.Name, err)
+		}
+		rule.Related = append(rule.Related, t)
+	}
+	return nil
+}
+
+// unwrapMarkdown joins the lines of each paragraph of text, leaving
+// paragraphs separated by a blank line
+func unwrapMarkdown(text string) string {
+	var paragraphs []string
+	for _, p := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// WriteRulesMarkdown writes the reference of the built-in rules published as
+// PVE_CODES.md, grouped the way output groups issues
+func WriteRulesMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("# Performance Vulnerability Encyclopedia (PVE) Codes\n\n")
+	sb.WriteString("<!-- Generated from models/builtin_rules.go and models/ruledocs by `go test ./models -update`. Do not edit. -->\n\n")
+	sb.WriteString("Every issue aiBsCleaner reports carries the PVE ID of its rule. ")
+	sb.WriteString("Ignore directives and the configuration accept the ID or the rule name, e.g. ")
+	sb.WriteString("`// abc:ignore PVE-003` or `// abc:ignore DeferInLoop`. ")
+	sb.WriteString("`aibscleaner explain PVE-003` prints a rule in the terminal.\n\n")
+
+	byGroup := make(map[string][]*Rule, len(ruleGroups))
+	for i := range builtinRules {
+		rule := &builtinRules[i]
+		byGroup[rule.Group] = append(byGroup[rule.Group], rule)
+	}
+
+	sb.WriteString("| Group | Rules |\n|---|---|\n")
+	for _, group := range ruleGroups {
+		fmt.Fprintf(&sb, "| [%s](#%s) | %d |\n", group, markdownAnchor(group), len(byGroup[group]))
+	}
+
+	for _, group := range ruleGroups {
+		fmt.Fprintf(&sb, "\n## %s\n", group)
+		for _, rule := range byGroup[group] {
+			sb.WriteString("\n")
+			writeRuleMarkdown(&sb, rule)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeRuleMarkdown(sb *strings.Builder, rule *Rule) {
+	fmt.Fprintf(sb, "### %s: %s\n\n", rule.ID, rule.Name)
+	fmt.Fprintf(sb, "**Severity**: %s · **Analyzer**: %s", strings.ToUpper(rule.Severity.String()), rule.Analyzer)
+	if len(rule.Tags) > 0 {
+		fmt.Fprintf(sb, " · **Tags**: %s", strings.Join(rule.Tags, ", "))
+	}
+	if rule.Fixable {
+		sb.WriteString(" · **Fixable** with `--fix`")
+	}
+	fmt.Fprintf(sb, "\n\n%s\n", rule.Description)
+	if rule.Why != "" {
+		fmt.Fprintf(sb, "\n%s\n", rule.Why)
+	}
+	if rule.Bad != "" {
+		fmt.Fprintf(sb, "\nBad:\n\n```go\n%s\n```\n\nGood:\n\n```go\n%s\n```\n", rule.Bad, rule.Good)
+	}
+	if rule.Cost != "" {
+		fmt.Fprintf(sb, "\n**Typical cost**: %s\n", rule.Cost)
+	}
+	if len(rule.Related) > 0 {
+		links := make([]string, 0, len(rule.Related))
+		for _, t := range rule.Related {
+			related, _ := t.Rule()
+			links = append(links, fmt.Sprintf("[%s](#%s)", related.Name, markdownAnchor(related.ID+": "+related.Name)))
+		}
+		fmt.Fprintf(sb, "\n**Related**: %s\n", strings.Join(links, ", "))
+	}
+}
+
+// markdownAnchor returns the anchor GitHub generates for a heading
+func markdownAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			sb.WriteByte('-')
+		case r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
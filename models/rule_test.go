@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterRule(t *testing.T) {
+	rule := Rule{
+		ID:          "ACME-001",
+		Name:        "DebugfInHandler",
+		Severity:    SeverityLevelHigh,
+		Group:       "Logging",
+		Description: "Debug logging without a level check in request handlers",
+	}
+	issueType, err := RegisterRule(rule)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, issueType, firstRuleType)
+
+	require.Equal(t, "DebugfInHandler", issueType.String())
+	require.Equal(t, SeverityLevelHigh, issueType.Severity())
+	require.Equal(t, "ACME-001", issueType.GetPVEID())
+	require.Equal(t, AnalyzerPlugin, issueType.GetAnalyzer())
+	require.True(t, issueType.IsAIssueType())
+	require.Contains(t, IssueTypeStrings(), "DebugfInHandler")
+
+	for _, ref := range []string{"DebugfInHandler", "DEBUGF_IN_HANDLER", "acme-001"} {
+		parsed, err := ParseIssueType(ref)
+		require.NoError(t, err, ref)
+		require.Equal(t, issueType, parsed, ref)
+	}
+	_, err = ParseIssueType("PVE-1000")
+	require.Error(t, err, "PVE IDs only name built-in issue types")
+
+	registered, ok := RegisteredRule(issueType)
+	require.True(t, ok)
+	rule.Type, rule.Analyzer = issueType, AnalyzerPlugin
+	require.Equal(t, rule, registered)
+	described, ok := issueType.Rule()
+	require.True(t, ok)
+	require.Equal(t, registered, described)
+	require.Equal(t, registered, Rules()[len(Rules())-1])
+	_, ok = RegisteredRule(IssueNestedLoop)
+	require.False(t, ok)
+
+	invalid := []Rule{
+		{ID: "ACME-002", Name: "DebugfInHandler"},
+		{ID: "ACME-001", Name: "OtherRule"},
+		{ID: "PVE-900", Name: "ReservedID"},
+		{ID: "ACME-003", Name: "NestedLoop"},
+		{ID: "ACME-004", Name: "not a name"},
+		{Name: "NoID"},
+	}
+	for _, rule := range invalid {
+		_, err := RegisterRule(rule)
+		require.Error(t, err, "%+v", rule)
+	}
+}
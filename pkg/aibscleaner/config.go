@@ -0,0 +1,294 @@
+package aibscleaner
+
+import (
+	"strings"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/cache"
+)
+
+// DefaultContextLines is the number of source lines shown around an issue
+// when context output is enabled and no count is configured
+const DefaultContextLines = 2
+
+// Config represents the configuration for the analyzer
+type Config struct {
+	// Analyzer configuration
+	Analyzers struct {
+		Loop                AnalyzerConfig `yaml:"loop" json:"loop"`
+		DeferOptimization   AnalyzerConfig `yaml:"defer_optimization" json:"defer_optimization"`
+		Slice               AnalyzerConfig `yaml:"slice" json:"slice"`
+		Map                 AnalyzerConfig `yaml:"map" json:"map"`
+		Reflection          AnalyzerConfig `yaml:"reflection" json:"reflection"`
+		Goroutine           AnalyzerConfig `yaml:"goroutine" json:"goroutine"`
+		Interface           AnalyzerConfig `yaml:"interface" json:"interface"`
+		Regex               AnalyzerConfig `yaml:"regex" json:"regex"`
+		Time                AnalyzerConfig `yaml:"time" json:"time"`
+		MemoryLeak          AnalyzerConfig `yaml:"memory_leak" json:"memory_leak"`
+		Database            AnalyzerConfig `yaml:"database" json:"database"`
+		APIMisuse           AnalyzerConfig `yaml:"api_misuse" json:"api_misuse"`
+		AIBullshit          AnalyzerConfig `yaml:"ai_bullshit" json:"ai_bullshit"`
+		Channel             AnalyzerConfig `yaml:"channel" json:"channel"`
+		HTTPClient          AnalyzerConfig `yaml:"http_client" json:"http_client"`
+		Privacy             AnalyzerConfig `yaml:"privacy" json:"privacy"`
+		Context             AnalyzerConfig `yaml:"context" json:"context"`
+		RaceCondition       AnalyzerConfig `yaml:"race_condition" json:"race_condition"`
+		ErrorHandling       AnalyzerConfig `yaml:"error_handling" json:"error_handling"`
+		GCPressure          AnalyzerConfig `yaml:"gc_pressure" json:"gc_pressure"`
+		ConcurrencyPatterns AnalyzerConfig `yaml:"concurrency_patterns" json:"concurrency_patterns"`
+		CPUOptimization     AnalyzerConfig `yaml:"cpu_optimization" json:"cpu_optimization"`
+		NetworkPatterns     AnalyzerConfig `yaml:"network_patterns" json:"network_patterns"`
+		SyncPool            AnalyzerConfig `yaml:"sync_pool" json:"sync_pool"`
+		TestCoverage        AnalyzerConfig `yaml:"test_coverage" json:"test_coverage"`
+		Crypto              AnalyzerConfig `yaml:"crypto" json:"crypto"`
+		Serialization       AnalyzerConfig `yaml:"serialization" json:"serialization"`
+		IOBuffer            AnalyzerConfig `yaml:"io_buffer" json:"io_buffer"`
+		HTTPReuse           AnalyzerConfig `yaml:"http_reuse" json:"http_reuse"`
+		CGO                 AnalyzerConfig `yaml:"cgo" json:"cgo"`
+		String              AnalyzerConfig `yaml:"string" json:"string"`
+		Dependency          AnalyzerConfig `yaml:"dependency" json:"dependency"`
+		StructLayout        AnalyzerConfig `yaml:"struct_layout" json:"struct_layout"`
+		CPUCache            AnalyzerConfig `yaml:"cpu_cache" json:"cpu_cache"`
+		HotPath             AnalyzerConfig `yaml:"hot_path" json:"hot_path"`
+		BoundsCheck         AnalyzerConfig `yaml:"bounds_check" json:"bounds_check"`
+		// Analyzers added with analyzer.Register, by registered name
+		Plugins map[string]AnalyzerConfig `yaml:"plugins,omitempty" json:"plugins,omitempty"`
+	} `yaml:"analyzers" json:"analyzers"`
+
+	// Thresholds for various checks
+	Thresholds struct {
+		MaxLoopDepth      int `yaml:"max_loop_depth" json:"max_loop_depth"`
+		MaxComplexity     int `yaml:"max_complexity" json:"max_complexity"`
+		MaxFunctionLength int `yaml:"max_function_length" json:"max_function_length"`
+		MaxParameters     int `yaml:"max_parameters" json:"max_parameters"`
+		MaxReturnValues   int `yaml:"max_return_values" json:"max_return_values"`
+	} `yaml:"thresholds" json:"thresholds"`
+
+	// Path configuration
+	Paths struct {
+		Exclude []string `yaml:"exclude" json:"exclude"` // Paths to exclude from analysis
+		Include []string `yaml:"include" json:"include"` // Specific paths to include (if empty, all non-excluded paths)
+		// Rules suppressed for matching paths without excluding the files
+		IgnoreRules []cache.IgnoredRule `yaml:"ignore_rules,omitempty" json:"ignore_rules,omitempty"`
+	} `yaml:"paths" json:"paths"`
+
+	// Suppression configuration for inline ignore directives
+	Suppression struct {
+		RequireReason bool `yaml:"require_reason" json:"require_reason"` // Directives without "-- reason" have no effect
+	} `yaml:"suppression" json:"suppression"`
+
+	// Output configuration
+	Output struct {
+		Format       string `yaml:"format" json:"format"`               // "text" or "json"
+		ShowContext  bool   `yaml:"show_context" json:"show_context"`   // Show code context
+		ContextLines int    `yaml:"context_lines" json:"context_lines"` // Lines shown around the issue (default 2)
+		MaxIssues    int    `yaml:"max_issues" json:"max_issues"`       // Maximum issues to report (0 = unlimited)
+	} `yaml:"output" json:"output"`
+
+	// Declarative rules evaluated alongside the built-in analyzers
+	CustomRules []CustomRule `yaml:"custom_rules,omitempty" json:"custom_rules,omitempty"`
+}
+
+// AnalyzerConfig represents configuration for a single analyzer
+type AnalyzerConfig struct {
+	Enabled  bool     `yaml:"enabled" json:"enabled"`
+	Severity string   `yaml:"severity,omitempty" json:"severity,omitempty"` // Override default severity
+	Exclude  []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`   // Exclude patterns
+}
+
+// DefaultConfig returns the default configuration
+func DefaultConfig() *Config {
+	config := &Config{}
+
+	// Enable performance-focused analyzers by default
+	config.Analyzers.Loop.Enabled = true
+	config.Analyzers.DeferOptimization.Enabled = true
+	config.Analyzers.Slice.Enabled = true
+	config.Analyzers.Map.Enabled = true
+	config.Analyzers.Reflection.Enabled = true
+	config.Analyzers.Goroutine.Enabled = true
+	config.Analyzers.Interface.Enabled = true
+	config.Analyzers.Regex.Enabled = true
+	config.Analyzers.Time.Enabled = true
+	config.Analyzers.MemoryLeak.Enabled = true
+	config.Analyzers.Database.Enabled = true
+	config.Analyzers.APIMisuse.Enabled = true
+	config.Analyzers.AIBullshit.Enabled = true
+	config.Analyzers.Channel.Enabled = true
+	config.Analyzers.HTTPClient.Enabled = true
+	config.Analyzers.Privacy.Enabled = true
+	config.Analyzers.Context.Enabled = true
+	config.Analyzers.RaceCondition.Enabled = true
+	config.Analyzers.ErrorHandling.Enabled = true
+	config.Analyzers.GCPressure.Enabled = true
+	config.Analyzers.ConcurrencyPatterns.Enabled = true
+	config.Analyzers.CPUOptimization.Enabled = true
+	config.Analyzers.NetworkPatterns.Enabled = true
+	config.Analyzers.SyncPool.Enabled = true
+	config.Analyzers.TestCoverage.Enabled = false // Usually noisy
+	config.Analyzers.Crypto.Enabled = true
+	config.Analyzers.Serialization.Enabled = true
+	config.Analyzers.IOBuffer.Enabled = true
+	config.Analyzers.HTTPReuse.Enabled = true
+	config.Analyzers.CGO.Enabled = true
+	config.Analyzers.String.Enabled = true
+	config.Analyzers.Dependency.Enabled = true
+	config.Analyzers.StructLayout.Enabled = true
+	config.Analyzers.CPUCache.Enabled = true
+	config.Analyzers.HotPath.Enabled = true
+	config.Analyzers.BoundsCheck.Enabled = false // Builds every package with the compiler
+
+	// Set default thresholds
+	config.Thresholds.MaxLoopDepth = 3
+	config.Thresholds.MaxComplexity = 10
+	config.Thresholds.MaxFunctionLength = 50
+	config.Thresholds.MaxParameters = 5
+	config.Thresholds.MaxReturnValues = 3
+
+	// Set default paths to exclude
+	config.Paths.Exclude = []string{
+		"examples",
+		"vendor",
+		".git",
+		"node_modules",
+		"testdata",
+		"test_data",
+		"mocks",
+		"_test.go",
+	}
+
+	// Set default output
+	config.Output.Format = "text"
+	config.Output.ShowContext = false
+	config.Output.ContextLines = DefaultContextLines
+	config.Output.MaxIssues = 0
+
+	return config
+}
+
+// GetAnalyzerConfig returns config for a specific analyzer
+func (c *Config) GetAnalyzerConfig(analyzerName string) AnalyzerConfig {
+	analyzerConfigMap := map[string]AnalyzerConfig{
+		"loop":                c.Analyzers.Loop,
+		"deferoptimization":   c.Analyzers.DeferOptimization,
+		"slice":               c.Analyzers.Slice,
+		"map":                 c.Analyzers.Map,
+		"reflection":          c.Analyzers.Reflection,
+		"goroutine":           c.Analyzers.Goroutine,
+		"interface":           c.Analyzers.Interface,
+		"regex":               c.Analyzers.Regex,
+		"time":                c.Analyzers.Time,
+		"memoryleak":          c.Analyzers.MemoryLeak,
+		"database":            c.Analyzers.Database,
+		"apimisuse":           c.Analyzers.APIMisuse,
+		"aibullshit":          c.Analyzers.AIBullshit,
+		"channel":             c.Analyzers.Channel,
+		"httpclient":          c.Analyzers.HTTPClient,
+		"privacy":             c.Analyzers.Privacy,
+		"context":             c.Analyzers.Context,
+		"racecondition":       c.Analyzers.RaceCondition,
+		"errorhandling":       c.Analyzers.ErrorHandling,
+		"gcpressure":          c.Analyzers.GCPressure,
+		"concurrencypatterns": c.Analyzers.ConcurrencyPatterns,
+		"cpuoptimization":     c.Analyzers.CPUOptimization,
+		"networkpatterns":     c.Analyzers.NetworkPatterns,
+		"syncpool":            c.Analyzers.SyncPool,
+		"testcoverage":        c.Analyzers.TestCoverage,
+		"crypto":              c.Analyzers.Crypto,
+		"serialization":       c.Analyzers.Serialization,
+		"iobuffer":            c.Analyzers.IOBuffer,
+		"httpreuse":           c.Analyzers.HTTPReuse,
+		"cgo":                 c.Analyzers.CGO,
+		"string":              c.Analyzers.String,
+		"dependency":          c.Analyzers.Dependency,
+		"structlayout":        c.Analyzers.StructLayout,
+		"cpucache":            c.Analyzers.CPUCache,
+		"hotpath":             c.Analyzers.HotPath,
+		"boundscheck":         c.Analyzers.BoundsCheck,
+	}
+
+	if cfg, ok := analyzerConfigMap[strings.ToLower(analyzerName)]; ok {
+		return cfg
+	}
+	for _, plugin := range analyzer.Plugins() {
+		if !strings.EqualFold(plugin.Name, analyzerName) {
+			continue
+		}
+		for name, cfg := range c.Analyzers.Plugins {
+			if strings.EqualFold(name, plugin.Name) {
+				return cfg
+			}
+		}
+		return AnalyzerConfig{Enabled: !plugin.Disabled}
+	}
+	return AnalyzerConfig{Enabled: true}
+}
+
+// EnabledAnalyzers returns the names of the analyzers enabled in c in the form
+// accepted by analyzer.Analyze, or nil (run all) when none are enabled
+func (c *Config) EnabledAnalyzers() map[string]bool {
+	if c == nil {
+		return nil // nil means run all
+	}
+
+	enabledAnalyzers := make(map[string]bool)
+	for configName, analyzerName := range analyzerMapping() {
+		if c.GetAnalyzerConfig(configName).Enabled {
+			enabledAnalyzers[analyzerName] = true
+		}
+	}
+
+	// If no analyzers enabled (shouldn't happen with default config), run all
+	if len(enabledAnalyzers) == 0 {
+		return nil
+	}
+
+	for _, plugin := range analyzer.Plugins() {
+		if c.GetAnalyzerConfig(plugin.Name).Enabled {
+			enabledAnalyzers[plugin.Name] = true
+		}
+	}
+	if len(c.CustomRules) > 0 {
+		enabledAnalyzers[analyzer.CustomRulesAnalyzerName] = true
+	}
+	return enabledAnalyzers
+}
+
+// analyzerMapping returns the mapping between config names and analyzer names
+func analyzerMapping() map[string]string {
+	return map[string]string{
+		"loop":                "loop",
+		"deferoptimization":   "deferoptimization",
+		"slice":               "slice",
+		"map":                 "map",
+		"reflection":          "reflection",
+		"interface":           "interface",
+		"regex":               "regex",
+		"time":                "time",
+		"memoryleak":          "memoryleak",
+		"database":            "database",
+		"apimisuse":           "apimisuse",
+		"aibullshit":          "aibullshit",
+		"goroutine":           "goroutine",
+		"channel":             "channel",
+		"httpclient":          "httpclient",
+		"context":             "context",
+		"racecondition":       "racecondition",
+		"concurrencypatterns": "concurrencypatterns",
+		"networkpatterns":     "networkpatterns",
+		"cpuoptimization":     "cpuoptimization",
+		"gcpressure":          "gcpressure",
+		"syncpool":            "syncpool",
+		"cgo":                 "cgo",
+		"serialization":       "serialization",
+		"crypto":              "crypto",
+		"httpreuse":           "httpreuse",
+		"iobuffer":            "iobuffer",
+		"privacy":             "privacy",
+		"testcoverage":        "testcoverage",
+		"structlayout":        "structlayout",
+		"cpucache":            "cpucache",
+		"hotpath":             "hotpath",
+		"boundscheck":         "bce",
+	}
+}
@@ -0,0 +1,23 @@
+package aibscleaner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnabledAnalyzersUsesConfig(t *testing.T) {
+	cfg := &Config{}
+	cfg.Analyzers.Loop.Enabled = true
+	cfg.Analyzers.Map.Enabled = false
+
+	enabled := cfg.EnabledAnalyzers()
+	require.NotNil(t, enabled)
+	require.True(t, enabled["loop"])
+	require.False(t, enabled["map"])
+}
+
+func TestEnabledAnalyzersReturnsNilWhenNoneEnabled(t *testing.T) {
+	require.Nil(t, (&Config{}).EnabledAnalyzers())
+	require.Nil(t, (*Config)(nil).EnabledAnalyzers())
+}
@@ -0,0 +1,125 @@
+package aibscleaner
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// CustomRule is a rule from the custom_rules configuration section. It
+// reports calls of one function or method, optionally only in some contexts:
+//
+//	custom_rules:
+//	  - id: ACME-010
+//	    name: QueryWithoutClose
+//	    call: database/sql.DB.Query
+//	    missing_call_in_same_func: database/sql.Rows.Close
+//	    message: rows are never closed
+//	    severity: high
+type CustomRule struct {
+	ID   string `yaml:"id" json:"id"`     // reference accepted by ignore directives, e.g. "ACME-010"
+	Name string `yaml:"name" json:"name"` // issue type name, e.g. "QueryWithoutClose"
+	// Call is the function or method reported, as "path.Func" or
+	// "path.Type.Method", e.g. "net/http.Get" or "database/sql.DB.Query"
+	Call string `yaml:"call" json:"call"`
+
+	InLoop                bool   `yaml:"in_loop,omitempty" json:"in_loop,omitempty"`
+	InGoroutine           bool   `yaml:"in_goroutine,omitempty" json:"in_goroutine,omitempty"`
+	InHandler             bool   `yaml:"in_handler,omitempty" json:"in_handler,omitempty"`
+	MissingCallInSameFunc string `yaml:"missing_call_in_same_func,omitempty" json:"missing_call_in_same_func,omitempty"`
+
+	Message    string `yaml:"message" json:"message"`
+	Severity   string `yaml:"severity,omitempty" json:"severity,omitempty"` // low, medium (default) or high
+	Suggestion string `yaml:"suggestion,omitempty" json:"suggestion,omitempty"`
+	Group      string `yaml:"group,omitempty" json:"group,omitempty"` // output group; "Custom" when empty
+}
+
+// customRuleGroup is the output group of custom rules without one
+const customRuleGroup = "Custom"
+
+// customRulesMu serializes the registration of custom rule issue types
+var customRulesMu sync.Mutex
+
+// CompileCustomRules validates the custom rules of c and returns them in the
+// form the analyzer evaluates. The issue type of each rule is registered on
+// first use; compiling the same rule again reuses it.
+func (c *Config) CompileCustomRules() ([]analyzer.CustomRule, error) {
+	if c == nil || len(c.CustomRules) == 0 {
+		return nil, nil
+	}
+
+	customRulesMu.Lock()
+	defer customRulesMu.Unlock()
+
+	rules := make([]analyzer.CustomRule, 0, len(c.CustomRules))
+	for i, cfg := range c.CustomRules {
+		rule, err := cfg.compile()
+		if err != nil {
+			return nil, fmt.Errorf("custom_rules[%d]: %w", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (cfg CustomRule) compile() (analyzer.CustomRule, error) {
+	call, err := analyzer.ParseCallPattern(cfg.Call)
+	if err != nil {
+		return analyzer.CustomRule{}, err
+	}
+	rule := analyzer.CustomRule{
+		Call:        call,
+		InLoop:      cfg.InLoop,
+		InGoroutine: cfg.InGoroutine,
+		InHandler:   cfg.InHandler,
+		Message:     cfg.Message,
+		Suggestion:  cfg.Suggestion,
+	}
+	if cfg.MissingCallInSameFunc != "" {
+		missing, err := analyzer.ParseCallPattern(cfg.MissingCallInSameFunc)
+		if err != nil {
+			return analyzer.CustomRule{}, fmt.Errorf("missing_call_in_same_func: %w", err)
+		}
+		rule.MissingCall = &missing
+	}
+
+	severity := models.SeverityLevelMedium
+	if cfg.Severity != "" {
+		if severity, err = models.SeverityLevelString(cfg.Severity); err != nil {
+			return analyzer.CustomRule{}, fmt.Errorf("rule %s: unknown severity %q", cfg.Name, cfg.Severity)
+		}
+	}
+	group := cfg.Group
+	if group == "" {
+		group = customRuleGroup
+	}
+
+	rule.Type, err = registerCustomRule(models.Rule{
+		ID:          cfg.ID,
+		Name:        cfg.Name,
+		Severity:    severity,
+		Group:       group,
+		Description: cfg.Message,
+	})
+	return rule, err
+}
+
+// registerCustomRule returns the issue type of rule, registering it unless
+// an identical rule already was, as happens when a configuration is loaded
+// again
+func registerCustomRule(rule models.Rule) (models.IssueType, error) {
+	if t, err := models.ParseIssueType(rule.ID); err == nil {
+		if registered, ok := models.RegisteredRule(t); ok && sameCustomRule(registered, rule) {
+			return t, nil
+		}
+	}
+	return models.RegisterRule(rule)
+}
+
+// sameCustomRule reports whether registered was registered for rule
+func sameCustomRule(registered, rule models.Rule) bool {
+	return registered.ID == rule.ID && registered.Name == rule.Name && registered.Severity == rule.Severity &&
+		registered.Group == rule.Group && registered.Description == rule.Description
+}
@@ -0,0 +1,85 @@
+package aibscleaner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const customRulesConfig = `
+custom_rules:
+  - id: TEST-201
+    name: TestSleepInLoop
+    call: time.Sleep
+    in_loop: true
+    message: Sleeping in a loop
+    severity: high
+    suggestion: Use a time.Ticker
+`
+
+func TestRunnerCustomRules(t *testing.T) {
+	cfg := DefaultConfig()
+	require.NoError(t, yaml.Unmarshal([]byte(customRulesConfig), cfg))
+	require.True(t, cfg.EnabledAnalyzers()["customrules"])
+
+	filename := filepath.Join(t.TempDir(), "main.go")
+	sources := map[string][]byte{filename: []byte(`package main
+
+import "time"
+
+func main() {
+	time.Sleep(time.Second)
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Second)
+	}
+}
+`)}
+	result, err := NewRunner(cfg, Options{}).AnalyzeSources(context.Background(), sources)
+	require.NoError(t, err)
+
+	var found []*models.Issue
+	for _, issue := range result.Issues {
+		if issue.Type.String() == "TestSleepInLoop" {
+			found = append(found, issue)
+		}
+	}
+	require.Len(t, found, 1)
+	require.Equal(t, 8, found[0].Line)
+	require.Equal(t, "TEST-201", found[0].Type.GetPVEID())
+	require.Equal(t, models.SeverityLevelHigh, found[0].Severity)
+	require.Equal(t, "Sleeping in a loop", found[0].Message)
+	require.Equal(t, "Use a time.Ticker", found[0].Suggestion)
+
+	rule, ok := models.RegisteredRule(found[0].Type)
+	require.True(t, ok)
+	require.Equal(t, customRuleGroup, rule.Group)
+
+	// Loading the same configuration again reuses the registered issue type
+	rules, err := cfg.CompileCustomRules()
+	require.NoError(t, err)
+	require.Equal(t, found[0].Type, rules[0].Type)
+}
+
+func TestCompileCustomRulesRejectsInvalidRules(t *testing.T) {
+	invalid := []CustomRule{
+		{ID: "TEST-202", Name: "TestNoCall"},
+		{ID: "TEST-203", Name: "TestBadCall", Call: "Sleep"},
+		{ID: "TEST-204", Name: "TestBadSeverity", Call: "time.Sleep", Severity: "urgent"},
+		{ID: "TEST-205", Name: "TestBadMissing", Call: "time.Sleep", MissingCallInSameFunc: "time."},
+		{ID: "PVE-001", Name: "TestReservedID", Call: "time.Sleep"},
+		{ID: "TEST-206", Name: "NestedLoop", Call: "time.Sleep"},
+	}
+	for _, rule := range invalid {
+		cfg := &Config{CustomRules: []CustomRule{rule}}
+		_, err := cfg.CompileCustomRules()
+		require.Error(t, err, "%+v", rule)
+
+		_, err = NewRunner(cfg, Options{}).AnalyzeSources(context.Background(), nil)
+		require.Error(t, err, "%+v", rule)
+	}
+}
@@ -0,0 +1,102 @@
+package aibscleaner
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// Kinds of JSON Lines records
+const (
+	RecordIssue   = "issue"
+	RecordSummary = "summary"
+)
+
+// IssueRecord is an issue line of a JSON Lines report
+type IssueRecord struct {
+	Kind string `json:"kind"`
+	ReportIssue
+}
+
+// SummaryRecord is the last line of a JSON Lines report
+type SummaryRecord struct {
+	Kind          string     `json:"kind"`
+	SchemaVersion string     `json:"schema_version"`
+	Target        string     `json:"target"`
+	Summary       Summary    `json:"summary"`
+	FileStats     []FileStat `json:"file_stats"`
+}
+
+// JSONLWriter writes a JSON Lines report: an IssueRecord per issue as issues
+// come in and a SummaryRecord on Close. Only the per-file counts are kept, so
+// memory does not grow with the number of issues.
+type JSONLWriter struct {
+	w       *bufio.Writer
+	enc     *json.Encoder
+	summary SummaryRecord
+	files   map[string]int
+}
+
+// NewJSONLWriter returns a JSONLWriter for the issues found under target
+func NewJSONLWriter(w io.Writer, target string) *JSONLWriter {
+	bw := bufio.NewWriter(w)
+	return &JSONLWriter{
+		w:   bw,
+		enc: json.NewEncoder(bw),
+		summary: SummaryRecord{
+			Kind:          RecordSummary,
+			SchemaVersion: SchemaVersion,
+			Target:        target,
+			FileStats:     []FileStat{},
+		},
+		files: make(map[string]int),
+	}
+}
+
+// WriteIssues writes a record per issue and flushes them, so that readers see
+// the issues of a file once it is done
+func (j *JSONLWriter) WriteIssues(issues []*models.Issue) error {
+	for _, issue := range issues {
+		record := IssueRecord{Kind: RecordIssue, ReportIssue: NewReportIssue(issue)}
+		if err := j.enc.Encode(record); err != nil {
+			return err
+		}
+		j.count(issue.Severity, record.File)
+	}
+	return j.w.Flush()
+}
+
+func (j *JSONLWriter) count(severity models.SeverityLevel, filename string) {
+	summary := &j.summary.Summary
+	summary.TotalIssues++
+	switch severity {
+	case models.SeverityLevelHigh:
+		summary.High++
+	case models.SeverityLevelMedium:
+		summary.Medium++
+	case models.SeverityLevelLow:
+		summary.Low++
+	}
+
+	if i, ok := j.files[filename]; ok {
+		j.summary.FileStats[i].Count++
+	} else {
+		j.files[filename] = len(j.summary.FileStats)
+		j.summary.FileStats = append(j.summary.FileStats, FileStat{Filename: filename, Count: 1})
+	}
+}
+
+// Summary returns the counts of the issues written so far
+func (j *JSONLWriter) Summary() Summary {
+	return j.summary.Summary
+}
+
+// Close writes the summary record. It does not close the underlying writer.
+func (j *JSONLWriter) Close() error {
+	if err := j.enc.Encode(j.summary); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
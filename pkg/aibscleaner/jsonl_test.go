@@ -0,0 +1,48 @@
+package aibscleaner
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func TestJSONLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf, ".")
+
+	require.NoError(t, w.WriteIssues([]*models.Issue{{
+		Type: models.IssueDeferInLoop, Severity: models.SeverityLevelHigh, Message: "Defer in loop",
+		Position: token.Position{Filename: "a.go", Line: 3, Column: 2},
+	}}))
+	// Written issues are flushed before the next file is done
+	require.Equal(t, 1, strings.Count(buf.String(), "\n"))
+
+	require.NoError(t, w.WriteIssues(nil))
+	require.NoError(t, w.WriteIssues([]*models.Issue{{
+		Type: models.IssueRegexCompileInLoop, Severity: models.SeverityLevelLow, Message: "Regex in loop",
+		Position: token.Position{Filename: "b.go", Line: 5, Column: 1},
+	}}))
+	require.NoError(t, w.Close())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	var issue IssueRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &issue))
+	require.Equal(t, RecordIssue, issue.Kind)
+	require.Equal(t, RuleName("DeferInLoop"), issue.Type)
+	require.Equal(t, "a.go", issue.File)
+
+	var summary SummaryRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &summary))
+	require.Equal(t, RecordSummary, summary.Kind)
+	require.Equal(t, SchemaVersion, summary.SchemaVersion)
+	require.Equal(t, Summary{TotalIssues: 2, High: 1, Low: 1}, summary.Summary)
+	require.Equal(t, []FileStat{{Filename: "a.go", Count: 1}, {Filename: "b.go", Count: 1}}, summary.FileStats)
+}
@@ -0,0 +1,154 @@
+package aibscleaner
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// SchemaVersion is the version of the JSON report format. It changes when a
+// field is removed, renamed or changes meaning; added fields keep it.
+const SchemaVersion = "2"
+
+// RuleName is the name of the rule an issue breaks, e.g. "DeferInLoop" or the
+// name of a custom or plugin rule
+type RuleName string
+
+// SeverityName is the severity of an issue: "HIGH", "MEDIUM" or "LOW"
+type SeverityName string
+
+// Report is the JSON report of a run
+type Report struct {
+	SchemaVersion string        `json:"schema_version"`
+	Target        string        `json:"target"`
+	Summary       Summary       `json:"summary"`
+	Issues        []ReportIssue `json:"issues"`
+	FileStats     []FileStat    `json:"file_stats"`
+}
+
+// Summary counts the issues of a report by severity
+type Summary struct {
+	TotalIssues int `json:"total_issues"`
+	High        int `json:"high"`
+	Medium      int `json:"medium"`
+	Low         int `json:"low"`
+}
+
+// FileStat is the number of issues found in a file
+type FileStat struct {
+	Filename string `json:"filename"`
+	Count    int    `json:"count"`
+}
+
+// ReportIssue is an issue as it appears in the JSON report. Lines and columns
+// are 1-based; the end position points just past the offending code.
+type ReportIssue struct {
+	ID         string               `json:"id,omitempty"`
+	File       string               `json:"file"`
+	Line       int                  `json:"line"`
+	Column     int                  `json:"column"`
+	EndLine    int                  `json:"end_line,omitempty"`
+	EndColumn  int                  `json:"end_column,omitempty"`
+	Type       RuleName             `json:"type"`
+	PVEID      string               `json:"pve_id"`
+	Severity   SeverityName         `json:"severity"`
+	Message    string               `json:"message"`
+	Suggestion string               `json:"suggestion,omitempty"`
+	WhyBad     string               `json:"why_bad,omitempty"`
+	Code       string               `json:"code,omitempty"`
+	CanBeFixed bool                 `json:"can_be_fixed"`
+	Fix        *models.SuggestedFix `json:"fix,omitempty"`
+	CreatedAt  *time.Time           `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time           `json:"updated_at,omitempty"`
+	FixedAt    *time.Time           `json:"fixed_at,omitempty"`
+	IgnoredAt  *time.Time           `json:"ignored_at,omitempty"`
+}
+
+// NewReport builds the JSON report of issues found under target
+func NewReport(target string, issues []*models.Issue) Report {
+	report := Report{
+		SchemaVersion: SchemaVersion,
+		Target:        target,
+		Issues:        make([]ReportIssue, 0, len(issues)),
+		FileStats:     []FileStat{},
+	}
+
+	fileIndex := make(map[string]int)
+	for _, issue := range issues {
+		entry := NewReportIssue(issue)
+		report.Issues = append(report.Issues, entry)
+
+		switch issue.Severity {
+		case models.SeverityLevelHigh:
+			report.Summary.High++
+		case models.SeverityLevelMedium:
+			report.Summary.Medium++
+		case models.SeverityLevelLow:
+			report.Summary.Low++
+		}
+
+		if i, ok := fileIndex[entry.File]; ok {
+			report.FileStats[i].Count++
+		} else {
+			fileIndex[entry.File] = len(report.FileStats)
+			report.FileStats = append(report.FileStats, FileStat{Filename: entry.File, Count: 1})
+		}
+	}
+	report.Summary.TotalIssues = len(issues)
+	return report
+}
+
+// NewReportIssue converts an issue to its JSON report form
+func NewReportIssue(issue *models.Issue) ReportIssue {
+	file, line, column := issue.Position.Filename, issue.Position.Line, issue.Position.Column
+	if file == "" {
+		file = issue.File
+	}
+	if line == 0 {
+		line, column = issue.Line, issue.Column
+	}
+
+	return ReportIssue{
+		ID:         issue.ID,
+		File:       file,
+		Line:       line,
+		Column:     column,
+		EndLine:    issue.EndLine,
+		EndColumn:  issue.EndColumn,
+		Type:       RuleName(issue.Type.String()),
+		PVEID:      issue.Type.GetPVEID(),
+		Severity:   severityName(issue.Severity),
+		Message:    issue.Message,
+		Suggestion: issue.Suggestion,
+		WhyBad:     whyBad(issue),
+		Code:       issue.Code,
+		CanBeFixed: issue.CanBeFixed,
+		Fix:        issue.Fix,
+		CreatedAt:  optionalTime(issue.CreatedAt),
+		UpdatedAt:  optionalTime(issue.UpdatedAt),
+		FixedAt:    optionalTime(issue.FixedAt),
+		IgnoredAt:  optionalTime(issue.IgnoredAt),
+	}
+}
+
+// whyBad returns the explanation of issue, falling back to the description
+// of its rule
+func whyBad(issue *models.Issue) string {
+	if issue.WhyBad != "" {
+		return issue.WhyBad
+	}
+	rule, _ := issue.Type.Rule()
+	return rule.Description
+}
+
+func severityName(severity models.SeverityLevel) SeverityName {
+	return SeverityName(strings.ToUpper(severity.String()))
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
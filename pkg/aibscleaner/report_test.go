@@ -0,0 +1,185 @@
+package aibscleaner
+
+import (
+	"encoding/json"
+	"flag"
+	"go/token"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+var updateSchema = flag.Bool("update", false, "rewrite report.schema.json from the Go types")
+
+const schemaFile = "report.schema.json"
+
+// TestJSONSchemaIsCurrent fails when the report types change without the
+// published schema being regenerated with `go test ./pkg/aibscleaner -update`.
+// Removing or renaming a field also needs a new SchemaVersion.
+func TestJSONSchemaIsCurrent(t *testing.T) {
+	schema, err := JSONSchema()
+	require.NoError(t, err)
+
+	if *updateSchema {
+		require.NoError(t, os.WriteFile(schemaFile, schema, 0o644))
+	}
+	published, err := os.ReadFile(schemaFile)
+	require.NoError(t, err)
+	require.Equal(t, string(published), string(schema), "report schema drifted, run go test ./pkg/aibscleaner -update")
+}
+
+func TestNewReportJSON(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	issues := []*models.Issue{
+		{
+			Type: models.IssueDeferInLoop, Severity: models.SeverityLevelHigh, Message: "Defer in loop",
+			Position: token.Position{Filename: "a.go", Line: 3, Column: 2}, CreatedAt: created,
+		},
+		{
+			Type: models.IssueRegexCompileInLoop, Severity: models.SeverityLevelLow, Message: "Regex in loop",
+			File: "b.go", Line: 5, Column: 1,
+		},
+		{
+			Type: models.IssueDeferInLoop, Severity: models.SeverityLevelMedium, Message: "Defer in loop",
+			Position: token.Position{Filename: "a.go", Line: 9, Column: 2},
+		},
+	}
+
+	data, err := json.Marshal(NewReport(".", issues))
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	require.Equal(t, SchemaVersion, got["schema_version"])
+	require.Equal(t, map[string]any{"total_issues": 3.0, "high": 1.0, "medium": 1.0, "low": 1.0}, got["summary"])
+	require.Equal(t, []any{
+		map[string]any{"filename": "a.go", "count": 2.0},
+		map[string]any{"filename": "b.go", "count": 1.0},
+	}, got["file_stats"])
+
+	first := got["issues"].([]any)[0].(map[string]any)
+	require.Equal(t, "DeferInLoop", first["type"])
+	require.Equal(t, "HIGH", first["severity"])
+	require.Equal(t, models.IssueDeferInLoop.GetPVEID(), first["pve_id"])
+	require.Equal(t, "2024-05-01T12:00:00Z", first["created_at"])
+	require.NotContains(t, first, "fixed_at")
+	require.NotContains(t, first, "position")
+	rule, _ := models.IssueDeferInLoop.Rule()
+	require.Equal(t, rule.Description, first["why_bad"], "why_bad falls back to the rule description")
+
+	second := got["issues"].([]any)[1].(map[string]any)
+	require.Equal(t, "b.go", second["file"])
+	require.Equal(t, 5.0, second["line"])
+	require.Equal(t, "LOW", second["severity"])
+	require.NotContains(t, second, "created_at")
+}
+
+func TestNewReportWithoutIssues(t *testing.T) {
+	data, err := json.Marshal(NewReport(".", nil))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schema_version": "2",
+		"target": ".",
+		"summary": {"total_issues": 0, "high": 0, "medium": 0, "low": 0},
+		"issues": [],
+		"file_stats": []
+	}`, string(data))
+}
+
+func TestReportWithCustomRuleMatchesSchema(t *testing.T) {
+	custom, err := models.RegisterRule(models.Rule{
+		ID: "ACME-010", Name: "ReportSchemaCustomRule", Severity: models.SeverityLevelMedium,
+	})
+	require.NoError(t, err)
+
+	issues := []*models.Issue{
+		{Type: models.IssueDeferInLoop, Severity: models.SeverityLevelHigh, Message: "Defer in loop", File: "a.go", Line: 3, Column: 2},
+		{Type: custom, Severity: models.SeverityLevelMedium, Message: "Custom", File: "b.go", Line: 5, Column: 1},
+	}
+	data, err := json.Marshal(NewReport(".", issues))
+	require.NoError(t, err)
+	var report any
+	require.NoError(t, json.Unmarshal(data, &report))
+
+	published, err := os.ReadFile(schemaFile)
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(published, &schema))
+
+	require.Empty(t, validateSchema(schema, schema, report, "$"))
+	second := report.(map[string]any)["issues"].([]any)[1].(map[string]any)
+	require.Equal(t, "ReportSchemaCustomRule", second["type"])
+	require.Equal(t, "ACME-010", second["pve_id"])
+}
+
+// validateSchema checks value against the subset of JSON Schema that
+// JSONSchema emits and returns the paths that do not match
+func validateSchema(root, schema map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		return validateSchema(root, root["$defs"].(map[string]any)[name].(map[string]any), value, path)
+	}
+	if want, ok := schema["const"]; ok && value != want {
+		return []string{path + ": not " + want.(string)}
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		return []string{path + ": not in enum"}
+	}
+
+	var errs []string
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return []string{path + ": not an object"}
+		}
+		for _, name := range schema["required"].([]any) {
+			if _, ok := object[name.(string)]; !ok {
+				errs = append(errs, path+"."+name.(string)+": missing")
+			}
+		}
+		properties := schema["properties"].(map[string]any)
+		for name, field := range object {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			errs = append(errs, validateSchema(root, property, field, path+"."+name)...)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return []string{path + ": not an array"}
+		}
+		for _, item := range items {
+			errs = append(errs, validateSchema(root, schema["items"].(map[string]any), item, path+"[]")...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{path + ": not a string"}
+		}
+		if minLength, ok := schema["minLength"].(float64); ok && len(s) < int(minLength) {
+			errs = append(errs, path+": too short")
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			errs = append(errs, path+": does not match "+pattern)
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			errs = append(errs, path+": not a number")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, path+": not a boolean")
+		}
+	}
+	return errs
+}
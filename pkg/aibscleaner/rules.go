@@ -0,0 +1,63 @@
+package aibscleaner
+
+import (
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// RuleInfo is a rule as `list-rules --json` and `explain --json` print it
+// for editor integrations. Fields other than the reference, grouping and
+// description are empty for rules registered without long-form documentation.
+type RuleInfo struct {
+	ID          string       `json:"id"`
+	Name        RuleName     `json:"name"`
+	Analyzer    string       `json:"analyzer"`
+	Group       string       `json:"group"`
+	Severity    SeverityName `json:"severity"`
+	Tags        []string     `json:"tags"`
+	Description string       `json:"description"`
+	Why         string       `json:"why,omitempty"`
+	Cost        string       `json:"cost,omitempty"`
+	Bad         string       `json:"bad,omitempty"`
+	Good        string       `json:"good,omitempty"`
+	Fixable     bool         `json:"fixable"`
+	Related     []string     `json:"related"` // IDs of the related rules
+}
+
+// NewRuleInfo converts a rule to its JSON form
+func NewRuleInfo(rule models.Rule) RuleInfo {
+	related := make([]string, 0, len(rule.Related))
+	for _, t := range rule.Related {
+		related = append(related, t.GetPVEID())
+	}
+	tags := rule.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return RuleInfo{
+		ID:          rule.ID,
+		Name:        RuleName(rule.Name),
+		Analyzer:    rule.Analyzer.String(),
+		Group:       rule.Group,
+		Severity:    severityName(rule.Severity),
+		Tags:        tags,
+		Description: rule.Description,
+		Why:         rule.Why,
+		Cost:        rule.Cost,
+		Bad:         rule.Bad,
+		Good:        rule.Good,
+		Fixable:     rule.Fixable,
+		Related:     related,
+	}
+}
+
+// RuleInfos returns every built-in and registered rule in the order of
+// models.Rules
+func RuleInfos() []RuleInfo {
+	rules := models.Rules()
+	infos := make([]RuleInfo, 0, len(rules))
+	for _, rule := range rules {
+		infos = append(infos, NewRuleInfo(rule))
+	}
+	return infos
+}
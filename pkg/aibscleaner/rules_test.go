@@ -0,0 +1,42 @@
+package aibscleaner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+func TestRuleInfoJSON(t *testing.T) {
+	rule, ok := models.IssueDeferInLoop.Rule()
+	require.True(t, ok)
+
+	data, err := json.Marshal(NewRuleInfo(rule))
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	require.Equal(t, "PVE-003", decoded["id"])
+	require.Equal(t, "DeferInLoop", decoded["name"])
+	require.Equal(t, "MEDIUM", decoded["severity"])
+	require.Equal(t, models.GroupPerformance, decoded["group"])
+	require.Equal(t, true, decoded["fixable"])
+	require.NotEmpty(t, decoded["why"])
+	require.NotEmpty(t, decoded["cost"])
+	require.NotEmpty(t, decoded["bad"])
+	require.Contains(t, decoded["related"], models.IssueMemoryLeak.GetPVEID())
+}
+
+func TestRuleInfosListEveryRule(t *testing.T) {
+	infos := RuleInfos()
+	require.Len(t, infos, len(models.Rules()))
+	require.Equal(t, "PVE-000", infos[0].ID)
+
+	// Rules without tags or related rules still marshal with empty lists rather than null
+	for _, info := range infos {
+		require.NotNil(t, info.Tags, info.Name)
+		require.NotNil(t, info.Related, info.Name)
+	}
+}
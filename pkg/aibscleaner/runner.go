@@ -0,0 +1,501 @@
+// Package aibscleaner is the stable API for embedding the analyzer in other
+// programs. A Runner analyzes files on disk or in memory and returns the
+// findings without printing, exiting or writing anything.
+package aibscleaner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/analyzer"
+	"github.com/SergeiSkv/AiBsCleaner/cache"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// Cache keeps analysis results between runs. A Runner calls it from several
+// goroutines at once.
+type Cache interface {
+	// Load returns the issues stored for filename if the file has not
+	// changed since they were stored
+	Load(filename string) ([]*models.Issue, bool)
+	// Store records the issues found in filename
+	Store(filename string, issues []*models.Issue)
+	// IgnoresRule reports whether rule is suppressed for relPath, a slash
+	// separated path relative to the working directory
+	IgnoresRule(relPath, rule string) bool
+}
+
+// Options configures a Runner
+type Options struct {
+	Concurrency int           // files analyzed at once; runtime.NumCPU() when <= 0
+	FileTimeout time.Duration // time budget per file; no limit when <= 0
+
+	// Overlay replaces the contents of files on disk, keyed by file path
+	Overlay map[string][]byte
+
+	// ReportUnusedIgnores adds an issue for every ignore directive that
+	// suppressed nothing. Cached results are not used when it is set.
+	ReportUnusedIgnores bool
+
+	// Cache is consulted before analyzing a file; nil disables caching
+	Cache Cache
+
+	// OnIssues receives the issues of every file, including clean ones, as
+	// soon as the file and the files before it are done. Returning an error
+	// stops the run.
+	OnIssues func(filename string, issues []*models.Issue) error
+
+	// DiscardIssues leaves Result.Issues empty so that memory does not grow
+	// with the number of issues when they are consumed through OnIssues
+	DiscardIssues bool
+
+	// Profile fills Stats.Analyzers and Stats.SlowestFiles
+	Profile bool
+
+	// EscapeAnalysis builds the analyzed packages with the local Go toolchain
+	// to confirm heap allocations and report functions on hot paths that miss
+	// the inlining budget. Cached results are not used when it is set.
+	EscapeAnalysis bool
+}
+
+// Result is the outcome of a Runner call
+type Result struct {
+	Issues []*models.Issue
+	Files  []string // files analyzed, in the order they finished
+	Stats  Stats
+	Errors []error // files that could not be analyzed, as *FileError
+}
+
+// Stats summarizes the work done by a Runner call
+type Stats struct {
+	Files    int
+	Lines    int
+	Duration time.Duration
+
+	// Set with Options.Profile
+	Analyzers    []AnalyzerStats // slowest first
+	SlowestFiles []FileTiming    // at most SlowestFilesLimit, slowest first
+}
+
+// AnalyzerStats is the work done by one analyzer over a Runner call
+type AnalyzerStats = analyzer.AnalyzerStats
+
+// FileTiming is the wall time spent on one file
+type FileTiming struct {
+	Filename string
+	Duration time.Duration
+}
+
+// SlowestFilesLimit is the number of files kept in Stats.SlowestFiles
+const SlowestFilesLimit = 10
+
+// FileError reports a file that could not be read or parsed
+type FileError struct {
+	Filename string
+	Err      error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Filename, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Runner analyzes Go files with a fixed configuration
+type Runner struct {
+	config  *Config
+	opts    Options
+	enabled map[string]bool
+	overlay analyzer.Overlay
+	escapes *analyzer.EscapeAnalysis // nil unless Options.EscapeAnalysis
+
+	customRules []analyzer.CustomRule
+	configErr   error                     // invalid custom rules, returned by every analysis
+	checked     map[models.IssueType]bool // issue types the enabled analyzers report
+}
+
+// NewRunner returns a Runner for config, or for DefaultConfig when config is
+// nil. Its analyses fail if config has invalid custom rules.
+func NewRunner(config *Config, opts Options) *Runner {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	r := &Runner{
+		config:  config,
+		opts:    opts,
+		enabled: config.EnabledAnalyzers(),
+		overlay: absOverlay(nil, opts.Overlay),
+	}
+	r.customRules, r.configErr = config.CompileCustomRules()
+	r.checked = analyzer.CheckedRules(r.enabled, r.customRules)
+	if opts.EscapeAnalysis {
+		r.escapes = analyzer.NewEscapeAnalysis()
+	}
+	return r
+}
+
+// CheckedRules returns the issue types the enabled analyzers and custom rules
+// look for, in PVE order followed by the registered rules
+func (r *Runner) CheckedRules() []models.IssueType {
+	rules := make([]models.IssueType, 0, len(r.checked))
+	for t := range r.checked {
+		rules = append(rules, t)
+	}
+	slices.Sort(rules)
+	return rules
+}
+
+// AnalyzePaths analyzes the Go files under paths, which may be files or
+// directories, skipping the paths excluded by the configuration. Files are
+// found on disk; their contents are taken from the overlay when present.
+// Dependency issues are reported once per path.
+func (r *Runner) AnalyzePaths(ctx context.Context, paths ...string) (*Result, error) {
+	if r.configErr != nil {
+		return nil, r.configErr
+	}
+	start := time.Now()
+	result := &Result{}
+
+	var files []string
+	for _, path := range paths {
+		if r.config.Analyzers.Dependency.Enabled {
+			if err := r.collect(result, path, analyzer.AnalyzeDependencies(path)); err != nil {
+				return nil, err
+			}
+		}
+		found, err := r.collectFiles(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+
+	if err := r.run(ctx, files, r.overlay, result); err != nil {
+		return nil, err
+	}
+	result.Stats.Duration = time.Since(start)
+	return result, nil
+}
+
+// AnalyzeSources analyzes in-memory files keyed by file name. Other files of
+// their packages are read from the overlay or the disk for type information.
+func (r *Runner) AnalyzeSources(ctx context.Context, sources map[string][]byte) (*Result, error) {
+	if r.configErr != nil {
+		return nil, r.configErr
+	}
+	start := time.Now()
+	result := &Result{}
+
+	files := make([]string, 0, len(sources))
+	for filename := range sources {
+		files = append(files, filename)
+	}
+	sort.Strings(files)
+
+	if err := r.run(ctx, files, absOverlay(r.overlay, sources), result); err != nil {
+		return nil, err
+	}
+	result.Stats.Duration = time.Since(start)
+	return result, nil
+}
+
+// CollectFiles returns the Go files under paths that AnalyzePaths analyzes
+func (r *Runner) CollectFiles(ctx context.Context, paths ...string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		found, err := r.collectFiles(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+	return files, nil
+}
+
+func (r *Runner) collectFiles(ctx context.Context, root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(
+		root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			skip, skipDir := shouldSkipPath(path, info, r.config.Paths.Exclude)
+			if skipDir {
+				return filepath.SkipDir
+			}
+			if skip {
+				return nil
+			}
+
+			if isGoFile(path, info) {
+				files = append(files, path)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("scan %s: %w", root, err)
+	}
+	return files, nil
+}
+
+func (r *Runner) run(ctx context.Context, files []string, overlay analyzer.Overlay, result *Result) error {
+	var profile *analyzer.Profile
+	if r.opts.Profile {
+		profile = analyzer.NewProfile()
+	}
+
+	opts := analyzer.PipelineOptions{Concurrency: r.opts.Concurrency, FileTimeout: r.opts.FileTimeout}
+	analyze := func(ctx context.Context, filename string) analyzer.FileResult {
+		return r.analyzeFile(ctx, filename, overlay, profile)
+	}
+	err := analyzer.RunPipeline(ctx, files, analyze, opts, func(res analyzer.FileResult) error {
+		if res.Err != nil {
+			var fileErr *FileError
+			if !errors.As(res.Err, &fileErr) {
+				return fmt.Errorf("analyze %s: %w", res.Filename, res.Err)
+			}
+			result.Errors = append(result.Errors, fileErr)
+		} else {
+			result.Files = append(result.Files, res.Filename)
+		}
+		result.Stats.Files++
+		result.Stats.Lines += res.Lines
+		if profile != nil {
+			result.Stats.SlowestFiles = addSlowFile(result.Stats.SlowestFiles, FileTiming{res.Filename, res.Duration})
+		}
+		return r.collect(result, res.Filename, res.Issues)
+	})
+	if profile != nil {
+		result.Stats.Analyzers = profile.Analyzers()
+	}
+	return err
+}
+
+// addSlowFile inserts timing into slowest, which is sorted slowest first, and
+// keeps at most SlowestFilesLimit entries
+func addSlowFile(slowest []FileTiming, timing FileTiming) []FileTiming {
+	i := sort.Search(len(slowest), func(i int) bool { return slowest[i].Duration < timing.Duration })
+	if i >= SlowestFilesLimit {
+		return slowest
+	}
+	slowest = slices.Insert(slowest, i, timing)
+	if len(slowest) > SlowestFilesLimit {
+		slowest = slowest[:SlowestFilesLimit]
+	}
+	return slowest
+}
+
+// collect hands the issues of a file to OnIssues and adds them to result
+func (r *Runner) collect(result *Result, filename string, issues []*models.Issue) error {
+	if r.opts.OnIssues != nil {
+		if err := r.opts.OnIssues(filename, issues); err != nil {
+			return err
+		}
+	}
+	if !r.opts.DiscardIssues {
+		result.Issues = append(result.Issues, issues...)
+	}
+	return nil
+}
+
+func (r *Runner) analyzeFile(
+	ctx context.Context, filename string, overlay analyzer.Overlay, profile *analyzer.Profile,
+) analyzer.FileResult {
+	res := analyzer.FileResult{Filename: filename}
+
+	src, overlaid := overlay.Lookup(filename)
+	if !overlaid {
+		var err error
+		if src, err = os.ReadFile(filename); err != nil {
+			res.Err = &FileError{Filename: filename, Err: err}
+			return res
+		}
+	}
+	res.Lines = countLines(src)
+
+	// The cache tracks files on disk, so overlaid contents bypass it, and it
+	// holds results found without the compiler's decisions
+	fileCache := r.opts.Cache
+	if overlaid || r.escapes != nil {
+		fileCache = nil
+	}
+
+	// Directive usage is only known after a fresh analysis
+	if fileCache != nil && !r.opts.ReportUnusedIgnores {
+		if issues, ok := fileCache.Load(filename); ok {
+			res.Issues = r.filterIgnoredRules(filename, issues)
+			return res
+		}
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		res.Err = &FileError{Filename: filename, Err: err}
+		return res
+	}
+	issues := analyzer.AnalyzeWithOptions(filename, file, fset, analyzer.Options{
+		Analyzers:   r.enabled,
+		Context:     ctx,
+		Overlay:     overlay,
+		Profile:     profile,
+		Thresholds:  analyzer.Thresholds{MaxLoopDepth: r.config.Thresholds.MaxLoopDepth},
+		Escapes:     r.escapes,
+		CustomRules: r.customRules,
+		// Streamed results are not held in memory
+		NoMemoryCache: r.opts.OnIssues != nil || r.opts.DiscardIssues,
+	})
+	// An analysis stopped by ctx is incomplete and must not reach the cache
+	if err := ctx.Err(); err != nil {
+		res.Err = err
+		return res
+	}
+
+	// Filter out issues that have ignore comments
+	ignoreOpts := analyzer.IgnoreOptions{
+		RequireReason: r.config.Suppression.RequireReason,
+		Checked:       func(t models.IssueType) bool { return r.checked[t] },
+		Overlay:       overlay,
+	}
+	checker := analyzer.NewIgnoreCheckerWithOptions(fset, file, ignoreOpts)
+	issues = r.filterIgnoredRules(filename, checker.Filter(issues))
+
+	if fileCache != nil {
+		fileCache.Store(filename, issues)
+	}
+	if r.opts.ReportUnusedIgnores {
+		issues = append(issues, checker.StaleDirectives()...)
+	}
+	res.Issues = issues
+	return res
+}
+
+// filterIgnoredRules drops issues suppressed by path rules from the config
+// (.abcignore rule entries) or by the cache
+func (r *Runner) filterIgnoredRules(filename string, issues []*models.Issue) []*models.Issue {
+	return filterIgnoredRules(filename, issues, r.config.Paths.IgnoreRules, r.opts.Cache)
+}
+
+func filterIgnoredRules(
+	filename string, issues []*models.Issue, rules []cache.IgnoredRule, fileCache Cache,
+) []*models.Issue {
+	if len(rules) == 0 && fileCache == nil {
+		return issues
+	}
+
+	relPath := relativePath(filename)
+	filtered := issues[:0]
+	for _, issue := range issues {
+		if !isRuleIgnored(relPath, issue.Type.String(), rules, fileCache) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered
+}
+
+func isRuleIgnored(relPath, ruleType string, rules []cache.IgnoredRule, fileCache Cache) bool {
+	for _, rule := range rules {
+		if rule.Matches(relPath, ruleType) {
+			return true
+		}
+	}
+	return fileCache != nil && fileCache.IgnoresRule(relPath, ruleType)
+}
+
+// relativePath returns filename relative to the working directory in slash
+// form, matching how .abcignore patterns are written
+func relativePath(filename string) string {
+	if abs, err := filepath.Abs(filename); err == nil {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(filename)
+}
+
+// absOverlay returns base extended with files keyed by absolute path, as
+// type checking expects
+func absOverlay(base analyzer.Overlay, files map[string][]byte) analyzer.Overlay {
+	if len(files) == 0 {
+		return base
+	}
+	overlay := make(analyzer.Overlay, len(base)+len(files))
+	for filename, src := range base {
+		overlay[filename] = src
+	}
+	for filename, src := range files {
+		if abs, err := filepath.Abs(filename); err == nil {
+			filename = abs
+		}
+		overlay[filename] = src
+	}
+	return overlay
+}
+
+// shouldSkipPath checks if a path should be skipped based on exclusion rules
+func shouldSkipPath(path string, info os.FileInfo, excludes []string) (skip, skipDir bool) {
+	cleanPath := filepath.Clean(path)
+
+	for _, exclude := range excludes {
+		cleanExclude := filepath.Clean(exclude)
+
+		if strings.HasSuffix(exclude, ".go") {
+			// File pattern (e.g., "_test.go")
+			if !info.IsDir() && strings.HasSuffix(cleanPath, exclude) {
+				return true, false
+			}
+			continue
+		}
+
+		// Check if path contains or matches the exclude pattern
+		if strings.Contains(cleanPath, cleanExclude) {
+			if info.IsDir() {
+				return false, true
+			}
+			return true, false
+		}
+
+		// Check base name matching
+		if filepath.Base(cleanPath) == exclude {
+			if info.IsDir() {
+				return false, true
+			}
+			return true, false
+		}
+	}
+
+	return false, false
+}
+
+// isGoFile checks if a file is a Go source file
+func isGoFile(path string, info os.FileInfo) bool {
+	return !info.IsDir() && strings.HasSuffix(path, ".go")
+}
+
+// countLines counts the lines of src, including a last line without newline
+func countLines(src []byte) int {
+	lines := bytes.Count(src, []byte("\n"))
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		lines++
+	}
+	return lines
+}
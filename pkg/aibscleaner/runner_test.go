@@ -0,0 +1,329 @@
+package aibscleaner
+
+import (
+	"context"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/SergeiSkv/AiBsCleaner/cache"
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+const deferInLoopSource = `package main
+
+func main() {
+	for i := 0; i < 3; i++ {
+		defer println(i)
+	}
+}
+`
+
+type stubFileInfo struct {
+	name string
+	dir  bool
+}
+
+func (s stubFileInfo) Name() string       { return s.name }
+func (s stubFileInfo) Size() int64        { return 0 }
+func (s stubFileInfo) Mode() os.FileMode  { return 0 }
+func (s stubFileInfo) ModTime() time.Time { return time.Time{} }
+func (s stubFileInfo) IsDir() bool        { return s.dir }
+func (s stubFileInfo) Sys() any           { return nil }
+
+type stubCache struct {
+	mu      sync.Mutex
+	cached  map[string][]*models.Issue
+	stored  map[string][]*models.Issue
+	ignored string
+}
+
+func (c *stubCache) Load(filename string) ([]*models.Issue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	issues, ok := c.cached[filename]
+	return issues, ok
+}
+
+func (c *stubCache) Store(filename string, issues []*models.Issue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stored[filename] = issues
+}
+
+func (c *stubCache) IgnoresRule(_, rule string) bool {
+	return rule == c.ignored
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o600))
+	}
+}
+
+func hasIssue(issues []*models.Issue, filename string, issueType models.IssueType) bool {
+	for _, issue := range issues {
+		if issue.Position.Filename == filename && issue.Type == issueType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRunnerAnalyzePaths(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":          "module example.com/demo\n\ngo 1.21\n",
+		"main.go":         deferInLoopSource,
+		"broken.go":       "package main\n\nfunc broken( {\n",
+		"vendor/x/x.go":   deferInLoopSource,
+		"main_test.go":    deferInLoopSource,
+		"notes/README.md": "not go\n",
+	})
+
+	result, err := NewRunner(nil, Options{Concurrency: 2}).AnalyzePaths(context.Background(), dir)
+	require.NoError(t, err)
+
+	mainFile := filepath.Join(dir, "main.go")
+	require.True(t, hasIssue(result.Issues, mainFile, models.IssueDeferInLoop))
+	for _, issue := range result.Issues {
+		require.NotContains(t, issue.Position.Filename, "vendor")
+		require.NotEqual(t, filepath.Join(dir, "main_test.go"), issue.Position.Filename)
+	}
+
+	require.Len(t, result.Errors, 1)
+	var fileErr *FileError
+	require.ErrorAs(t, result.Errors[0], &fileErr)
+	require.Equal(t, filepath.Join(dir, "broken.go"), fileErr.Filename)
+
+	require.Equal(t, 2, result.Stats.Files)
+	require.Equal(t, 10, result.Stats.Lines)
+	require.Equal(t, []string{mainFile}, result.Files)
+}
+
+func TestRunnerCheckedRules(t *testing.T) {
+	rules := NewRunner(nil, Options{}).CheckedRules()
+	require.True(t, slices.IsSorted(rules))
+	require.Contains(t, rules, models.IssueDeferInLoop)
+	// testcoverage is off in the default configuration
+	for _, rule := range models.RulesOf(models.AnalyzerTestCoverage) {
+		require.NotContains(t, rules, rule)
+	}
+}
+
+func TestRunnerAnalyzeSources(t *testing.T) {
+	// The file only exists in memory
+	filename := filepath.Join(t.TempDir(), "main.go")
+	sources := map[string][]byte{filename: []byte(deferInLoopSource)}
+
+	result, err := NewRunner(nil, Options{}).AnalyzeSources(context.Background(), sources)
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	require.Equal(t, 1, result.Stats.Files)
+
+	var found *models.Issue
+	for _, issue := range result.Issues {
+		if issue.Type == models.IssueDeferInLoop {
+			found = issue
+		}
+	}
+	require.NotNil(t, found)
+	require.Equal(t, filename, found.Position.Filename)
+	require.Equal(t, "defer println(i)", found.Code)
+}
+
+func TestRunnerKeepsDirectivesOfDisabledAnalyzers(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "main.go")
+	src := "package main\n\n// abc:ignore DeferInLoop -- closed by the caller\nfunc f() {}\n\n// abc:ignore NestedLoop\nfunc g() {}\n"
+	config := DefaultConfig()
+	config.Analyzers.DeferOptimization.Enabled = false
+	config.Analyzers.APIMisuse.Enabled = false
+
+	for _, loop := range []bool{true, false} {
+		config.Analyzers.Loop.Enabled = loop
+		runner := NewRunner(config, Options{ReportUnusedIgnores: true})
+		result, err := runner.AnalyzeSources(context.Background(), map[string][]byte{filename: []byte(src)})
+		require.NoError(t, err)
+
+		var stale []int
+		for _, issue := range result.Issues {
+			if issue.Type == models.IssueStaleIgnoreDirective {
+				stale = append(stale, issue.Line)
+			}
+		}
+		if loop {
+			require.Equal(t, []int{3, 6}, stale)
+		} else {
+			require.Empty(t, stale, "the loop analyzer did not run, so its directives may still be needed")
+		}
+	}
+}
+
+func TestRunnerStreamsIssues(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.go": deferInLoopSource, "b.go": "package main\n", "c.go": deferInLoopSource})
+
+	var files []string
+	streamed := 0
+	opts := Options{
+		Concurrency: 3,
+		OnIssues: func(filename string, issues []*models.Issue) error {
+			files = append(files, filepath.Base(filename))
+			streamed += len(issues)
+			return nil
+		},
+		DiscardIssues: true,
+	}
+	config := DefaultConfig()
+	config.Analyzers.Dependency.Enabled = false
+	result, err := NewRunner(config, opts).AnalyzePaths(context.Background(), dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.go", "b.go", "c.go"}, files)
+	require.Positive(t, streamed)
+	require.Empty(t, result.Issues)
+	require.Equal(t, 3, result.Stats.Files)
+
+	opts.OnIssues = func(string, []*models.Issue) error { return os.ErrClosed }
+	_, err = NewRunner(config, opts).AnalyzePaths(context.Background(), dir)
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestRunnerProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.go": deferInLoopSource, "b.go": "package main\n"})
+
+	config := DefaultConfig()
+	config.Analyzers.Dependency.Enabled = false
+	result, err := NewRunner(config, Options{Profile: true}).AnalyzePaths(context.Background(), dir)
+	require.NoError(t, err)
+
+	require.Len(t, result.Stats.SlowestFiles, 2)
+	require.GreaterOrEqual(t, result.Stats.SlowestFiles[0].Duration, result.Stats.SlowestFiles[1].Duration)
+
+	var loop *AnalyzerStats
+	for i, stats := range result.Stats.Analyzers {
+		if stats.Name == "loop" {
+			loop = &result.Stats.Analyzers[i]
+		}
+	}
+	require.NotNil(t, loop)
+	require.Equal(t, 2, loop.Files)
+	require.Positive(t, loop.Issues)
+
+	result, err = NewRunner(config, Options{}).AnalyzePaths(context.Background(), dir)
+	require.NoError(t, err)
+	require.Empty(t, result.Stats.Analyzers)
+	require.Empty(t, result.Stats.SlowestFiles)
+}
+
+func TestAddSlowFile(t *testing.T) {
+	var slowest []FileTiming
+	for i := range SlowestFilesLimit + 5 {
+		slowest = addSlowFile(slowest, FileTiming{Filename: strconv.Itoa(i), Duration: time.Duration(i)})
+	}
+	require.Len(t, slowest, SlowestFilesLimit)
+	require.Equal(t, strconv.Itoa(SlowestFilesLimit+4), slowest[0].Filename)
+	require.Equal(t, time.Duration(5), slowest[SlowestFilesLimit-1].Duration)
+}
+
+func TestRunnerUsesCache(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"main.go": deferInLoopSource, "cached.go": "package main\n"})
+	cachedFile := filepath.Join(dir, "cached.go")
+	mainFile := filepath.Join(dir, "main.go")
+
+	fileCache := &stubCache{
+		cached: map[string][]*models.Issue{
+			cachedFile: {{Type: models.IssueStringConcat, Position: token.Position{Filename: cachedFile, Line: 1}}},
+		},
+		stored:  make(map[string][]*models.Issue),
+		ignored: models.IssueStringConcat.String(),
+	}
+	cfg := DefaultConfig()
+	cfg.Analyzers.Dependency.Enabled = false
+
+	result, err := NewRunner(cfg, Options{Cache: fileCache}).AnalyzePaths(context.Background(), dir)
+	require.NoError(t, err)
+	require.True(t, hasIssue(result.Issues, mainFile, models.IssueDeferInLoop))
+	require.False(t, hasIssue(result.Issues, cachedFile, models.IssueStringConcat), "ignored rule should be dropped")
+	require.Contains(t, fileCache.stored, mainFile)
+	require.NotContains(t, fileCache.stored, cachedFile)
+}
+
+func TestFilterIgnoredRules(t *testing.T) {
+	rules := []cache.IgnoredRule{
+		{FilePattern: "*.pb.go", RuleTypes: []string{"AIGeneratedComment"}},
+		{FilePattern: "internal/legacy/**", RuleTypes: []string{models.IssueRaceCondition.GetPVEID()}},
+	}
+	issues := []*models.Issue{
+		{Type: models.IssueAIGeneratedComment},
+		{Type: models.IssueRaceCondition},
+	}
+
+	kept := filterIgnoredRules(filepath.Join("api", "service.pb.go"), append([]*models.Issue(nil), issues...), rules, nil)
+	require.Len(t, kept, 1)
+	require.Equal(t, models.IssueRaceCondition, kept[0].Type)
+
+	kept = filterIgnoredRules(filepath.Join("internal", "legacy", "old.go"), append([]*models.Issue(nil), issues...), rules, nil)
+	require.Len(t, kept, 1)
+	require.Equal(t, models.IssueAIGeneratedComment, kept[0].Type)
+}
+
+func TestShouldSkipPath(t *testing.T) {
+	excludes := []string{"vendor", "_test.go"}
+
+	skip, skipDir := shouldSkipPath(filepath.Join("project", "vendor"), stubFileInfo{dir: true}, excludes)
+	require.False(t, skip)
+	require.True(t, skipDir, "expected to skip directory traversal for vendor")
+
+	skip, skipDir = shouldSkipPath(filepath.Join("project", "handler_test.go"), stubFileInfo{name: "handler_test.go"}, excludes)
+	require.True(t, skip, "expected to skip test file")
+	require.False(t, skipDir)
+
+	skip, skipDir = shouldSkipPath(filepath.Join("project", "main.go"), stubFileInfo{name: "main.go"}, excludes)
+	require.False(t, skip)
+	require.False(t, skipDir)
+}
+
+func TestIsGoFile(t *testing.T) {
+	require.True(t, isGoFile("main.go", stubFileInfo{name: "main.go"}))
+	require.False(t, isGoFile("data.txt", stubFileInfo{name: "data.txt"}))
+}
+
+func TestCountLinesHandlesMissingNewline(t *testing.T) {
+	require.Equal(t, 2, countLines([]byte("line1\nline2")))
+	require.Equal(t, 2, countLines([]byte("line1\nline2\n")))
+	require.Equal(t, 0, countLines(nil))
+}
+
+func TestRunnerOverlayReachesTypeChecking(t *testing.T) {
+	t.Setenv("GOARCH", "amd64")
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/demo\n\ngo 1.21\n",
+		"main.go": "package main\n\nfunc main() {}\n",
+	})
+	mainFile := filepath.Join(dir, "main.go")
+
+	// Only the overlaid content has a padded struct
+	overlay := map[string][]byte{
+		mainFile: []byte("package main\n\ntype Pair struct {\n\ta bool\n\tb int64\n\tc bool\n}\n\nfunc main() { _ = Pair{} }\n"),
+	}
+	cfg := DefaultConfig()
+	cfg.Analyzers.Dependency.Enabled = false
+
+	result, err := NewRunner(cfg, Options{Overlay: overlay}).AnalyzePaths(context.Background(), dir)
+	require.NoError(t, err)
+	require.True(t, hasIssue(result.Issues, mainFile, models.IssueStructLayoutUnoptimized))
+	require.Equal(t, 9, result.Stats.Lines)
+}
@@ -0,0 +1,124 @@
+package aibscleaner
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SergeiSkv/AiBsCleaner/models"
+)
+
+// SchemaID identifies the JSON Schema of the current report version
+const SchemaID = "https://github.com/SergeiSkv/AiBsCleaner/schema/report-v" + SchemaVersion + ".json"
+
+var (
+	timeType         = reflect.TypeFor[time.Time]()
+	severityNameType = reflect.TypeFor[SeverityName]()
+)
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of Report. It is built
+// from the Go types, so the schema cannot drift from what is written.
+func JSONSchema() ([]byte, error) {
+	defs := make(map[string]any)
+	root := schemaObject(reflect.TypeFor[Report](), defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaID
+	root["title"] = "AiBsCleaner report"
+	root["$defs"] = defs
+	root["properties"].(map[string]any)["schema_version"] = map[string]any{"const": SchemaVersion}
+	// Custom and plugin rules bring their own names and IDs, so neither is
+	// limited to the built-in rules
+	issueProperties := defs["ReportIssue"].(map[string]any)["properties"].(map[string]any)
+	issueProperties["type"] = map[string]any{
+		"type": "string", "minLength": 1,
+		"description": "Rule name, e.g. DeferInLoop, or the name of a custom or plugin rule",
+	}
+	issueProperties["pve_id"] = map[string]any{
+		"type": "string", "minLength": 1,
+		"description": "Rule ID: PVE-NNN for built-in rules, the configured ID such as ACME-010 for custom and plugin rules",
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// schemaFor returns the schema of a value of type t. Structs other than the
+// root go to defs and are referenced by name.
+func schemaFor(t reflect.Type, defs map[string]any) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case severityNameType:
+		return map[string]any{"type": "string", "enum": severityNames()}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem(), defs)
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = nil // Reserve the name for recursive types
+			defs[t.Name()] = schemaObject(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+// schemaObject describes a struct by its JSON tags; fields without omitempty
+// are required
+func schemaObject(t reflect.Type, defs map[string]any) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, defs)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+func severityNames() []string {
+	values := models.SeverityLevelValues()
+	names := make([]string, 0, len(values))
+	for _, severity := range values {
+		names = append(names, string(severityName(severity)))
+	}
+	return names
+}